@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"fmt"
+	"image"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// AnimationMode describes what happens when the animation reaches its last frame.
+type AnimationMode int
+
+const (
+	// AnimationLoop restarts the animation from the first frame.
+	AnimationLoop AnimationMode = iota
+
+	// AnimationOnce stops the animation at the last frame.
+	AnimationOnce
+
+	// AnimationPingPong plays the animation backwards, then forwards again, etc.
+	AnimationPingPong
+)
+
+// Animation is a minimal frame animation player.
+//
+// It can be created from a sprite sheet Image (see NewAnimation)
+// or from the TileSet tile animation (see NewTileAnimation).
+// Call Update every tick and draw the CurrentFrame.
+type Animation struct {
+	Mode AnimationMode
+
+	frames    []*ebiten.Image
+	durations []time.Duration
+
+	frame    int
+	elapsed  time.Duration
+	backward bool
+	finished bool
+}
+
+// NewAnimation creates an animation player for the sprite sheet frames.
+// The frames are the image DefaultFrameWidth*DefaultFrameHeight cells
+// in the row-major order; every frame is shown for the frameDuration.
+func NewAnimation(img Image, frameDuration time.Duration) *Animation {
+	fw, fh := img.DefaultFrameWidth, img.DefaultFrameHeight
+	if fw <= 0 || fh <= 0 {
+		panic(fmt.Sprintf("image with id=%d has no frame size", img.ID))
+	}
+	w, h := img.Data.Size()
+	var frames []*ebiten.Image
+	for y := 0; y+fh <= h; y += fh {
+		for x := 0; x+fw <= w; x += fw {
+			frames = append(frames, img.Data.SubImage(image.Rect(x, y, x+fw, y+fh)).(*ebiten.Image))
+		}
+	}
+	durations := make([]time.Duration, len(frames))
+	for i := range durations {
+		durations[i] = frameDuration
+	}
+	return newAnimation(frames, durations)
+}
+
+// NewTileAnimation creates an animation player for the animated tile.
+// The img is the tile set texture (see TileSet.Image).
+func NewTileAnimation(img *ebiten.Image, ts TileSet, tile Tile) *Animation {
+	frames := make([]*ebiten.Image, len(tile.Animation))
+	durations := make([]time.Duration, len(tile.Animation))
+	for i, f := range tile.Animation {
+		frames[i] = img.SubImage(ts.TileRect(f.TileIndex)).(*ebiten.Image)
+		durations[i] = f.Duration
+	}
+	return newAnimation(frames, durations)
+}
+
+func newAnimation(frames []*ebiten.Image, durations []time.Duration) *Animation {
+	if len(frames) == 0 {
+		panic("creating an animation without frames")
+	}
+	return &Animation{frames: frames, durations: durations}
+}
+
+// NumFrames returns the number of animation frames.
+func (a *Animation) NumFrames() int { return len(a.frames) }
+
+// FrameIndex returns the current frame index.
+func (a *Animation) FrameIndex() int { return a.frame }
+
+// CurrentFrame returns the image of the current frame.
+func (a *Animation) CurrentFrame() *ebiten.Image { return a.frames[a.frame] }
+
+// IsFinished reports whether the AnimationOnce animation has reached its end.
+// It's always false for the other modes.
+func (a *Animation) IsFinished() bool { return a.finished }
+
+// Rewind resets the animation to its first frame.
+func (a *Animation) Rewind() {
+	a.frame = 0
+	a.elapsed = 0
+	a.backward = false
+	a.finished = false
+}
+
+// Update advances the animation by dt.
+// Several frames can be skipped if dt is long enough.
+func (a *Animation) Update(dt time.Duration) {
+	if a.finished {
+		return
+	}
+	a.elapsed += dt
+	for !a.finished && a.durations[a.frame] > 0 && a.elapsed >= a.durations[a.frame] {
+		a.elapsed -= a.durations[a.frame]
+		a.nextFrame()
+	}
+}
+
+func (a *Animation) nextFrame() {
+	last := len(a.frames) - 1
+	switch a.Mode {
+	case AnimationOnce:
+		if a.frame == last {
+			a.finished = true
+			a.elapsed = 0
+			return
+		}
+		a.frame++
+	case AnimationPingPong:
+		if last == 0 {
+			return
+		}
+		if a.backward && a.frame == 0 || !a.backward && a.frame == last {
+			a.backward = !a.backward
+		}
+		if a.backward {
+			a.frame--
+		} else {
+			a.frame++
+		}
+	default:
+		a.frame = (a.frame + 1) % len(a.frames)
+	}
+}
@@ -0,0 +1,125 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Beatmap is a timing data attached to the audio track.
+// It's loaded from the AudioInfo.BeatmapPath sidecar file.
+//
+// The sidecar is a JSON document like this:
+//
+//	{
+//	  "bpm": 120,
+//	  "units": "seconds",
+//	  "beats": [0.5, 1.0, 1.5],
+//	  "onsets": [0.52, 0.77]
+//	}
+//
+// The units can be either "seconds" (the default) or "samples".
+// The samples are converted using the "sample_rate" value;
+// if it's omitted, the track stream sample rate is used
+// (the same one that is used for the Audio.Duration).
+type Beatmap struct {
+	// BPM is an optional track tempo.
+	BPM float64
+
+	// Beats are the beat positions in ascending order.
+	Beats []time.Duration
+
+	// Onsets are the note onset positions in ascending order.
+	Onsets []time.Duration
+}
+
+// NextBeat returns the index of the first beat at or after t.
+// It returns len(Beats) if there are no such beats.
+func (b *Beatmap) NextBeat(t time.Duration) int {
+	return sort.Search(len(b.Beats), func(i int) bool {
+		return b.Beats[i] >= t
+	})
+}
+
+// NextOnset returns the index of the first onset at or after t.
+// It returns len(Onsets) if there are no such onsets.
+func (b *Beatmap) NextOnset(t time.Duration) int {
+	return sort.Search(len(b.Onsets), func(i int) bool {
+		return b.Onsets[i] >= t
+	})
+}
+
+func decodeBeatmap(r io.Reader, defaultSampleRate int) (*Beatmap, error) {
+	var doc struct {
+		BPM        float64   `json:"bpm"`
+		Units      string    `json:"units"`
+		SampleRate int       `json:"sample_rate"`
+		Beats      []float64 `json:"beats"`
+		Onsets     []float64 `json:"onsets"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var unit float64
+	switch doc.Units {
+	case "", "seconds":
+		unit = float64(time.Second)
+	case "samples":
+		sampleRate := doc.SampleRate
+		if sampleRate == 0 {
+			sampleRate = defaultSampleRate
+		}
+		if sampleRate <= 0 {
+			return nil, errors.New("samples units require a sample_rate")
+		}
+		unit = float64(time.Second) / float64(sampleRate)
+	default:
+		return nil, fmt.Errorf("unexpected units %q", doc.Units)
+	}
+
+	convert := func(key string, values []float64) ([]time.Duration, error) {
+		result := make([]time.Duration, len(values))
+		for i, v := range values {
+			if v < 0 {
+				return nil, fmt.Errorf("%s[%d]: negative position", key, i)
+			}
+			result[i] = time.Duration(v * unit)
+			if i > 0 && result[i] < result[i-1] {
+				return nil, fmt.Errorf("%s[%d]: positions are not sorted", key, i)
+			}
+		}
+		return result, nil
+	}
+	beats, err := convert("beats", doc.Beats)
+	if err != nil {
+		return nil, err
+	}
+	onsets, err := convert("onsets", doc.Onsets)
+	if err != nil {
+		return nil, err
+	}
+	return &Beatmap{BPM: doc.BPM, Beats: beats, Onsets: onsets}, nil
+}
+
+// loadBeatmap reads the track beatmap.
+// The sampleRate is the track stream sample rate.
+func (l *Loader) loadBeatmap(id AudioID, info AudioInfo, sampleRate int) *Beatmap {
+	if info.BeatmapPath == "" {
+		return nil
+	}
+	r := l.openResource(id.Ref(), info.BeatmapPath, nil)
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(newError("read", id.Ref(), info.BeatmapPath, err))
+		}
+	}()
+	b, err := decodeBeatmap(r, sampleRate)
+	if err != nil {
+		panic(l.decodeError("decode", id.Ref(), info.BeatmapPath, nil, err))
+	}
+	return b
+}
@@ -0,0 +1,116 @@
+package resource
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeBeatmap(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name       string
+		data       string
+		sampleRate int
+		want       Beatmap
+	}{
+		{
+			name: "empty",
+			data: `{}`,
+			want: Beatmap{Beats: []time.Duration{}, Onsets: []time.Duration{}},
+		},
+		{
+			name: "seconds",
+			data: `{"bpm": 120, "beats": [0.5, 1, 1.5], "onsets": [0.25]}`,
+			want: Beatmap{BPM: 120, Beats: []time.Duration{500 * ms, 1000 * ms, 1500 * ms}, Onsets: []time.Duration{250 * ms}},
+		},
+		{
+			name: "explicit seconds",
+			data: `{"units": "seconds", "beats": [2]}`,
+			want: Beatmap{Beats: []time.Duration{2 * time.Second}, Onsets: []time.Duration{}},
+		},
+		{
+			name:       "samples with stream rate",
+			data:       `{"units": "samples", "beats": [22050, 44100]}`,
+			sampleRate: 44100,
+			want:       Beatmap{Beats: []time.Duration{500 * ms, 1000 * ms}, Onsets: []time.Duration{}},
+		},
+		{
+			name:       "samples with explicit rate",
+			data:       `{"units": "samples", "sample_rate": 1000, "onsets": [250]}`,
+			sampleRate: 44100,
+			want:       Beatmap{Beats: []time.Duration{}, Onsets: []time.Duration{250 * ms}},
+		},
+		{
+			name: "equal positions",
+			data: `{"beats": [1, 1]}`,
+			want: Beatmap{Beats: []time.Duration{time.Second, time.Second}, Onsets: []time.Duration{}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			have, err := decodeBeatmap(strings.NewReader(test.data), test.sampleRate)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*have, test.want) {
+				t.Fatalf("result mismatch:\nhave: %+v\nwant: %+v", *have, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeBeatmapErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		sampleRate int
+		want       string
+	}{
+		{"syntax", `{`, 0, "unexpected EOF"},
+		{"units", `{"units": "beats"}`, 0, `unexpected units "beats"`},
+		{"no sample rate", `{"units": "samples", "beats": [1]}`, 0, "samples units require a sample_rate"},
+		{"negative", `{"beats": [-1]}`, 0, "beats[0]: negative position"},
+		{"unsorted", `{"onsets": [2, 1]}`, 0, "onsets[1]: positions are not sorted"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeBeatmap(strings.NewReader(test.data), test.sampleRate)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if err.Error() != test.want {
+				t.Fatalf("error mismatch:\nhave: %q\nwant: %q", err.Error(), test.want)
+			}
+		})
+	}
+}
+
+type sampleRateStream struct {
+	*bytes.Reader
+	sampleRate int
+}
+
+func (s sampleRateStream) Length() int64   { return s.Size() }
+func (s sampleRateStream) SampleRate() int { return s.sampleRate }
+
+func TestBeatmapStreamSampleRate(t *testing.T) {
+	l := NewLoader(nil)
+	l.OpenAssetFunc = func(path string) io.ReadCloser {
+		return io.NopCloser(strings.NewReader(`{"units": "samples", "beats": [11025]}`))
+	}
+	// One second of 16-bit stereo audio at 22050Hz.
+	stream := sampleRateStream{Reader: bytes.NewReader(make([]byte, 22050*4)), sampleRate: 22050}
+	a := l.createAudioObject(nil, 1, AudioInfo{BeatmapPath: "track.beats"}, stream)
+	if a.Duration != time.Second {
+		t.Fatalf("duration mismatch: have %v, want 1s", a.Duration)
+	}
+	if want := []time.Duration{500 * time.Millisecond}; !reflect.DeepEqual(a.Beatmap.Beats, want) {
+		t.Fatalf("beats mismatch: have %v, want %v", a.Beatmap.Beats, want)
+	}
+}
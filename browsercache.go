@@ -0,0 +1,22 @@
+package resource
+
+import "io"
+
+// NewBrowserCacheOpener returns a function that can be used
+// as the Loader.OpenAssetErrFunc in the browsers (js/wasm).
+//
+// It downloads the assets from baseURL+path and persists them
+// in the browser Cache Storage, so the repeated visits don't download
+// the multi-megabyte music tracks again.
+// If the Cache Storage is not available (it requires https),
+// the assets are downloaded every time.
+//
+// The cached assets are never revalidated: put the game version
+// into the cacheName to make the new game builds use a fresh cache.
+//
+//	l.OpenAssetErrFunc = resource.NewBrowserCacheOpener("mygame-v1.4.0", "assets/")
+//
+// On the other platforms, the returned function always fails.
+func NewBrowserCacheOpener(cacheName, baseURL string) func(path string) (io.ReadCloser, error) {
+	return newBrowserCacheOpener(cacheName, baseURL)
+}
@@ -0,0 +1,14 @@
+//go:build !js
+
+package resource
+
+import (
+	"errors"
+	"io"
+)
+
+func newBrowserCacheOpener(cacheName, baseURL string) func(path string) (io.ReadCloser, error) {
+	return func(path string) (io.ReadCloser, error) {
+		return nil, errors.New("browser cache is only available on js/wasm")
+	}
+}
@@ -0,0 +1,101 @@
+//go:build js
+
+package resource
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"syscall/js"
+)
+
+func newBrowserCacheOpener(cacheName, baseURL string) func(path string) (io.ReadCloser, error) {
+	return func(path string) (io.ReadCloser, error) {
+		data, err := fetchCached(cacheName, baseURL+path)
+		if err != nil {
+			return nil, fmt.Errorf("fetch %q: %w", path, err)
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+}
+
+func fetchCached(cacheName, url string) ([]byte, error) {
+	caches := js.Global().Get("caches")
+	if caches.IsUndefined() {
+		// Cache Storage is only available in the secure contexts (https).
+		resp, err := fetchURL(url)
+		if err != nil {
+			return nil, err
+		}
+		return readResponse(resp)
+	}
+
+	cache, err := awaitPromise(caches.Call("open", cacheName))
+	if err != nil {
+		return nil, err
+	}
+	resp, err := awaitPromise(cache.Call("match", url))
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsUndefined() {
+		resp, err = fetchURL(url)
+		if err != nil {
+			return nil, err
+		}
+		// The caching is a best-effort: the quota could be exceeded.
+		_, _ = awaitPromise(cache.Call("put", url, resp.Call("clone")))
+	}
+	return readResponse(resp)
+}
+
+func fetchURL(url string) (js.Value, error) {
+	resp, err := awaitPromise(js.Global().Call("fetch", url))
+	if err != nil {
+		return js.Value{}, err
+	}
+	if !resp.Get("ok").Bool() {
+		return js.Value{}, fmt.Errorf("status %d %s", resp.Get("status").Int(), resp.Get("statusText").String())
+	}
+	return resp, nil
+}
+
+func readResponse(resp js.Value) ([]byte, error) {
+	buf, err := awaitPromise(resp.Call("arrayBuffer"))
+	if err != nil {
+		return nil, err
+	}
+	array := js.Global().Get("Uint8Array").New(buf)
+	data := make([]byte, array.Get("length").Int())
+	js.CopyBytesToGo(data, array)
+	return data, nil
+}
+
+// awaitPromise blocks until the promise is settled.
+// It must not be called from the JS callbacks.
+func awaitPromise(p js.Value) (js.Value, error) {
+	done := make(chan struct{})
+	var result js.Value
+	var err error
+	onResolve := js.FuncOf(func(this js.Value, args []js.Value) any {
+		if len(args) != 0 {
+			result = args[0]
+		}
+		close(done)
+		return nil
+	})
+	defer onResolve.Release()
+	onReject := js.FuncOf(func(this js.Value, args []js.Value) any {
+		err = errors.New("promise rejected")
+		if len(args) != 0 {
+			err = errors.New(args[0].Call("toString").String())
+		}
+		close(done)
+		return nil
+	})
+	defer onReject.Release()
+	p.Call("then", onResolve, onReject)
+	<-done
+	return result, err
+}
@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"fmt"
+	"image"
+)
+
+// Channel is an RGBA color channel index.
+type Channel int
+
+const (
+	ChannelR Channel = iota
+	ChannelG
+	ChannelB
+	ChannelA
+)
+
+// LoadImageChannel returns a single channel of the Image resource
+// associated with a given key as a separate opaque grayscale image.
+// The channel values are stored in the R, G and B components.
+//
+// This makes it possible to keep several single-purpose maps in one file,
+// like using the sprite alpha as a lighting mask texture.
+// The channel values are taken from the straight (non-premultiplied) colors.
+//
+// Every unique (id, channel) pair is decoded only once and cached.
+// Unloading the original image unloads the variant too.
+func (l *Loader) LoadImageChannel(id ImageID, ch Channel) Image {
+	if ch < ChannelR || ch > ChannelA {
+		panic(fmt.Sprintf("invalid image channel %d", ch))
+	}
+	id = l.ImageRegistry.resolve(id)
+	key := imageVariantKey{id: id, variant: ch}
+	img, ok := l.imageVariant(key)
+	if !ok {
+		imageInfo, ok := l.ImageRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		src := toNRGBA(processImage(l.decodeImage(id, imageInfo), imageInfo))
+		dst := image.NewNRGBA(src.Rect)
+		for i := 0; i < len(src.Pix); i += 4 {
+			v := src.Pix[i+int(ch)]
+			dst.Pix[i+0] = v
+			dst.Pix[i+1] = v
+			dst.Pix[i+2] = v
+			dst.Pix[i+3] = 0xff
+		}
+		img = Image{
+			ID:                 id,
+			Data:               newEbitenImage(dst, imageInfo),
+			DefaultFrameWidth:  imageInfo.FrameWidth,
+			DefaultFrameHeight: imageInfo.FrameHeight,
+		}
+		l.storeImageVariant(key, img)
+	}
+	return img
+}
+
+// packChannels creates an image from the grayscale images
+// bound to the ImageInfo.PackChannels.
+func (l *Loader) packChannels(id ImageID, imageInfo ImageInfo) image.Image {
+	l.packStack = append(l.packStack, id.Ref())
+	defer func() {
+		l.packStack = l.packStack[:len(l.packStack)-1]
+	}()
+	var dst *image.NRGBA
+	for ch, srcID := range imageInfo.PackChannels {
+		if srcID == 0 {
+			continue
+		}
+		srcID = l.ImageRegistry.resolve(srcID)
+		l.checkLoadCycle(l.packStack, srcID.Ref(), "channel packing")
+		srcInfo, ok := l.ImageRegistry.mapping[srcID]
+		if !ok {
+			panic(l.notRegisteredError(srcID.Ref()))
+		}
+		src := toNRGBA(processImage(l.decodeImage(srcID, srcInfo), srcInfo))
+		if dst == nil {
+			dst = image.NewNRGBA(src.Rect)
+			// The missing color channels are zero, the missing alpha is opaque.
+			for i := 3; i < len(dst.Pix); i += 4 {
+				dst.Pix[i] = 0xff
+			}
+		}
+		if src.Rect != dst.Rect {
+			err := fmt.Errorf("channel %d image size %v doesn't match %v", ch, src.Rect.Size(), dst.Rect.Size())
+			panic(newError("decode", id.Ref(), imageInfo.Path, err))
+		}
+		// The grayscale value is taken from the red component.
+		for i := 0; i < len(src.Pix); i += 4 {
+			dst.Pix[i+ch] = src.Pix[i]
+		}
+	}
+	return dst
+}
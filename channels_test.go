@@ -0,0 +1,42 @@
+package resource
+
+import "testing"
+
+func TestPackChannelsCycle(t *testing.T) {
+	tests := []struct {
+		name  string
+		packs map[ImageID]ImageID
+		want  string
+	}{
+		{
+			name:  "self",
+			packs: map[ImageID]ImageID{1: 1},
+			want:  `load "packed.png" image: channel packing cycle: image with id=1 -> image with id=1`,
+		},
+		{
+			name:  "pair",
+			packs: map[ImageID]ImageID{1: 2, 2: 1},
+			want:  `load "packed.png" image: channel packing cycle: image with id=1 -> image with id=2 -> image with id=1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			for id, src := range test.packs {
+				info := ImageInfo{Path: "packed.png"}
+				info.PackChannels[ChannelR] = src
+				l.ImageRegistry.Set(id, info)
+			}
+			have := recoverString(func() {
+				l.decodeImage(1, l.ImageRegistry.mapping[1])
+			})
+			if have != test.want {
+				t.Fatalf("panic mismatch:\nhave: %q\nwant: %q", have, test.want)
+			}
+			if len(l.packStack) != 0 {
+				t.Fatalf("pack stack is not empty after a panic: %v", l.packStack)
+			}
+		})
+	}
+}
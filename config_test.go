@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	type settings struct {
+		Volume int `json:"volume"`
+	}
+
+	// A toy "key=value" decoder that stands in for yaml.Unmarshal and the likes.
+	decodeKV := func(data []byte, v any) error {
+		s := v.(*settings)
+		k, val, ok := strings.Cut(strings.TrimSpace(string(data)), "=")
+		if !ok || k != "volume" {
+			return errors.New("bad kv")
+		}
+		s.Volume = len(val)
+		return nil
+	}
+
+	tests := []struct {
+		path string
+		data string
+		want int
+		err  string
+	}{
+		{path: "a.json", data: `{"volume": 5}`, want: 5},
+		{path: "a.JSON", data: `{"volume": 7}`, want: 7},
+		{path: "a.kv", data: "volume=xxx", want: 3},
+		{path: "a.kv", data: "pitch=1", err: "bad kv"},
+		{path: "a.yaml", data: "volume: 1", err: `no decoder for ".yaml" extension`},
+		{path: "a.json", data: `{"volume": "x"}`, err: "cannot unmarshal"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			l := NewLoader(nil)
+			l.ConfigDecoders[".kv"] = decodeKV
+			l.ConfigRegistry.Set(1, ConfigInfo{Path: test.path, Data: []byte(test.data)})
+			var have settings
+			errText := recoverString(func() { have = DecodeConfig[settings](l, 1) })
+			if test.err != "" {
+				if !strings.Contains(errText, test.err) {
+					t.Fatalf("error mismatch:\nhave: %q\nwant: %q", errText, test.err)
+				}
+				return
+			}
+			if errText != "" {
+				t.Fatalf("unexpected error: %s", errText)
+			}
+			if have.Volume != test.want {
+				t.Fatalf("volume mismatch: have %d, want %d", have.Volume, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeConfigLoadedPath(t *testing.T) {
+	l := NewLoader(nil)
+	calls := 0
+	l.VariantFunc = func(ref Ref, info any) any {
+		calls++
+		configInfo := info.(ConfigInfo)
+		if calls > 1 {
+			// A changed variant must not affect the loaded config.
+			configInfo.Path = "a.yaml"
+		}
+		return configInfo
+	}
+	l.ConfigRegistry.Set(1, ConfigInfo{Path: "a.json", Data: []byte(`{"volume": 5}`)})
+	l.LoadConfig(1)
+	type settings struct {
+		Volume int `json:"volume"`
+	}
+	if have := DecodeConfig[settings](l, 1); have.Volume != 5 {
+		t.Fatalf("volume mismatch: have %d, want 5", have.Volume)
+	}
+	if calls != 1 {
+		t.Fatalf("VariantFunc is called %d times", calls)
+	}
+}
@@ -0,0 +1,204 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+)
+
+// Easing is a function that maps a [0, 1] segment progress
+// to the interpolation weight.
+//
+// The JSON definitions refer to the easings by their names:
+// "linear" (the default), "step", and "in_", "out_" and "in_out_"
+// variants of "quad", "cubic" and "sine" (like "in_out_sine").
+type Easing func(t float64) float64
+
+// easings are the supported JSON easing names.
+var easings = map[string]Easing{
+	"linear": func(t float64) float64 { return t },
+	"step":   func(t float64) float64 { return 0 },
+
+	"in_quad":     func(t float64) float64 { return t * t },
+	"out_quad":    func(t float64) float64 { return t * (2 - t) },
+	"in_out_quad": easeInOut(func(t float64) float64 { return t * t }),
+
+	"in_cubic":     func(t float64) float64 { return t * t * t },
+	"out_cubic":    func(t float64) float64 { return 1 - math.Pow(1-t, 3) },
+	"in_out_cubic": easeInOut(func(t float64) float64 { return t * t * t }),
+
+	"in_sine":     func(t float64) float64 { return 1 - math.Cos(t*math.Pi/2) },
+	"out_sine":    func(t float64) float64 { return math.Sin(t * math.Pi / 2) },
+	"in_out_sine": func(t float64) float64 { return (1 - math.Cos(t*math.Pi)) / 2 },
+}
+
+func easeInOut(in Easing) Easing {
+	return func(t float64) float64 {
+		if t < 0.5 {
+			return in(t*2) / 2
+		}
+		return 1 - in((1-t)*2)/2
+	}
+}
+
+func lookupEasing(name string) (Easing, error) {
+	if name == "" {
+		return easings["linear"], nil
+	}
+	e, ok := easings[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown easing %q", name)
+	}
+	return e, nil
+}
+
+// CurveKey is a curve keyframe.
+type CurveKey struct {
+	T     float64
+	Value float64
+
+	// Ease is applied to the segment that starts at this key.
+	Ease Easing
+}
+
+// segmentWeight finds the keys segment for t and returns its
+// start index and the eased interpolation weight.
+// The t values outside of the keys range are clamped.
+func segmentWeight(n int, keyT func(i int) float64, ease func(i int) Easing, t float64) (int, float64) {
+	if t <= keyT(0) {
+		return 0, 0
+	}
+	for i := 1; i < n; i++ {
+		if t < keyT(i) {
+			a := keyT(i - 1)
+			b := keyT(i)
+			return i - 1, ease(i - 1)((t - a) / (b - a))
+		}
+	}
+	return n - 1, 0
+}
+
+// At returns the curve value at t.
+// The t values outside of the keys range are clamped,
+// unless the curve is looped.
+func (c Curve) At(t float64) float64 {
+	keys := c.Keys
+	if len(keys) == 0 {
+		return 0
+	}
+	if c.Loop && len(keys) > 1 {
+		first := keys[0].T
+		t = first + math.Mod(t-first, keys[len(keys)-1].T-first)
+		if t < first {
+			t += keys[len(keys)-1].T - first
+		}
+	}
+	i, w := segmentWeight(len(keys),
+		func(i int) float64 { return keys[i].T },
+		func(i int) Easing { return keys[i].Ease },
+		t)
+	if w == 0 {
+		return keys[i].Value
+	}
+	return keys[i].Value + (keys[i+1].Value-keys[i].Value)*w
+}
+
+// GradientStop is a gradient color stop.
+type GradientStop struct {
+	T     float64
+	Color color.NRGBA
+
+	// Ease is applied to the segment that starts at this stop.
+	Ease Easing
+}
+
+// At returns the gradient color at t.
+// The t values outside of the stops range are clamped.
+func (g Gradient) At(t float64) color.NRGBA {
+	stops := g.Stops
+	if len(stops) == 0 {
+		return color.NRGBA{}
+	}
+	i, w := segmentWeight(len(stops),
+		func(i int) float64 { return stops[i].T },
+		func(i int) Easing { return stops[i].Ease },
+		t)
+	if w == 0 {
+		return stops[i].Color
+	}
+	a := stops[i].Color
+	b := stops[i+1].Color
+	mix := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*w))
+	}
+	return color.NRGBA{R: mix(a.R, b.R), G: mix(a.G, b.G), B: mix(a.B, b.B), A: mix(a.A, b.A)}
+}
+
+// decodeCurve decodes a JSON curve, see Curve for the format description.
+func decodeCurve(r io.Reader) (Curve, error) {
+	var doc struct {
+		Loop bool `json:"loop"`
+		Keys []struct {
+			T     float64 `json:"t"`
+			Value float64 `json:"value"`
+			Ease  string  `json:"ease"`
+		} `json:"keys"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return Curve{}, err
+	}
+	if len(doc.Keys) == 0 {
+		return Curve{}, fmt.Errorf("curve has no keys")
+	}
+	c := Curve{Loop: doc.Loop, Keys: make([]CurveKey, len(doc.Keys))}
+	for i, k := range doc.Keys {
+		if i > 0 && k.T <= doc.Keys[i-1].T {
+			return Curve{}, fmt.Errorf("key %d: keys are not sorted by t", i)
+		}
+		ease, err := lookupEasing(k.Ease)
+		if err != nil {
+			return Curve{}, fmt.Errorf("key %d: %w", i, err)
+		}
+		c.Keys[i] = CurveKey{T: k.T, Value: k.Value, Ease: ease}
+	}
+	return c, nil
+}
+
+// decodeGradient decodes a JSON gradient, see Gradient for the format description.
+func decodeGradient(r io.Reader) (Gradient, error) {
+	var doc struct {
+		Stops []struct {
+			T     float64 `json:"t"`
+			Color string  `json:"color"`
+			Ease  string  `json:"ease"`
+		} `json:"stops"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return Gradient{}, err
+	}
+	if len(doc.Stops) == 0 {
+		return Gradient{}, fmt.Errorf("gradient has no stops")
+	}
+	g := Gradient{Stops: make([]GradientStop, len(doc.Stops))}
+	for i, s := range doc.Stops {
+		if i > 0 && s.T <= doc.Stops[i-1].T {
+			return Gradient{}, fmt.Errorf("stop %d: stops are not sorted by t", i)
+		}
+		c, err := parseHexColor(s.Color)
+		if err != nil {
+			return Gradient{}, fmt.Errorf("stop %d: %w", i, err)
+		}
+		ease, err := lookupEasing(s.Ease)
+		if err != nil {
+			return Gradient{}, fmt.Errorf("stop %d: %w", i, err)
+		}
+		g.Stops[i] = GradientStop{T: s.T, Color: c, Ease: ease}
+	}
+	return g, nil
+}
@@ -1,16 +1,40 @@
 package resource
 
 import (
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2/audio"
-	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
 )
 
+// Loop wraps a stream into an infinite loop.
+//
+// The stream should have a Length() int64 method that reports
+// the stream length in bytes (OGG and WAV streams do).
+// Loop panics if the stream has no such method.
+func Loop(stream io.ReadSeeker) io.ReadSeeker {
+	s, ok := stream.(lengthStream)
+	if !ok {
+		panic(fmt.Sprintf("loop %T stream: it has no Length method", stream))
+	}
+	return audio.NewInfiniteLoop(s, s.Length())
+}
+
 // LoogOGG wraps OGG vorbis stream into an infinite loop.
+//
+// It also accepts the streams that are created from the disk-cached
+// OGG resources (see Loader.DiskCacheDir).
 func LoopOGG(stream io.ReadSeeker) io.ReadSeeker {
-	oggStream := stream.(*vorbis.Stream)
-	return audio.NewInfiniteLoop(oggStream, oggStream.Length())
+	return Loop(stream)
+}
+
+// LoopWAV wraps WAV stream into an infinite loop.
+//
+// Note that using this decorator makes the WAV resource streamed
+// instead of being read into memory (see AudioInfo.StreamDecorator).
+func LoopWAV(stream io.ReadSeeker) io.ReadSeeker {
+	return Loop(stream)
 }
 
 // NopDecorator returns the input stream as is.
@@ -24,3 +48,39 @@ func LoopOGG(stream io.ReadSeeker) io.ReadSeeker {
 func NopDecorator(stream io.ReadSeeker) io.ReadSeeker {
 	return stream
 }
+
+// ComposeDecorators combines several stream decorators into one.
+// The decorators are applied in order: the first decorator wraps
+// the original stream, the second one wraps the result of the first one, etc.
+//
+// This makes it possible to combine loop, fade and volume normalization
+// decorators declaratively:
+//
+//	StreamDecorator: resource.ComposeDecorators(resource.LoopOGG, fadeIn)
+func ComposeDecorators(decorators ...func(stream io.ReadSeeker) io.ReadSeeker) func(stream io.ReadSeeker) io.ReadSeeker {
+	return func(stream io.ReadSeeker) io.ReadSeeker {
+		for _, d := range decorators {
+			stream = d(stream)
+		}
+		return stream
+	}
+}
+
+type lengthStream interface {
+	io.ReadSeeker
+	Length() int64
+}
+
+// StreamDuration converts the decoded stream length (in bytes) to its duration.
+// The stream is expected to be in the Ebitengine audio format:
+// 16-bit signed stereo samples (4 bytes per sample frame).
+//
+// It returns 0 if the sampleRate is not positive.
+func StreamDuration(length int64, sampleRate int) time.Duration {
+	if sampleRate <= 0 {
+		return 0
+	}
+	const bytesPerFrame = 4
+	frames := length / bytesPerFrame
+	return time.Duration(frames) * time.Second / time.Duration(sampleRate)
+}
@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDependencyCycle(t *testing.T) {
+	tests := []struct {
+		name string
+		deps map[RawID][]RawID
+		want string
+	}{
+		{
+			name: "self",
+			deps: map[RawID][]RawID{1: {1}},
+			want: `load "1.txt" raw: dependency cycle: raw with id=1 -> raw with id=1`,
+		},
+		{
+			name: "pair",
+			deps: map[RawID][]RawID{1: {2}, 2: {1}},
+			want: `load "1.txt" raw: dependency cycle: raw with id=1 -> raw with id=2 -> raw with id=1`,
+		},
+		{
+			name: "triple",
+			deps: map[RawID][]RawID{1: {2}, 2: {3}, 3: {1}},
+			want: `load "1.txt" raw: dependency cycle: raw with id=1 -> raw with id=2 -> raw with id=3 -> raw with id=1`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			for id, deps := range test.deps {
+				info := RawInfo{Path: fmt.Sprintf("%d.txt", id), Data: []byte("x")}
+				for _, dep := range deps {
+					info.DependsOn = append(info.DependsOn, dep.Ref())
+				}
+				l.RawRegistry.Set(id, info)
+			}
+			have := recoverString(func() { l.LoadRaw(1) })
+			if have != test.want {
+				t.Fatalf("panic mismatch:\nhave: %q\nwant: %q", have, test.want)
+			}
+			if len(l.depStack) != 0 {
+				t.Fatalf("dependency stack is not empty after a panic: %v", l.depStack)
+			}
+		})
+	}
+}
+
+func TestDependencyDiamond(t *testing.T) {
+	// 1 => {2, 3}; 2 => 4; 3 => 4 is not a cycle.
+	l := NewLoader(nil)
+	deps := map[RawID][]RawID{1: {2, 3}, 2: {4}, 3: {4}, 4: nil}
+	for id, ids := range deps {
+		info := RawInfo{Path: fmt.Sprintf("%d.txt", id), Data: []byte("x")}
+		for _, dep := range ids {
+			info.DependsOn = append(info.DependsOn, dep.Ref())
+		}
+		l.RawRegistry.Set(id, info)
+	}
+	l.LoadRaw(1)
+	for id := range deps {
+		if !l.IsLoaded(id.Ref()) {
+			t.Fatalf("raw %d is not loaded", id)
+		}
+	}
+}
+
+func recoverString(f func()) (s string) {
+	defer func() {
+		if rv := recover(); rv != nil {
+			s = fmt.Sprint(rv)
+		}
+	}()
+	f()
+	return ""
+}
+
+func TestDependencyFailureRollback(t *testing.T) {
+	l := NewLoader(nil)
+	l.RawRegistry.Set(1, RawInfo{Path: "1.txt", Data: []byte("x"), DependsOn: []Ref{RawID(2).Ref(), RawID(3).Ref()}})
+	l.RawRegistry.Set(2, RawInfo{Path: "2.txt", Data: []byte("x")})
+	// Raw 3 is not registered.
+
+	if err := l.TryLoad(RawID(1).Ref()); err == nil {
+		t.Fatal("expected a load error")
+	}
+	if l.IsLoaded(RawID(1).Ref()) {
+		t.Fatal("raw 1 is loaded after a failed dependency")
+	}
+	if !l.Unload(RawID(2).Ref()) {
+		t.Fatal("raw 2 can't be unloaded after a failed dependent load")
+	}
+}
@@ -0,0 +1,293 @@
+// Package dialog loads Yarn Spinner dialogs into traversable dialog trees.
+//
+// Both the Yarn source format (.yarn files) and the Yarn Editor JSON
+// export format are supported. Only the dialog structure is parsed:
+// lines, options and jumps. Other commands (like <<set>> or <<if>>)
+// are preserved as Line.Command values for the game to interpret.
+//
+// The dialog sources are read as Raw resources:
+//
+//	dialogs := dialog.NewCache(loader)
+//	tree := dialogs.Load(RawIntroDialog)
+//	node := tree.Node("Start")
+package dialog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	resource "github.com/quasilyte/ebitengine-resource"
+)
+
+// Tree is a parsed dialog.
+type Tree struct {
+	// Nodes maps a node title to the node.
+	Nodes map[string]*Node
+}
+
+// Node returns the node with a given title.
+// It returns nil if there is no such node.
+func (t *Tree) Node(title string) *Node {
+	return t.Nodes[title]
+}
+
+// Node is a single dialog node.
+//
+// Playing a node means showing all of its lines and then
+// either presenting the options or following the Jump.
+type Node struct {
+	Title string
+
+	Tags []string
+
+	Lines []Line
+
+	Options []Option
+
+	// Jump is a title of the node to go to after this node is finished.
+	// It's empty if there is no top-level jump command in this node.
+	Jump string
+}
+
+// Line is a dialog node text line or a command.
+type Line struct {
+	// Speaker is a "Name" part of the "Name: text" lines.
+	// It's empty if the line has no speaker prefix.
+	Speaker string
+
+	Text string
+
+	// Command is a contents of the <<command>> line.
+	// Text and Speaker are empty for the command lines.
+	Command string
+}
+
+// Option is a dialog choice presented to the player.
+type Option struct {
+	Text string
+
+	// Target is a title of the node to go to if this option is selected.
+	// It's empty if the option doesn't change the current node.
+	Target string
+
+	// Lines are shown after this option is selected.
+	// These are the indented lines below the "-> Text" option.
+	Lines []Line
+}
+
+// Cache parses and caches the dialog trees stored as Raw resources.
+type Cache struct {
+	loader *resource.Loader
+	trees  map[resource.RawID]*Tree
+}
+
+// NewCache returns a dialog cache that reads the dialog sources via l.
+func NewCache(l *resource.Loader) *Cache {
+	return &Cache{
+		loader: l,
+		trees:  make(map[resource.RawID]*Tree),
+	}
+}
+
+// Load returns a dialog tree parsed from the Raw resource with a given ID.
+// Only a first call for this id will lead to resource parsing,
+// all next calls return the cached result.
+func (c *Cache) Load(id resource.RawID) *Tree {
+	tree, ok := c.trees[id]
+	if !ok {
+		raw := c.loader.LoadRaw(id)
+		var err error
+		tree, err = Parse(raw.Data)
+		if err != nil {
+			path := c.loader.GetRawInfo(id).Path
+			panic(fmt.Sprintf("parse %q dialog: %v", path, err))
+		}
+		c.trees[id] = tree
+	}
+	return tree
+}
+
+// Unload removes the tree from the cache.
+// It doesn't unload the underlying Raw resource.
+func (c *Cache) Unload(id resource.RawID) {
+	delete(c.trees, id)
+}
+
+// Parse parses the Yarn source or the Yarn Editor JSON export.
+func Parse(data []byte) (*Tree, error) {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '[' {
+		return parseJSON(trimmed)
+	}
+	return parseSource(data)
+}
+
+type jsonNode struct {
+	Title string `json:"title"`
+	Tags  string `json:"tags"`
+	Body  string `json:"body"`
+}
+
+func parseJSON(data []byte) (*Tree, error) {
+	var nodes []jsonNode
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, err
+	}
+	b := newTreeBuilder()
+	for _, n := range nodes {
+		if err := b.addNode(n.Title, strings.Fields(n.Tags), strings.Split(n.Body, "\n")); err != nil {
+			return nil, err
+		}
+	}
+	return b.finish()
+}
+
+func parseSource(data []byte) (*Tree, error) {
+	b := newTreeBuilder()
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	for len(lines) != 0 {
+		// Parse the node header.
+		var title string
+		var tags []string
+		for len(lines) != 0 {
+			l := strings.TrimSpace(lines[0])
+			lines = lines[1:]
+			if l == "---" {
+				break
+			}
+			key, value, ok := strings.Cut(l, ":")
+			if !ok {
+				continue
+			}
+			switch strings.TrimSpace(key) {
+			case "title":
+				title = strings.TrimSpace(value)
+			case "tags":
+				tags = strings.Fields(value)
+			}
+		}
+		if title == "" {
+			break
+		}
+		// Collect the node body.
+		var body []string
+		closed := false
+		for len(lines) != 0 {
+			l := lines[0]
+			lines = lines[1:]
+			if strings.TrimSpace(l) == "===" {
+				closed = true
+				break
+			}
+			body = append(body, l)
+		}
+		if !closed {
+			return nil, fmt.Errorf("node %q: missing === terminator", title)
+		}
+		if err := b.addNode(title, tags, body); err != nil {
+			return nil, err
+		}
+	}
+	return b.finish()
+}
+
+type treeBuilder struct {
+	tree *Tree
+}
+
+func newTreeBuilder() *treeBuilder {
+	return &treeBuilder{
+		tree: &Tree{Nodes: make(map[string]*Node)},
+	}
+}
+
+func (b *treeBuilder) addNode(title string, tags []string, body []string) error {
+	if title == "" {
+		return errors.New("node with empty title")
+	}
+	if _, ok := b.tree.Nodes[title]; ok {
+		return fmt.Errorf("duplicated node %q", title)
+	}
+	n := &Node{Title: title, Tags: tags}
+	// An index of the "-> Text" option that owns the indented lines below it.
+	lastOption := -1
+	lastOptionIndent := 0
+	for _, rawLine := range body {
+		l := strings.TrimSpace(rawLine)
+		indent := len(rawLine) - len(strings.TrimLeft(rawLine, " \t"))
+		if l == "" || strings.HasPrefix(l, "//") {
+			continue
+		}
+		if lastOption != -1 && indent <= lastOptionIndent {
+			lastOption = -1
+		}
+		switch {
+		case strings.HasPrefix(l, "[[") && strings.HasSuffix(l, "]]"):
+			// Yarn 1 style option: [[Text|Target]] or [[Target]].
+			text, target, ok := strings.Cut(l[len("[["):len(l)-len("]]")], "|")
+			if !ok {
+				target = text
+			}
+			n.Options = append(n.Options, Option{
+				Text:   strings.TrimSpace(text),
+				Target: strings.TrimSpace(target),
+			})
+		case strings.HasPrefix(l, "->"):
+			n.Options = append(n.Options, Option{Text: strings.TrimSpace(l[len("->"):])})
+			lastOption = len(n.Options) - 1
+			lastOptionIndent = indent
+		case strings.HasPrefix(l, "<<") && strings.HasSuffix(l, ">>"):
+			cmd := strings.TrimSpace(l[len("<<") : len(l)-len(">>")])
+			if target, ok := parseJump(cmd); ok {
+				if lastOption != -1 {
+					n.Options[lastOption].Target = target
+				} else {
+					n.Jump = target
+				}
+				continue
+			}
+			n.addLine(lastOption, Line{Command: cmd})
+		default:
+			line := Line{Text: l}
+			if speaker, text, ok := strings.Cut(l, ": "); ok && !strings.ContainsAny(speaker, "[<") {
+				line.Speaker = strings.TrimSpace(speaker)
+				line.Text = strings.TrimSpace(text)
+			}
+			n.addLine(lastOption, line)
+		}
+	}
+	b.tree.Nodes[title] = n
+	return nil
+}
+
+func (n *Node) addLine(option int, l Line) {
+	if option != -1 {
+		n.Options[option].Lines = append(n.Options[option].Lines, l)
+		return
+	}
+	n.Lines = append(n.Lines, l)
+}
+
+func (b *treeBuilder) finish() (*Tree, error) {
+	for _, n := range b.tree.Nodes {
+		if n.Jump != "" && b.tree.Nodes[n.Jump] == nil {
+			return nil, fmt.Errorf("node %q: jump to undefined node %q", n.Title, n.Jump)
+		}
+		for _, o := range n.Options {
+			if o.Target != "" && b.tree.Nodes[o.Target] == nil {
+				return nil, fmt.Errorf("node %q: option %q refers to undefined node %q", n.Title, o.Text, o.Target)
+			}
+		}
+	}
+	return b.tree, nil
+}
+
+func parseJump(cmd string) (string, bool) {
+	fields := strings.Fields(cmd)
+	if len(fields) != 2 || fields[0] != "jump" {
+		return "", false
+	}
+	return fields[1], true
+}
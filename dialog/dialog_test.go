@@ -0,0 +1,132 @@
+package dialog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSource = `title: Start
+tags: intro  tutorial
+position: 0,0
+---
+// A comment.
+Narrator: Welcome!
+Just a line without a speaker.
+<<set $visited to true>>
+-> Ask about the weather
+    Guide: It's sunny.
+    <<jump Weather>>
+-> Leave
+[[Skip|End]]
+===
+title: Weather
+---
+Guide: See you.
+<<jump End>>
+===
+title: End
+---
+===
+`
+
+func TestParse(t *testing.T) {
+	want := map[string]*Node{
+		"Start": {
+			Title: "Start",
+			Tags:  []string{"intro", "tutorial"},
+			Lines: []Line{
+				{Speaker: "Narrator", Text: "Welcome!"},
+				{Text: "Just a line without a speaker."},
+				{Command: "set $visited to true"},
+			},
+			Options: []Option{
+				{Text: "Ask about the weather", Target: "Weather", Lines: []Line{{Speaker: "Guide", Text: "It's sunny."}}},
+				{Text: "Leave"},
+				{Text: "Skip", Target: "End"},
+			},
+		},
+		"Weather": {
+			Title: "Weather",
+			Lines: []Line{{Speaker: "Guide", Text: "See you."}},
+			Jump:  "End",
+		},
+		"End": {Title: "End"},
+	}
+
+	jsonExport := `[
+		{"title": "Start", "tags": "intro tutorial", "body": "// A comment.\nNarrator: Welcome!\nJust a line without a speaker.\n<<set $visited to true>>\n-> Ask about the weather\n    Guide: It's sunny.\n    <<jump Weather>>\n-> Leave\n[[Skip|End]]"},
+		{"title": "Weather", "tags": "", "body": "Guide: See you.\n<<jump End>>"},
+		{"title": "End", "body": ""}
+	]`
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"source", testSource},
+		{"source crlf", strings.ReplaceAll(testSource, "\n", "\r\n")},
+		{"json", jsonExport},
+		{"json with spaces", "\n  " + jsonExport},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tree, err := Parse([]byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(tree.Nodes) != len(want) {
+				t.Fatalf("have %d nodes, want %d", len(tree.Nodes), len(want))
+			}
+			for title, wantNode := range want {
+				have := tree.Node(title)
+				if have == nil {
+					t.Fatalf("node %q is missing", title)
+				}
+				if len(have.Tags) == 0 {
+					have.Tags = nil
+				}
+				if !reflect.DeepEqual(have, wantNode) {
+					t.Errorf("node %q mismatch:\nhave: %+v\nwant: %+v", title, have, wantNode)
+				}
+			}
+		})
+	}
+}
+
+func TestParseOptionTargets(t *testing.T) {
+	tree, err := Parse([]byte("title: A\n---\n[[B]]\n[[Go back | A]]\n===\ntitle: B\n---\n===\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Option{{Text: "B", Target: "B"}, {Text: "Go back", Target: "A"}}
+	if have := tree.Node("A").Options; !reflect.DeepEqual(have, want) {
+		t.Fatalf("options mismatch:\nhave: %+v\nwant: %+v", have, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no terminator", "title: A\n---\nline\n", `node "A": missing === terminator`},
+		{"duplicated", "title: A\n---\n===\ntitle: A\n---\n===\n", `duplicated node "A"`},
+		{"undefined jump", "title: A\n---\n<<jump B>>\n===\n", `node "A": jump to undefined node "B"`},
+		{"undefined option", "title: A\n---\n-> Go\n    <<jump B>>\n===\n", `node "A": option "Go" refers to undefined node "B"`},
+		{"undefined link", "title: A\n---\n[[Go|B]]\n===\n", `node "A": option "Go" refers to undefined node "B"`},
+		{"json syntax", `[{"title": "A"`, "unexpected end of JSON input"},
+		{"json empty title", `[{"title": "", "body": ""}]`, "node with empty title"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse([]byte(test.data))
+			if err == nil || err.Error() != test.want {
+				t.Fatalf("error mismatch:\nhave: %v\nwant: %q", err, test.want)
+			}
+		})
+	}
+}
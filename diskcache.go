@@ -0,0 +1,248 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// pcmCacheMagic starts the decoded OGG cache entries.
+// It's followed by the source sample rate (uint32, little endian)
+// and the decoded PCM data.
+const pcmCacheMagic = "PCM1"
+
+const pcmHeaderSize = len(pcmCacheMagic) + 4
+
+// pcmStream is a decoded audio stream that is read from the disk cache.
+// It implements the Length method, so it can be used with LoopOGG.
+type pcmStream struct {
+	*io.SectionReader
+	sampleRate int
+}
+
+func (s *pcmStream) Length() int64 { return s.Size() }
+
+// SampleRate returns the source OGG sample rate.
+// It's zero if the rate is unknown.
+func (s *pcmStream) SampleRate() int { return s.sampleRate }
+
+// pcmFile is an opened disk cache entry of the decoded OGG.
+// The streams read it with ReadAt, so all players
+// of the resource share a single file descriptor.
+type pcmFile struct {
+	f          *os.File
+	size       int64
+	sampleRate int
+}
+
+func (p *pcmFile) newStream() *pcmStream {
+	return &pcmStream{
+		SectionReader: io.NewSectionReader(p.f, int64(pcmHeaderSize), p.size-int64(pcmHeaderSize)),
+		sampleRate:    p.sampleRate,
+	}
+}
+
+func (l *Loader) loadCachedOGG(id AudioID, info AudioInfo) io.ReadSeeker {
+	if p, ok := l.pcmFiles[id]; ok {
+		return p.newStream()
+	}
+
+	r := l.openResource(id.Ref(), info.Path, info.Data)
+	data, err := io.ReadAll(r)
+	if err != nil {
+		panic(newError("read", id.Ref(), info.Path, err))
+	}
+	if err := r.Close(); err != nil {
+		panic(newError("read", id.Ref(), info.Path, err))
+	}
+
+	sum := sha256.Sum256(data)
+	cachePath := filepath.Join(l.DiskCacheDir, hex.EncodeToString(sum[:])+".pcm")
+	p := openPCMFile(cachePath)
+	if p == nil {
+		done := l.profileStart(id.Ref(), info.Path, stageDecode)
+		oggStream, err := vorbis.DecodeWithoutResampling(bytes.NewReader(data))
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), info.Path, data, err))
+		}
+		if writePCMCache(cachePath, oggSampleRate(data), oggStream) {
+			p = openPCMFile(cachePath)
+		}
+		done()
+	}
+	if p == nil {
+		// The cache is not writable: decode the data on the fly.
+		stream, err := vorbis.DecodeWithoutResampling(bytes.NewReader(data))
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), info.Path, data, err))
+		}
+		return &oggStream{Stream: stream, sampleRate: oggSampleRate(data)}
+	}
+	if l.pcmFiles == nil {
+		l.pcmFiles = make(map[AudioID]*pcmFile)
+	}
+	l.pcmFiles[id] = p
+	return p.newStream()
+}
+
+// closePCMFile closes the disk cache entry opened for the audio resource.
+// The streams that read it become invalid.
+func (l *Loader) closePCMFile(id AudioID) {
+	if p, ok := l.pcmFiles[id]; ok {
+		p.f.Close()
+		delete(l.pcmFiles, id)
+	}
+}
+
+func openPCMFile(path string) *pcmFile {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	stat, err := f.Stat()
+	if err != nil || stat.Size() < int64(pcmHeaderSize) {
+		f.Close()
+		return nil
+	}
+	var header [pcmHeaderSize]byte
+	if _, err := f.ReadAt(header[:], 0); err != nil || string(header[:len(pcmCacheMagic)]) != pcmCacheMagic {
+		// An entry of the older format or a broken file: it will be overwritten.
+		f.Close()
+		return nil
+	}
+	return &pcmFile{
+		f:          f,
+		size:       stat.Size(),
+		sampleRate: int(binary.LittleEndian.Uint32(header[len(pcmCacheMagic):])),
+	}
+}
+
+// writePCMCache streams the decoded PCM data into the disk cache entry.
+// It reports whether the entry was written.
+func writePCMCache(path string, sampleRate int, pcm io.Reader) bool {
+	return writeCacheFileFrom(path, func(w io.Writer) error {
+		var header [pcmHeaderSize]byte
+		copy(header[:], pcmCacheMagic)
+		binary.LittleEndian.PutUint32(header[len(pcmCacheMagic):], uint32(sampleRate))
+		if _, err := w.Write(header[:]); err != nil {
+			return err
+		}
+		_, err := io.Copy(w, pcm)
+		return err
+	})
+}
+
+// writeCacheFile writes the disk cache entry.
+func writeCacheFile(path string, data []byte) {
+	writeCacheFileFrom(path, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeCacheFileFrom writes the disk cache entry contents using the write function.
+// It reports whether the entry was written.
+func writeCacheFileFrom(path string, write func(w io.Writer) error) bool {
+	// Writing into a temporary file first makes sure that
+	// we never observe a partially written cache entry.
+	// All errors are ignored here: the cache is optional.
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return false
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "cache-*.tmp")
+	if err != nil {
+		return false
+	}
+	err = write(f)
+	closeErr := f.Close()
+	if err != nil || closeErr != nil {
+		os.Remove(f.Name())
+		return false
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		os.Remove(f.Name())
+		return false
+	}
+	return true
+}
+
+// sdfCacheVersion is a part of the SDF cache key.
+// It should be changed when the SDF generation algorithm changes.
+const sdfCacheVersion = "sdf-v2"
+
+// sdfCacheEntry is an SDF font disk cache file contents.
+type sdfCacheEntry struct {
+	Glyphs     map[rune]SDFGlyph
+	Spread     int
+	Ascent     float64
+	Descent    float64
+	LineHeight float64
+
+	AtlasWidth  int
+	AtlasHeight int
+	AtlasPix    []byte
+}
+
+// sdfCacheKey returns the SDF font cache file name.
+// The glyphs depend on the font data and all generation parameters.
+func sdfCacheKey(info SDFFontInfo, fontData []byte) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s size=%d dpi=%d spread=%d glyphs=%q\n", sdfCacheVersion, info.Size, fontDPI, info.Spread, info.Glyphs)
+	h.Write(fontData)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func readSDFCache(path string) (SDFFont, *image.Alpha, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return SDFFont{}, nil, false
+	}
+	defer f.Close()
+	var e sdfCacheEntry
+	if err := gob.NewDecoder(f).Decode(&e); err != nil {
+		return SDFFont{}, nil, false
+	}
+	if e.AtlasWidth <= 0 || e.AtlasHeight <= 0 || len(e.AtlasPix) != e.AtlasWidth*e.AtlasHeight {
+		return SDFFont{}, nil, false
+	}
+	atlas := &image.Alpha{
+		Pix:    e.AtlasPix,
+		Stride: e.AtlasWidth,
+		Rect:   image.Rect(0, 0, e.AtlasWidth, e.AtlasHeight),
+	}
+	font := SDFFont{
+		Glyphs:     e.Glyphs,
+		Spread:     e.Spread,
+		Ascent:     e.Ascent,
+		Descent:    e.Descent,
+		LineHeight: e.LineHeight,
+	}
+	return font, atlas, true
+}
+
+func writeSDFCache(path string, font SDFFont, atlas *image.Alpha) {
+	e := sdfCacheEntry{
+		Glyphs:      font.Glyphs,
+		Spread:      font.Spread,
+		Ascent:      font.Ascent,
+		Descent:     font.Descent,
+		LineHeight:  font.LineHeight,
+		AtlasWidth:  atlas.Rect.Dx(),
+		AtlasHeight: atlas.Rect.Dy(),
+		AtlasPix:    atlas.Pix,
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(e); err != nil {
+		return
+	}
+	writeCacheFile(path, buf.Bytes())
+}
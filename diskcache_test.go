@@ -0,0 +1,137 @@
+package resource
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestSDFCache(t *testing.T) {
+	tt, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := SDFFontInfo{Size: 16, Glyphs: "AB"}
+	font, atlas, err := generateSDFFont(tt, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), sdfCacheKey(info, goregular.TTF)+".sdf")
+	if _, _, ok := readSDFCache(path); ok {
+		t.Fatal("reading a missing cache entry succeeded")
+	}
+	writeSDFCache(path, font, atlas)
+	cachedFont, cachedAtlas, ok := readSDFCache(path)
+	if !ok {
+		t.Fatal("can't read the cache entry")
+	}
+	if !reflect.DeepEqual(cachedFont, font) {
+		t.Fatalf("font mismatch:\nhave: %+v\nwant: %+v", cachedFont, font)
+	}
+	if cachedAtlas.Rect != atlas.Rect || !bytes.Equal(cachedAtlas.Pix, atlas.Pix) {
+		t.Fatal("atlas mismatch")
+	}
+}
+
+func TestSDFCacheKey(t *testing.T) {
+	base := SDFFontInfo{Size: 16, Glyphs: "AB"}
+	key := sdfCacheKey(base, []byte("font"))
+	if sdfCacheKey(base, []byte("font")) != key {
+		t.Fatal("the key is not deterministic")
+	}
+	variants := []struct {
+		name string
+		info SDFFontInfo
+		data string
+	}{
+		{"data", base, "other font"},
+		{"size", SDFFontInfo{Size: 17, Glyphs: "AB"}, "font"},
+		{"spread", SDFFontInfo{Size: 16, Glyphs: "AB", Spread: 3}, "font"},
+		{"glyphs", SDFFontInfo{Size: 16, Glyphs: "ABC"}, "font"},
+	}
+	for _, v := range variants {
+		if sdfCacheKey(v.info, []byte(v.data)) == key {
+			t.Errorf("%s change doesn't change the key", v.name)
+		}
+	}
+}
+
+func TestPCMCache(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "track.pcm")
+	if openPCMFile(path) != nil {
+		t.Fatal("opening a missing cache entry succeeded")
+	}
+
+	pcm := bytes.Repeat([]byte{1, 2, 3, 4}, 1000)
+	if !writePCMCache(path, 22050, bytes.NewReader(pcm)) {
+		t.Fatal("can't write the cache entry")
+	}
+	p := openPCMFile(path)
+	if p == nil {
+		t.Fatal("can't open the cache entry")
+	}
+	defer p.f.Close()
+
+	// Every stream is independent, but they share the file.
+	s1 := p.newStream()
+	s2 := p.newStream()
+	if s1.Length() != int64(len(pcm)) {
+		t.Fatalf("have %d length, want %d", s1.Length(), len(pcm))
+	}
+	if s1.SampleRate() != 22050 {
+		t.Fatalf("have %d sample rate, want 22050", s1.SampleRate())
+	}
+	if _, err := s1.Seek(100, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(s2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pcm) {
+		t.Fatal("stream data mismatch")
+	}
+	data, err = io.ReadAll(s1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, pcm[100:]) {
+		t.Fatal("seeked stream data mismatch")
+	}
+}
+
+func TestPCMCacheOldFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.pcm")
+	// The entries written without a header are ignored.
+	if err := os.WriteFile(path, bytes.Repeat([]byte{1, 2, 3, 4}, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if openPCMFile(path) != nil {
+		t.Fatal("opened an entry without a header")
+	}
+}
+
+func TestClosePCMFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "track.pcm")
+	if !writePCMCache(path, 44100, bytes.NewReader(make([]byte, 64))) {
+		t.Fatal("can't write the cache entry")
+	}
+	l := NewLoader(nil)
+	l.pcmFiles = map[AudioID]*pcmFile{1: openPCMFile(path)}
+	stream := l.pcmFiles[1].newStream()
+	l.Unload(AudioID(1).Ref())
+	if len(l.pcmFiles) != 0 {
+		t.Fatal("the cache entry is not closed on unload")
+	}
+	if _, err := io.ReadAll(stream); err == nil {
+		t.Fatal("reading a closed cache entry succeeded")
+	}
+}
@@ -0,0 +1,40 @@
+// Package resource implements a resource manager (loader) for Ebitengine.
+//
+// Every resource kind has a typed ID (like ImageID), an info type that
+// describes how to load the resource (like ImageInfo) and a registry
+// that binds the IDs to their infos (like Loader.ImageRegistry).
+// The first Load call for an ID decodes the resource, the next calls
+// return the cached result.
+//
+// # Resource info fields
+//
+// Most info types share these fields; they work the same way for every kind.
+//
+// Path is the resource path that is passed to the Loader.OpenAssetFunc.
+// Its extension often selects the resource format (see the info type docs).
+//
+// PathByPlatform maps a platform name to a path that is used instead of
+// the Path on that platform. See Loader.Platform for the details.
+//
+// Data is the resource contents. If it's not nil, it's used instead of
+// reading the resource by its path. This makes it possible to register
+// the generated or downloaded resources. The Path is still used to detect
+// the resource format and in the error messages, so it's advised to set it anyway.
+//
+// DependsOn lists the resources this resource depends on.
+// Loading this resource loads all of its dependencies as well.
+// A resource can't be unloaded while it's required by other loaded resources.
+// Some kinds add their implicit dependencies to this list,
+// like the TileSet image.
+//
+// Eager marks a resource that is loaded at startup by Loader.PreloadEager,
+// like the UI or the player character assets.
+// The other resources are loaded lazily, on their first use.
+//
+// Tier is a progressive loading tier of the resource (see Loader.PreloadTier).
+// A zero value means that the resource doesn't belong to any tier.
+//
+// FileSize is the resource data size in bytes. It's optional and only used
+// for the estimations (see Loader.EstimateBytes), so it's usually filled
+// by the code generator.
+package resource
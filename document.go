@@ -0,0 +1,119 @@
+package resource
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+
+	"golang.org/x/image/font"
+)
+
+// TextSpan is a piece of the document text that shares the same style.
+type TextSpan struct {
+	Text string
+
+	// Font is the span font resource ID, Face is its loaded font face.
+	Font FontID
+	Face font.Face
+
+	// Color is the span text color (not premultiplied).
+	Color color.NRGBA
+}
+
+type documentStyle struct {
+	tag   string
+	font  FontID
+	color color.NRGBA
+}
+
+// parseDocument parses the document markup into the styled lines.
+// See Document for the markup description.
+func parseDocument(src string, info DocumentInfo) ([][]TextSpan, error) {
+	defaultColor := info.Color
+	if defaultColor == (color.NRGBA{}) {
+		defaultColor = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	}
+	stack := []documentStyle{{font: info.Font, color: defaultColor}}
+
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := [][]TextSpan{nil}
+	var text strings.Builder
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		style := stack[len(stack)-1]
+		line := lines[len(lines)-1]
+		if n := len(line); n != 0 && line[n-1].Font == style.font && line[n-1].Color == style.color {
+			line[n-1].Text += text.String()
+		} else {
+			line = append(line, TextSpan{Text: text.String(), Font: style.font, Color: style.color})
+		}
+		lines[len(lines)-1] = line
+		text.Reset()
+	}
+
+	lineNum := 1
+	for len(src) != 0 {
+		switch {
+		case src[0] == '\n':
+			flush()
+			lines = append(lines, nil)
+			lineNum++
+			src = src[1:]
+			continue
+		case strings.HasPrefix(src, "[["):
+			text.WriteByte('[')
+			src = src[2:]
+			continue
+		case src[0] != '[':
+			text.WriteByte(src[0])
+			src = src[1:]
+			continue
+		}
+
+		end := strings.IndexAny(src, "]\n")
+		if end == -1 || src[end] != ']' {
+			return nil, fmt.Errorf("line %d: unterminated tag", lineNum)
+		}
+		tag := src[1:end]
+		src = src[end+1:]
+		flush()
+
+		if name := strings.TrimPrefix(tag, "/"); name != tag {
+			top := stack[len(stack)-1]
+			if len(stack) == 1 || top.tag != name {
+				return nil, fmt.Errorf("line %d: unexpected [/%s]", lineNum, name)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		name, value, _ := strings.Cut(tag, "=")
+		style := stack[len(stack)-1]
+		style.tag = name
+		switch name {
+		case "font":
+			fontID, ok := info.Fonts[value]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unknown font %q", lineNum, value)
+			}
+			style.font = fontID
+		case "color":
+			c, err := parseHexColor(value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			style.color = c
+		default:
+			return nil, fmt.Errorf("line %d: unknown tag [%s]", lineNum, tag)
+		}
+		stack = append(stack, style)
+	}
+	flush()
+
+	if len(stack) != 1 {
+		return nil, fmt.Errorf("line %d: unclosed [%s]", lineNum, stack[len(stack)-1].tag)
+	}
+	return lines, nil
+}
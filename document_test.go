@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+func TestParseDocument(t *testing.T) {
+	white := color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	info := DocumentInfo{
+		Font:  1,
+		Fonts: map[string]FontID{"title": 2, "body": 1},
+	}
+
+	tests := []struct {
+		src  string
+		want [][]TextSpan
+	}{
+		{"", [][]TextSpan{nil}},
+		{"plain text", [][]TextSpan{{{Text: "plain text", Font: 1, Color: white}}}},
+		{"a\n\r\nb\r\n", [][]TextSpan{
+			{{Text: "a", Font: 1, Color: white}},
+			nil,
+			{{Text: "b", Font: 1, Color: white}},
+			nil,
+		}},
+		{"[font=title]Credits[/font]", [][]TextSpan{{{Text: "Credits", Font: 2, Color: white}}}},
+		{"x [color=#ff0000]red[/color] y", [][]TextSpan{{
+			{Text: "x ", Font: 1, Color: white},
+			{Text: "red", Font: 1, Color: red},
+			{Text: " y", Font: 1, Color: white},
+		}}},
+		{"[font=title][color=#ff000080]a[/color]b[/font]", [][]TextSpan{{
+			{Text: "a", Font: 2, Color: color.NRGBA{R: 0xff, A: 0x80}},
+			{Text: "b", Font: 2, Color: white},
+		}}},
+		// The same style spans are merged.
+		{"a[font=body]b[/font]c", [][]TextSpan{{{Text: "abc", Font: 1, Color: white}}}},
+		// A style can span several lines.
+		{"[font=title]a\nb[/font]", [][]TextSpan{
+			{{Text: "a", Font: 2, Color: white}},
+			{{Text: "b", Font: 2, Color: white}},
+		}},
+		{"[[x] and ]", [][]TextSpan{{{Text: "[x] and ]", Font: 1, Color: white}}}},
+	}
+
+	for _, test := range tests {
+		have, err := parseDocument(test.src, info)
+		if err != nil {
+			t.Errorf("parse %q: %v", test.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("parse %q:\nhave: %+v\nwant: %+v", test.src, have, test.want)
+		}
+	}
+}
+
+func TestParseDocumentColor(t *testing.T) {
+	green := color.NRGBA{G: 0xff, A: 0xff}
+	have, err := parseDocument("text", DocumentInfo{Color: green})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]TextSpan{{{Text: "text", Color: green}}}
+	if !reflect.DeepEqual(have, want) {
+		t.Fatalf("have: %+v\nwant: %+v", have, want)
+	}
+}
+
+func TestParseDocumentErrors(t *testing.T) {
+	info := DocumentInfo{Fonts: map[string]FontID{"title": 2}}
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"[font=title", "line 1: unterminated tag"},
+		{"a\n[font=title\n]", "line 2: unterminated tag"},
+		{"[/font]", "line 1: unexpected [/font]"},
+		{"[font=title][color=#fff000]a[/font][/color]", "line 1: unexpected [/font]"},
+		{"[font=body]a[/font]", `line 1: unknown font "body"`},
+		{"[color=red]a[/color]", `line 1: bad hex color "red"`},
+		{"[b]a[/b]", "line 1: unknown tag [b]"},
+		{"x\n[font=title]a\nb", "line 3: unclosed [font]"},
+	}
+
+	for _, test := range tests {
+		_, err := parseDocument(test.src, info)
+		if err == nil || err.Error() != test.want {
+			t.Errorf("parse %q:\nhave error: %v\nwant error: %q", test.src, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+)
+
+// maxHistoryLen is a max number of the recent events kept for DumpState.
+const maxHistoryLen = 64
+
+type historyEntry struct {
+	time time.Time
+	typ  EventType
+	ref  Ref
+	err  *Error
+}
+
+// recordHistory adds the event to the recent events ring buffer.
+func (l *Loader) recordHistory(typ EventType, ref Ref, err *Error) {
+	e := historyEntry{time: time.Now(), typ: typ, ref: ref, err: err}
+	if len(l.history) < maxHistoryLen {
+		l.history = append(l.history, e)
+		return
+	}
+	l.history[l.historyPos] = e
+	l.historyPos = (l.historyPos + 1) % maxHistoryLen
+}
+
+type stateDump struct {
+	Platform string             `json:"platform"`
+	Stats    stateDumpStats     `json:"stats"`
+	Kinds    []stateDumpKind    `json:"kinds"`
+	History  []stateDumpHistory `json:"history"`
+}
+
+type stateDumpStats struct {
+	Registered    int   `json:"registered"`
+	Loaded        int   `json:"loaded"`
+	TextureMemory int64 `json:"texture_memory"`
+	Queued        int   `json:"queued"`
+}
+
+type stateDumpKind struct {
+	Kind      string              `json:"kind"`
+	Resources []stateDumpResource `json:"resources"`
+}
+
+type stateDumpResource struct {
+	ID     int    `json:"id"`
+	Path   string `json:"path,omitempty"`
+	Loaded bool   `json:"loaded"`
+}
+
+type stateDumpHistory struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	Kind  string    `json:"kind"`
+	ID    int       `json:"id"`
+	Error string    `json:"error,omitempty"`
+}
+
+// DumpState writes the loader state as JSON to w:
+// the registered resources and their load states,
+// the recent events history (see Subscribe) and the loader stats.
+//
+// It's intended to be used by the crash handlers,
+// so the report shows what the loader was doing when the game died:
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			f, _ := os.Create("crash_loader.json")
+//			l.DumpState(f)
+//			f.Close()
+//			panic(r)
+//		}
+//	}()
+//
+// The history keeps the last 64 events.
+func (l *Loader) DumpState(w io.Writer) error {
+	dump := stateDump{
+		Platform: l.Platform,
+		Kinds:    []stateDumpKind{},
+		History:  make([]stateDumpHistory, 0, len(l.history)),
+	}
+	for _, kind := range l.allKinds() {
+		r := l.kindRegistry(kind)
+		ids := r.ids()
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Ints(ids)
+		k := stateDumpKind{
+			Kind:      kind.String(),
+			Resources: make([]stateDumpResource, len(ids)),
+		}
+		for i, id := range ids {
+			loaded := l.IsLoaded(Ref{Kind: kind, ID: id})
+			k.Resources[i] = stateDumpResource{ID: id, Path: r.infoPath(id), Loaded: loaded}
+			if loaded {
+				dump.Stats.Loaded++
+			}
+		}
+		dump.Stats.Registered += len(ids)
+		dump.Kinds = append(dump.Kinds, k)
+	}
+	dump.Stats.TextureMemory = l.TextureMemory()
+	for _, q := range l.loadQueue {
+		dump.Stats.Queued += len(q)
+	}
+
+	// The ring buffer is written from the oldest to the newest event.
+	for i := range l.history {
+		e := l.history[(l.historyPos+i)%len(l.history)]
+		h := stateDumpHistory{
+			Time:  e.time,
+			Event: e.typ.String(),
+			Kind:  e.ref.Kind.String(),
+			ID:    e.ref.ID,
+		}
+		if e.err != nil {
+			h.Error = e.err.Error()
+		}
+		dump.History = append(dump.History, h)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dump)
+}
@@ -0,0 +1,120 @@
+package resource
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// GainDecorator returns a stream decorator that multiplies
+// every sample by the given gain.
+// A gain of 0.5 makes the sound two times quieter, 2 makes it two times louder.
+// The resulting samples are clamped to avoid the overflow artifacts.
+func GainDecorator(gain float64) func(stream io.ReadSeeker) io.ReadSeeker {
+	return func(stream io.ReadSeeker) io.ReadSeeker {
+		return newEffectStream(stream, func(channel int, x float64) float64 {
+			return x * gain
+		}, nil)
+	}
+}
+
+// LowPassDecorator returns a stream decorator that applies a simple
+// one-pole low-pass filter with a given cutoff frequency (in Hz).
+// It can be used for the "underwater" or the "muffled" sound effects.
+//
+// The sampleRate should match the audio context sample rate.
+func LowPassDecorator(sampleRate int, cutoff float64) func(stream io.ReadSeeker) io.ReadSeeker {
+	dt := 1.0 / float64(sampleRate)
+	rc := 1.0 / (2 * math.Pi * cutoff)
+	alpha := dt / (rc + dt)
+	return func(stream io.ReadSeeker) io.ReadSeeker {
+		var prev [2]float64
+		return newEffectStream(stream, func(channel int, x float64) float64 {
+			y := prev[channel] + alpha*(x-prev[channel])
+			prev[channel] = y
+			return y
+		}, func() { prev = [2]float64{} })
+	}
+}
+
+// HighPassDecorator returns a stream decorator that applies a simple
+// one-pole high-pass filter with a given cutoff frequency (in Hz).
+// It can be used to make the sound "thin", like it's played by a radio.
+//
+// The sampleRate should match the audio context sample rate.
+func HighPassDecorator(sampleRate int, cutoff float64) func(stream io.ReadSeeker) io.ReadSeeker {
+	dt := 1.0 / float64(sampleRate)
+	rc := 1.0 / (2 * math.Pi * cutoff)
+	alpha := rc / (rc + dt)
+	return func(stream io.ReadSeeker) io.ReadSeeker {
+		var prevX [2]float64
+		var prevY [2]float64
+		return newEffectStream(stream, func(channel int, x float64) float64 {
+			y := alpha * (prevY[channel] + x - prevX[channel])
+			prevX[channel] = x
+			prevY[channel] = y
+			return y
+		}, func() {
+			prevX = [2]float64{}
+			prevY = [2]float64{}
+		})
+	}
+}
+
+// effectStream applies a per-sample function to the stream.
+//
+// The stream is expected to be in the Ebitengine audio format:
+// 16-bit signed little-endian stereo samples.
+type effectStream struct {
+	src   io.ReadSeeker
+	apply func(channel int, x float64) float64
+	reset func()
+}
+
+func newEffectStream(src io.ReadSeeker, apply func(channel int, x float64) float64, reset func()) *effectStream {
+	return &effectStream{src: src, apply: apply, reset: reset}
+}
+
+func (s *effectStream) Read(p []byte) (int, error) {
+	// Only process the complete frames (2 channels * 2 bytes).
+	p = p[:len(p)&^3]
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n, err := s.src.Read(p)
+	if rem := n % 4; rem != 0 {
+		// Read the rest of an incomplete frame.
+		m, fullErr := io.ReadFull(s.src, p[n:n+4-rem])
+		n += m
+		if fullErr != nil {
+			// The stream is truncated: drop the incomplete frame.
+			n -= n % 4
+			err = fullErr
+		}
+	}
+	for i := 0; i < n; i += 2 {
+		x := float64(int16(binary.LittleEndian.Uint16(p[i:]))) / math.MaxInt16
+		y := s.apply((i/2)%2, x)
+		y = math.Max(-1, math.Min(1, y))
+		binary.LittleEndian.PutUint16(p[i:], uint16(int16(y*math.MaxInt16)))
+	}
+	return n, err
+}
+
+func (s *effectStream) Seek(offset int64, whence int) (int64, error) {
+	if s.reset != nil {
+		s.reset()
+	}
+	return s.src.Seek(offset, whence)
+}
+
+// Length reports the underlying stream length.
+// This makes it possible to use the effect streams with Loop.
+func (s *effectStream) Length() int64 {
+	src, ok := s.src.(lengthStream)
+	if !ok {
+		panic(fmt.Sprintf("get %T stream length: it has no Length method", s.src))
+	}
+	return src.Length()
+}
@@ -0,0 +1,221 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrNotRegistered is reported for the IDs that have no associated info.
+	ErrNotRegistered = errors.New("resource is not registered")
+
+	// ErrOpen is reported when the resource data can't be opened or read.
+	ErrOpen = errors.New("can't open the resource")
+
+	// ErrDecode is reported when the resource data is malformed.
+	// This includes the checksum mismatches (see Loader.Checksums).
+	ErrDecode = errors.New("can't decode the resource")
+
+	// ErrCycle is reported when the resources depend on each other
+	// (see DependsOn and ImageInfo.PackChannels).
+	ErrCycle = errors.New("cycle")
+)
+
+// Error describes a resource operation failure.
+//
+// The load methods panic with this error value when the resource
+// is not registered or can't be opened, read or decoded.
+// Use TryLoad to get these errors without recovering the panics manually.
+//
+// The failure cause can be checked with errors.Is and one of
+// ErrNotRegistered, ErrOpen, ErrDecode and ErrCycle values:
+//
+//	if errors.Is(err, resource.ErrOpen) {
+//		// Show "missing DLC" message.
+//	}
+type Error struct {
+	// Op is a failed operation, like "open" or "decode".
+	Op string
+
+	// Kind and ID identify the resource.
+	Kind Kind
+	ID   int
+
+	// Path is the resource path (before its rewriting).
+	// It's empty for the unregistered resources.
+	Path string
+
+	Err error
+
+	// Debug contains the extra details that are included in the error message.
+	Debug DebugInfo
+}
+
+// DebugInfo describes the error context in a structured form.
+// The tools can use it to produce their own failure reports.
+type DebugInfo struct {
+	// NearestIDs are the registered IDs that are closest to the
+	// unregistered one (sorted in ascending order).
+	// It's only set for ErrNotRegistered errors.
+	NearestIDs []int
+
+	// Head contains the first bytes of the resource data
+	// that failed to decode.
+	Head []byte
+
+	// Offset is the data offset of the decoding error.
+	// It's -1 if the offset is unknown.
+	Offset int64
+}
+
+// maxDebugHeadLen is a max number of bytes stored in DebugInfo.Head.
+const maxDebugHeadLen = 16
+
+// maxNearestIDs is a max number of IDs stored in DebugInfo.NearestIDs.
+const maxNearestIDs = 3
+
+func newError(op string, ref Ref, path string, err error) *Error {
+	return &Error{
+		Op:    op,
+		Kind:  ref.Kind,
+		ID:    ref.ID,
+		Path:  path,
+		Err:   err,
+		Debug: DebugInfo{Offset: -1},
+	}
+}
+
+func (l *Loader) notRegisteredError(ref Ref) *Error {
+	e := newError("lookup", ref, "", ErrNotRegistered)
+	ids := l.registeredIDs(ref.Kind)
+	sort.Slice(ids, func(i, j int) bool {
+		di := absInt(ids[i] - ref.ID)
+		dj := absInt(ids[j] - ref.ID)
+		if di != dj {
+			return di < dj
+		}
+		return ids[i] < ids[j]
+	})
+	if len(ids) > maxNearestIDs {
+		ids = ids[:maxNearestIDs]
+	}
+	sort.Ints(ids)
+	e.Debug.NearestIDs = ids
+	return e
+}
+
+// decodeError creates a decoding error for the resource.
+// The data is the resource in-memory contents; if it's nil,
+// the resource is re-opened to collect the debug info.
+func (l *Loader) decodeError(op string, ref Ref, path string, data []byte, err error) *Error {
+	e := newError(op, ref, path, err)
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var schemaErr *SchemaError
+	switch {
+	case errors.As(err, &syntaxErr):
+		e.Debug.Offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		e.Debug.Offset = typeErr.Offset
+	case errors.As(err, &schemaErr):
+		e.Debug.Offset = schemaErr.Offset
+	}
+	if data == nil {
+		data = l.readDebugHead(ref, path)
+	}
+	if len(data) > maxDebugHeadLen {
+		data = data[:maxDebugHeadLen]
+	}
+	e.Debug.Head = append([]byte(nil), data...)
+	return e
+}
+
+func (l *Loader) readDebugHead(ref Ref, path string) (head []byte) {
+	// This is a best-effort attempt: it's OK to fail here.
+	defer func() {
+		if recover() != nil {
+			head = nil
+		}
+	}()
+	r := l.openAsset(ref, path)
+	defer r.Close()
+	head = make([]byte, maxDebugHeadLen)
+	n, _ := io.ReadFull(r, head)
+	return head[:n]
+}
+
+func (e *Error) Error() string {
+	var msg string
+	if e.Err == ErrNotRegistered {
+		msg = fmt.Sprintf("unregistered %s with id=%d", e.Kind, e.ID)
+	} else {
+		msg = fmt.Sprintf("%s %q %s: %v", e.Op, e.Path, e.Kind, e.Err)
+	}
+	var details []string
+	if len(e.Debug.NearestIDs) != 0 {
+		ids := make([]string, len(e.Debug.NearestIDs))
+		for i, id := range e.Debug.NearestIDs {
+			ids[i] = fmt.Sprint(id)
+		}
+		details = append(details, "nearest registered ids: "+strings.Join(ids, ", "))
+	}
+	if e.Debug.Offset >= 0 {
+		details = append(details, fmt.Sprintf("at offset %d", e.Debug.Offset))
+	}
+	if len(e.Debug.Head) != 0 {
+		details = append(details, fmt.Sprintf("data starts with % x", e.Debug.Head))
+	}
+	if len(details) != 0 {
+		msg += " (" + strings.Join(details, "; ") + ")"
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Is reports whether the error matches one of the
+// ErrOpen and ErrDecode values based on the failed operation.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrOpen:
+		return e.Op == "open" || e.Op == "read"
+	case ErrDecode:
+		return e.Op == "decode" || e.Op == "parse" || e.Op == "compile" || e.Op == "validate" || e.Op == "patch" || e.Op == "verify"
+	}
+	return false
+}
+
+// BatchError is returned by LoadBatch and ValidatePaths.
+// It contains all resource loading errors (see Error).
+type BatchError struct {
+	Errors []*Error
+}
+
+func (e *BatchError) Error() string {
+	lines := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		lines[i] = err.Error()
+	}
+	return fmt.Sprintf("%d resources failed to load:\n%s", len(e.Errors), strings.Join(lines, "\n"))
+}
+
+// Unwrap returns the individual errors.
+// It makes errors.Is and errors.As work for the wrapped errors (Go 1.20+).
+func (e *BatchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func absInt(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,141 @@
+package resource
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeErrorReadsOnce(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		load func(l *Loader)
+	}{
+		{"font", "not a font", func(l *Loader) {
+			l.FontRegistry.Set(1, FontInfo{Path: "a.ttf", Size: 12})
+			l.LoadFont(1)
+		}},
+		{"sdf font", "not a font", func(l *Loader) {
+			l.SDFFontRegistry.Set(1, SDFFontInfo{Path: "a.ttf", Size: 12})
+			l.LoadSDFFont(1)
+		}},
+		{"template", "{{ not a template", func(l *Loader) {
+			l.TemplateRegistry.Set(1, TemplateInfo{Path: "a.tmpl"})
+			l.LoadTemplate(1)
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			l.OpenAssetFunc = func(path string) io.ReadCloser {
+				return io.NopCloser(strings.NewReader(test.data))
+			}
+			opens := 0
+			l.Use(func(next OpenAssetFunc) OpenAssetFunc {
+				return func(path string) io.ReadCloser {
+					opens++
+					return next(path)
+				}
+			})
+
+			var err error
+			func() {
+				defer func() {
+					err, _ = recover().(error)
+				}()
+				test.load(l)
+			}()
+			var resErr *Error
+			if !errors.As(err, &resErr) {
+				t.Fatalf("expected a resource error, got %v", err)
+			}
+			if len(resErr.Debug.Head) == 0 {
+				t.Fatal("the error has no debug head")
+			}
+			if opens != 1 {
+				t.Fatalf("the resource was opened %d times", opens)
+			}
+		})
+	}
+}
+
+func TestLoadErrorKinds(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *Loader)
+		want  error
+	}{
+		{"checksum", func(l *Loader) {
+			l.Checksums = map[string]string{"a.txt": "00"}
+			l.RawRegistry.Set(1, RawInfo{Path: "a.txt"})
+		}, ErrDecode},
+		{"cycle", func(l *Loader) {
+			l.RawRegistry.Set(1, RawInfo{Path: "a.txt", DependsOn: []Ref{RawID(1).Ref()}})
+		}, ErrCycle},
+		{"variant", func(l *Loader) {
+			l.VariantFunc = func(ref Ref, info any) any { return "a.txt" }
+			l.RawRegistry.Set(1, RawInfo{Path: "a.txt"})
+		}, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			l.OpenAssetFunc = func(path string) io.ReadCloser {
+				return io.NopCloser(strings.NewReader("data"))
+			}
+			test.setup(l)
+			err := l.TryLoad(RawID(1).Ref())
+			var resErr *Error
+			if !errors.As(err, &resErr) {
+				t.Fatalf("expected a resource error, got %v", err)
+			}
+			if test.want != nil && !errors.Is(err, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, err)
+			}
+		})
+	}
+}
+
+func TestLoadBatchRecoversPanics(t *testing.T) {
+	l := NewLoader(nil)
+	l.OpenAssetFunc = func(path string) io.ReadCloser {
+		if path == "broken.txt" {
+			panic("storage is unavailable")
+		}
+		return io.NopCloser(strings.NewReader("data"))
+	}
+	l.RawRegistry.Set(1, RawInfo{Path: "broken.txt"})
+	l.RawRegistry.Set(2, RawInfo{Path: "ok.txt"})
+
+	err := l.LoadBatch([]Ref{RawID(1).Ref(), RawID(2).Ref()})
+	var batchErr *BatchError
+	if !errors.As(err, &batchErr) {
+		t.Fatalf("expected a batch error, got %v", err)
+	}
+	if len(batchErr.Errors) != 1 || batchErr.Errors[0].Op != "load" {
+		t.Fatalf("unexpected batch errors: %v", batchErr.Errors)
+	}
+	if !l.IsLoaded(RawID(2).Ref()) {
+		t.Fatal("raw 2 is not loaded after a failed batch item")
+	}
+}
+
+func TestDecodeAudioStreamUnknownFormat(t *testing.T) {
+	l := NewLoader(nil)
+	l.AudioRegistry.Set(1, AudioInfo{Path: "a.mp3", Data: []byte("data")})
+
+	var err error
+	func() {
+		defer func() {
+			err, _ = recover().(error)
+		}()
+		l.ValidateLoop(1, 0)
+	}()
+	if !errors.Is(err, ErrDecode) {
+		t.Fatalf("expected a decode error, got %v", err)
+	}
+}
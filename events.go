@@ -0,0 +1,179 @@
+package resource
+
+import "time"
+
+// EventType is a loader event type.
+// See Loader.Subscribe.
+type EventType int
+
+const (
+	// EventLoad is emitted when a resource is loaded (decoded and cached).
+	EventLoad EventType = iota
+
+	// EventUnload is emitted when a resource is unloaded (see Unload and Close).
+	EventUnload
+
+	// EventReload is emitted when a loaded resource data is replaced
+	// (see ReloadImage and SaveRaw).
+	EventReload
+
+	// EventError is emitted for the resource errors
+	// reported by TryLoad (and LoadBatch, which uses it).
+	EventError
+
+	// EventEvict is emitted when a resource is unloaded by the
+	// cache limits (see LRUCacheLimit and SoftCacheLimit).
+	EventEvict
+
+	// EventDeprecated is emitted when a resource is read by
+	// a deprecated path that is redirected (see PathRedirects).
+	// It's emitted only once per path.
+	EventDeprecated
+)
+
+func (typ EventType) String() string {
+	switch typ {
+	case EventLoad:
+		return "load"
+	case EventUnload:
+		return "unload"
+	case EventReload:
+		return "reload"
+	case EventError:
+		return "error"
+	case EventEvict:
+		return "evict"
+	case EventDeprecated:
+		return "deprecated"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a loader resource state change.
+type Event struct {
+	Type EventType
+
+	// Ref is the affected resource.
+	Ref Ref
+
+	// Path is the resource info path (it can be empty).
+	// For the EventDeprecated events, it's the deprecated path.
+	Path string
+
+	// NewPath is the path the deprecated Path is redirected to.
+	// It's only set for the EventDeprecated events.
+	NewPath string
+
+	// Duration is the resource loading time.
+	// It's only set for the EventLoad events of the resources
+	// that are read by their paths.
+	Duration time.Duration
+
+	// Bytes is the resource data size in bytes.
+	// It's only set for the images (their texture size)
+	// and for the raw resources.
+	Bytes int64
+
+	// Err is set for the EventError events.
+	Err *Error
+}
+
+type eventSubscriber struct {
+	id int
+	fn func(Event)
+}
+
+// Subscribe adds a function that is called for every loader event.
+// It allows the debug tools and editors to mirror the loader state without polling.
+//
+// The function is called synchronously, right after the state change.
+// It should not load or unload the resources.
+//
+// The returned function removes the subscription.
+func (l *Loader) Subscribe(fn func(Event)) (unsubscribe func()) {
+	l.nextSubscriberID++
+	id := l.nextSubscriberID
+	l.subscribers = append(l.subscribers, eventSubscriber{id: id, fn: fn})
+	return func() {
+		for i, s := range l.subscribers {
+			if s.id == id {
+				l.subscribers = append(l.subscribers[:i:i], l.subscribers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+func (l *Loader) emit(typ EventType, ref Ref) {
+	l.recordHistory(typ, ref, nil)
+	if len(l.subscribers) == 0 {
+		return
+	}
+	e := l.newEvent(typ, ref)
+	if typ == EventLoad {
+		if start, ok := l.loadStarts[ref]; ok {
+			e.Duration = time.Since(start)
+			delete(l.loadStarts, ref)
+		}
+	}
+	switch ref.Kind {
+	case KindImage:
+		e.Bytes = l.images[ImageID(ref.ID)].textureBytes()
+	case KindRaw:
+		e.Bytes = int64(len(l.raws[RawID(ref.ID)].Data))
+	}
+	for _, s := range l.subscribers {
+		s.fn(e)
+	}
+}
+
+func (l *Loader) emitError(ref Ref, err *Error) {
+	ref = l.resolveRef(ref)
+	l.recordHistory(EventError, ref, err)
+	if len(l.subscribers) == 0 {
+		return
+	}
+	// The failed loads are never finished.
+	for start := range l.loadStarts {
+		delete(l.loadStarts, start)
+	}
+	e := l.newEvent(EventError, ref)
+	e.Err = err
+	for _, s := range l.subscribers {
+		s.fn(e)
+	}
+}
+
+func (l *Loader) emitDeprecated(ref Ref, path, newPath string) {
+	l.recordHistory(EventDeprecated, ref, nil)
+	if len(l.subscribers) == 0 {
+		return
+	}
+	e := Event{Type: EventDeprecated, Ref: ref, Path: path, NewPath: newPath}
+	for _, s := range l.subscribers {
+		s.fn(e)
+	}
+}
+
+func (l *Loader) newEvent(typ EventType, ref Ref) Event {
+	e := Event{Type: typ, Ref: ref}
+	if r := l.kindRegistry(ref.Kind); r != nil {
+		e.Path = r.infoPath(ref.ID)
+	}
+	return e
+}
+
+// markLoadStart records the resource loading start time
+// for the EventLoad duration.
+func (l *Loader) markLoadStart(ref Ref) {
+	if len(l.subscribers) == 0 {
+		return
+	}
+	if l.loadStarts == nil {
+		l.loadStarts = make(map[Ref]time.Time)
+	}
+	if _, ok := l.loadStarts[ref]; !ok {
+		l.loadStarts[ref] = time.Now()
+	}
+}
@@ -0,0 +1,226 @@
+package resource
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// filteredFace is a font face that hides the glyphs
+// outside of the specified ranges.
+// See FontInfo.GlyphFilter.
+type filteredFace struct {
+	font.Face
+	ranges []*unicode.RangeTable
+}
+
+func (f *filteredFace) Glyph(dot fixed.Point26_6, r rune) (dr image.Rectangle, mask image.Image, maskp image.Point, advance fixed.Int26_6, ok bool) {
+	if !unicode.In(r, f.ranges...) {
+		return image.Rectangle{}, nil, image.Point{}, 0, false
+	}
+	return f.Face.Glyph(dot, r)
+}
+
+func (f *filteredFace) GlyphBounds(r rune) (bounds fixed.Rectangle26_6, advance fixed.Int26_6, ok bool) {
+	if !unicode.In(r, f.ranges...) {
+		return fixed.Rectangle26_6{}, 0, false
+	}
+	return f.Face.GlyphBounds(r)
+}
+
+func (f *filteredFace) GlyphAdvance(r rune) (advance fixed.Int26_6, ok bool) {
+	if !unicode.In(r, f.ranges...) {
+		return 0, false
+	}
+	return f.Face.GlyphAdvance(r)
+}
+
+// uniqueRunes returns a string that contains every printable rune of data once.
+func uniqueRunes(data []byte) string {
+	seen := make(map[rune]struct{})
+	var sb strings.Builder
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			continue
+		}
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// errFontVariations is reported when a variable font
+// instance is requested (see FontInfo.Axes).
+var errFontVariations = errors.New("font variations are not supported")
+
+// fontAxis is a variable font design axis from the fvar table.
+type fontAxis struct {
+	tag string
+	min float64
+	def float64
+	max float64
+}
+
+// fontInstance is a named instance from the fvar table.
+type fontInstance struct {
+	nameID sfnt.NameID
+	coords []float64
+}
+
+// fontCoords are the variable font axis values by their tags.
+type fontCoords map[string]float64
+
+func (c fontCoords) String() string {
+	tags := make([]string, 0, len(c))
+	for tag := range c {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = fmt.Sprintf("%s=%g", tag, c[tag])
+	}
+	return strings.Join(parts, ",")
+}
+
+// fontVariation resolves the FontInfo Instance and Axes
+// to the validated axis coordinates.
+func fontVariation(f *opentype.Font, data []byte, instance string, axes map[string]float64) (fontCoords, error) {
+	fvar := findFontTable(data, "fvar")
+	if fvar == nil {
+		return nil, errors.New("the font has no variation axes (fvar table)")
+	}
+	fontAxes, instances, err := parseFontVariations(fvar)
+	if err != nil {
+		return nil, err
+	}
+	coords := make(fontCoords, len(fontAxes))
+	for _, a := range fontAxes {
+		coords[a.tag] = a.def
+	}
+	if instance != "" {
+		found := false
+		var buf sfnt.Buffer
+		for _, inst := range instances {
+			name, err := f.Name(&buf, inst.nameID)
+			if err != nil || name != instance {
+				continue
+			}
+			for i, a := range fontAxes {
+				coords[a.tag] = inst.coords[i]
+			}
+			found = true
+			break
+		}
+		if !found {
+			return nil, fmt.Errorf("the font has no %q named instance", instance)
+		}
+	}
+	for tag, v := range axes {
+		i := -1
+		for j, a := range fontAxes {
+			if a.tag == tag {
+				i = j
+				break
+			}
+		}
+		if i == -1 {
+			return nil, fmt.Errorf("the font has no %q axis", tag)
+		}
+		if a := fontAxes[i]; v < a.min || v > a.max {
+			return nil, fmt.Errorf("%s=%g is out of the axis range [%g, %g]", tag, v, a.min, a.max)
+		}
+		coords[tag] = v
+	}
+	return coords, nil
+}
+
+// findFontTable returns the sfnt table data by its tag.
+// It returns nil if there is no such table.
+func findFontTable(data []byte, tag string) []byte {
+	const headerSize = 12
+	const recordSize = 16
+	if len(data) < headerSize {
+		return nil
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := 0; i < numTables; i++ {
+		record := data[headerSize+i*recordSize:]
+		if len(record) < recordSize {
+			return nil
+		}
+		if string(record[:4]) != tag {
+			continue
+		}
+		offset := uint64(binary.BigEndian.Uint32(record[8:]))
+		length := uint64(binary.BigEndian.Uint32(record[12:]))
+		if offset+length > uint64(len(data)) {
+			return nil
+		}
+		return data[offset : offset+length]
+	}
+	return nil
+}
+
+// parseFontVariations parses the fvar table axes and named instances.
+func parseFontVariations(fvar []byte) ([]fontAxis, []fontInstance, error) {
+	const headerSize = 16
+	const axisSize = 20
+	errMalformed := errors.New("malformed fvar table")
+	if len(fvar) < headerSize {
+		return nil, nil, errMalformed
+	}
+	axesOffset := int(binary.BigEndian.Uint16(fvar[4:]))
+	axisCount := int(binary.BigEndian.Uint16(fvar[8:]))
+	axisRecordSize := int(binary.BigEndian.Uint16(fvar[10:]))
+	instanceCount := int(binary.BigEndian.Uint16(fvar[12:]))
+	instanceSize := int(binary.BigEndian.Uint16(fvar[14:]))
+	if axisRecordSize < axisSize || instanceSize < 4+4*axisCount {
+		return nil, nil, errMalformed
+	}
+	instancesOffset := axesOffset + axisCount*axisRecordSize
+	if instancesOffset+instanceCount*instanceSize > len(fvar) {
+		return nil, nil, errMalformed
+	}
+	fixedValue := func(b []byte) float64 {
+		return float64(int32(binary.BigEndian.Uint32(b))) / 0x10000
+	}
+	axes := make([]fontAxis, axisCount)
+	for i := range axes {
+		record := fvar[axesOffset+i*axisRecordSize:]
+		axes[i] = fontAxis{
+			tag: string(record[:4]),
+			min: fixedValue(record[4:]),
+			def: fixedValue(record[8:]),
+			max: fixedValue(record[12:]),
+		}
+	}
+	instances := make([]fontInstance, instanceCount)
+	for i := range instances {
+		record := fvar[instancesOffset+i*instanceSize:]
+		inst := fontInstance{
+			nameID: sfnt.NameID(binary.BigEndian.Uint16(record)),
+			coords: make([]float64, axisCount),
+		}
+		for j := range inst.coords {
+			inst.coords[j] = fixedValue(record[4+4*j:])
+		}
+		instances[i] = inst
+	}
+	return axes, instances, nil
+}
@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+	"unicode"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// makeTestVariableFont adds an fvar table with a single "wght" axis
+// and a "Regular" (name ID 2) instance to the Go Regular font.
+func makeTestVariableFont() []byte {
+	src := goregular.TTF
+	numTables := int(binary.BigEndian.Uint16(src[4:]))
+	dirEnd := 12 + numTables*16
+
+	fvar := make([]byte, 16+20+8)
+	binary.BigEndian.PutUint16(fvar[0:], 1)
+	binary.BigEndian.PutUint16(fvar[4:], 16) // axesArrayOffset
+	binary.BigEndian.PutUint16(fvar[8:], 1)  // axisCount
+	binary.BigEndian.PutUint16(fvar[10:], 20)
+	binary.BigEndian.PutUint16(fvar[12:], 1) // instanceCount
+	binary.BigEndian.PutUint16(fvar[14:], 8)
+	axis := fvar[16:]
+	copy(axis, "wght")
+	binary.BigEndian.PutUint32(axis[4:], 100<<16)
+	binary.BigEndian.PutUint32(axis[8:], 400<<16)
+	binary.BigEndian.PutUint32(axis[12:], 900<<16)
+	instance := fvar[36:]
+	binary.BigEndian.PutUint16(instance, 2)
+	binary.BigEndian.PutUint32(instance[4:], 400<<16)
+
+	fvarRecord := make([]byte, 16)
+	copy(fvarRecord, "fvar")
+	binary.BigEndian.PutUint32(fvarRecord[8:], uint32(len(src)+16))
+	binary.BigEndian.PutUint32(fvarRecord[12:], uint32(len(fvar)))
+
+	// The table records are sorted by their tags.
+	var data []byte
+	data = append(data, src[:12]...)
+	binary.BigEndian.PutUint16(data[4:], uint16(numTables+1))
+	for i := 0; i < numTables; i++ {
+		record := append([]byte(nil), src[12+i*16:12+(i+1)*16]...)
+		binary.BigEndian.PutUint32(record[8:], binary.BigEndian.Uint32(record[8:])+16)
+		if fvarRecord != nil && string(record[:4]) > "fvar" {
+			data = append(data, fvarRecord...)
+			fvarRecord = nil
+		}
+		data = append(data, record...)
+	}
+	data = append(data, fvarRecord...)
+	data = append(data, src[dirEnd:]...)
+	data = append(data, fvar...)
+	return data
+}
+
+func TestFontVariations(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		instance string
+		axes     map[string]float64
+		valid    bool
+	}{
+		{"static font", goregular.TTF, "", map[string]float64{"wght": 700}, false},
+		{"instance", makeTestVariableFont(), "Regular", nil, true},
+		{"axis", makeTestVariableFont(), "", map[string]float64{"wght": 700}, true},
+		{"unknown instance", makeTestVariableFont(), "Black", nil, false},
+		{"unknown axis", makeTestVariableFont(), "", map[string]float64{"wdth": 75}, false},
+		{"out of range", makeTestVariableFont(), "", map[string]float64{"wght": 1000}, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			var warnings []error
+			l.WarningFunc = func(err error) {
+				warnings = append(warnings, err)
+			}
+			l.FontRegistry.Set(1, FontInfo{
+				Path:     "font.ttf",
+				Data:     test.data,
+				Size:     12,
+				Instance: test.instance,
+				Axes:     test.axes,
+			})
+			err := l.TryLoad(FontID(1).Ref())
+			if !test.valid {
+				var resErr *Error
+				if !errors.As(err, &resErr) || resErr.Op != "validate" {
+					t.Fatalf("expected a validation error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(warnings) != 1 || !errors.Is(warnings[0], errFontVariations) {
+				t.Fatalf("expected a font variations warning, got %v", warnings)
+			}
+		})
+	}
+}
+
+func TestFontGlyphFilter(t *testing.T) {
+	l := NewLoader(nil)
+	l.FontRegistry.Set(1, FontInfo{
+		Path:        "font.ttf",
+		Data:        goregular.TTF,
+		Size:        12,
+		GlyphFilter: []*unicode.RangeTable{unicode.Digit},
+	})
+	face := l.LoadFont(1).Face
+	if _, ok := face.GlyphAdvance('7'); !ok {
+		t.Fatal("the digit glyph is filtered")
+	}
+	if _, ok := face.GlyphAdvance('a'); ok {
+		t.Fatal("the letter glyph is not filtered")
+	}
+}
@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// imageFrameVariant is an image variant key for the split frames.
+type imageFrameVariant int
+
+// NumImageFrames returns the number of frames in the sprite sheet
+// associated with a given key.
+// The frame grid is described by the ImageInfo FrameWidth and FrameHeight.
+func (l *Loader) NumImageFrames(id ImageID) int {
+	img := l.LoadImage(id)
+	columns, rows := l.imageFrameGrid(img)
+	return columns * rows
+}
+
+// LoadImageFrame returns a sprite sheet frame as an independent image.
+// Unlike the SubImage frames, it has its own texture.
+// The frames are numbered in the row-major order, starting from 0.
+//
+// This is useful for the renderers that require a standalone texture
+// per frame. Every frame is created only once and cached.
+// Unloading the original image unloads its frames too.
+// See also ImageInfo.SplitFrames.
+func (l *Loader) LoadImageFrame(id ImageID, index int) Image {
+	id = l.ImageRegistry.resolve(id)
+	key := imageVariantKey{id: id, variant: imageFrameVariant(index)}
+	img, ok := l.imageVariant(key)
+	if !ok {
+		src := l.LoadImage(id)
+		if src.Tiled != nil {
+			panic(fmt.Sprintf("image with id=%d is tiled, it can't be split", id))
+		}
+		columns, rows := l.imageFrameGrid(src)
+		if index < 0 || index >= columns*rows {
+			panic(fmt.Sprintf("image with id=%d has no frame %d (%d frames in total)", id, index, columns*rows))
+		}
+		img = l.newImageFrame(id, src, index, columns)
+		l.storeImageVariant(key, img)
+	}
+	return img
+}
+
+// splitImageFrames creates all frames of the loaded sprite sheet.
+func (l *Loader) splitImageFrames(id ImageID, src Image) {
+	if src.Tiled != nil {
+		panic(fmt.Sprintf("image with id=%d is tiled, it can't be split", id))
+	}
+	columns, rows := l.imageFrameGrid(src)
+	for i := 0; i < columns*rows; i++ {
+		key := imageVariantKey{id: id, variant: imageFrameVariant(i)}
+		if _, ok := l.imageVariants[key]; !ok {
+			l.storeImageVariant(key, l.newImageFrame(id, src, i, columns))
+		}
+	}
+}
+
+func (l *Loader) newImageFrame(id ImageID, src Image, index, columns int) Image {
+	fw, fh := src.DefaultFrameWidth, src.DefaultFrameHeight
+	x := (index % columns) * fw
+	y := (index / columns) * fh
+	data := ebiten.NewImageWithOptions(image.Rect(0, 0, fw, fh), &ebiten.NewImageOptions{
+		Unmanaged: l.ImageRegistry.mapping[id].Unmanaged,
+	})
+	data.DrawImage(src.Data.SubImage(image.Rect(x, y, x+fw, y+fh)).(*ebiten.Image), nil)
+	return Image{
+		ID:                 id,
+		Data:               data,
+		DefaultFrameWidth:  fw,
+		DefaultFrameHeight: fh,
+	}
+}
+
+func (l *Loader) imageFrameGrid(img Image) (columns, rows int) {
+	if img.DefaultFrameWidth <= 0 || img.DefaultFrameHeight <= 0 {
+		panic(fmt.Sprintf("image with id=%d has no frame size", img.ID))
+	}
+	w, h := img.Data.Size()
+	return w / img.DefaultFrameWidth, h / img.DefaultFrameHeight
+}
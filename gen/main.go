@@ -0,0 +1,261 @@
+// Command gen scans an assets directory and generates typed resource
+// ID constants along with the registration code for them.
+//
+// It's intended to be used via go:generate:
+//
+//	//go:generate go run github.com/quasilyte/ebitengine-resource/gen -dir ../assets -pkg assets -o resources_gen.go
+//
+// Every file gets an ID constant named after its kind and its path.
+// For instance, "ui/button_hover.png" becomes ImageUiButtonHover.
+// The resource kind is inferred from the file extension
+// (for instance, only the ".kage" files are registered as shaders);
+// unrecognized files are registered as Raw resources.
+// The paths that map to the <Kind>None names (like "none.png")
+// are rejected as these names are used for the zero IDs.
+//
+// The generated file contains a RegisterResources(l *resource.Loader)
+// function that binds all generated IDs to their infos.
+//
+// The -tiers flag assigns the progressive loading tiers (see resource.Tier)
+// by the asset directories:
+//
+//	-tiers ui:essential,fonts:essential,music:standard,hd:highres
+//
+// A file belongs to the tier of its longest matching directory;
+// the files outside of the listed directories have no tier.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type resourceKind struct {
+	name     string // Used as both constant prefix and type name prefix
+	registry string
+	files    []assetFile
+}
+
+type assetFile struct {
+	constName string
+	path      string
+	size      int64
+	tier      string
+}
+
+// tierConsts maps the -tiers flag tier names to their constants.
+var tierConsts = map[string]string{
+	"essential": "resource.TierEssential",
+	"standard":  "resource.TierStandard",
+	"highres":   "resource.TierHighRes",
+}
+
+func main() {
+	log.SetFlags(0)
+
+	dir := flag.String("dir", "", "assets root directory to scan")
+	pkg := flag.String("pkg", "", "generated file package name")
+	output := flag.String("o", "resources_gen.go", "output file name")
+	prefix := flag.String("prefix", "", "a prefix to add to every generated resource path")
+	tiersFlag := flag.String("tiers", "", "comma-separated dir:tier pairs, the tiers are essential, standard and highres")
+	flag.Parse()
+
+	if *dir == "" {
+		log.Fatal("-dir argument can't be empty")
+	}
+	if *pkg == "" {
+		log.Fatal("-pkg argument can't be empty")
+	}
+
+	tiers, err := parseTiers(*tiersFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := generate(*dir, *pkg, *prefix, tiers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parseTiers parses the -tiers flag value into a dir => tier constant map.
+func parseTiers(s string) (map[string]string, error) {
+	tiers := make(map[string]string)
+	if s == "" {
+		return tiers, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		dir, tier, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid -tiers pair %q: expected dir:tier", pair)
+		}
+		c, ok := tierConsts[tier]
+		if !ok {
+			return nil, fmt.Errorf("invalid -tiers pair %q: unknown tier %q", pair, tier)
+		}
+		tiers[strings.Trim(filepath.ToSlash(dir), "/")] = c
+	}
+	return tiers, nil
+}
+
+// fileTier returns the tier constant of the longest directory that contains path.
+// It returns an empty string if the file has no tier.
+func fileTier(tiers map[string]string, path string) string {
+	tier := ""
+	longest := -1
+	for dir, c := range tiers {
+		if (dir == "" || strings.HasPrefix(path, dir+"/")) && len(dir) > longest {
+			tier = c
+			longest = len(dir)
+		}
+	}
+	return tier
+}
+
+func generate(dir, pkg, prefix string, tiers map[string]string) ([]byte, error) {
+	kinds := []*resourceKind{
+		{name: "Image", registry: "ImageRegistry"},
+		{name: "Audio", registry: "AudioRegistry"},
+		{name: "Font", registry: "FontRegistry"},
+		{name: "Shader", registry: "ShaderRegistry"},
+		{name: "Raw", registry: "RawRegistry"},
+	}
+	kindByExt := map[string]*resourceKind{
+		".png":  kinds[0],
+		".jpg":  kinds[0],
+		".jpeg": kinds[0],
+		".gif":  kinds[0],
+		".ogg":  kinds[1],
+		".wav":  kinds[1],
+		// The loader can't decode ".mp3" files without a CustomAudioLoader,
+		// so they're registered as Raw resources.
+		".ttf":  kinds[2],
+		".otf":  kinds[2],
+		".kage": kinds[3],
+	}
+
+	constNames := make(map[string]string)
+	// The <Kind>None constants are reserved for the zero IDs.
+	for _, k := range kinds {
+		constNames[k.name+"None"] = ""
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && path != dir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		kind := kindByExt[strings.ToLower(filepath.Ext(rel))]
+		if kind == nil {
+			kind = kinds[4]
+		}
+		constName := kind.name + identFromPath(rel)
+		if otherPath, ok := constNames[constName]; ok {
+			if otherPath == "" {
+				return fmt.Errorf("%q maps to %s, which is reserved for the zero ID", rel, constName)
+			}
+			return fmt.Errorf("%q and %q both map to %s", otherPath, rel, constName)
+		}
+		constNames[constName] = rel
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		kind.files = append(kind.files, assetFile{
+			constName: constName,
+			path:      prefix + rel,
+			size:      info.Size(),
+			tier:      fileTier(tiers, rel),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by ebitengine-resource/gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import resource \"github.com/quasilyte/ebitengine-resource\"\n\n")
+
+	for _, k := range kinds {
+		if len(k.files) == 0 {
+			continue
+		}
+		sort.Slice(k.files, func(i, j int) bool {
+			return k.files[i].path < k.files[j].path
+		})
+		buf.WriteString("const (\n")
+		fmt.Fprintf(&buf, "%sNone resource.%sID = iota\n", k.name, k.name)
+		for _, f := range k.files {
+			fmt.Fprintf(&buf, "%s\n", f.constName)
+		}
+		buf.WriteString(")\n\n")
+	}
+
+	buf.WriteString("// RegisterResources binds all generated IDs to their infos.\n")
+	buf.WriteString("func RegisterResources(l *resource.Loader) {\n")
+	for _, k := range kinds {
+		if len(k.files) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "l.%s.Assign(map[resource.%sID]resource.%sInfo{\n", k.registry, k.name, k.name)
+		for _, f := range k.files {
+			if f.tier != "" {
+				fmt.Fprintf(&buf, "%s: {Path: %q, FileSize: %d, Tier: %s},\n", f.constName, f.path, f.size, f.tier)
+			} else {
+				fmt.Fprintf(&buf, "%s: {Path: %q, FileSize: %d},\n", f.constName, f.path, f.size)
+			}
+		}
+		buf.WriteString("})\n")
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, errors.New("format generated code: " + err.Error())
+	}
+	return src, nil
+}
+
+// identFromPath converts a slash-separated file path into
+// a CamelCase identifier suffix.
+// It's always used with a kind prefix, so leading digits are not a problem.
+// The file extension is not included.
+func identFromPath(path string) string {
+	path = strings.TrimSuffix(path, filepath.Ext(path))
+	parts := strings.FieldsFunc(path, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var sb strings.Builder
+	for _, p := range parts {
+		runes := []rune(p)
+		runes[0] = unicode.ToUpper(runes[0])
+		sb.WriteString(string(runes))
+	}
+	return sb.String()
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileTier(t *testing.T) {
+	tiers, err := parseTiers("ui:essential, ui/hd:highres,music:standard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"ui/button.png", "resource.TierEssential"},
+		{"ui/hd/button.png", "resource.TierHighRes"},
+		{"music/theme.ogg", "resource.TierStandard"},
+		{"musicbox.ogg", ""},
+		{"sfx/click.wav", ""},
+	}
+	for _, test := range tests {
+		if have := fileTier(tiers, test.path); have != test.want {
+			t.Errorf("fileTier(%q): have %q, want %q", test.path, have, test.want)
+		}
+	}
+}
+
+func TestParseTiersErrors(t *testing.T) {
+	for _, s := range []string{"ui", "ui:ultra", "ui:essential,music"} {
+		if _, err := parseTiers(s); err == nil {
+			t.Errorf("parseTiers(%q): expected an error", s)
+		}
+	}
+}
+
+func TestGenerateTiers(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{"ui/button.png", "music/theme.ogg"}
+	for _, f := range files {
+		writeTestAsset(t, dir, f)
+	}
+	tiers, err := parseTiers("ui:essential")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(dir, "assets", "", tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`ImageUiButton: {Path: "ui/button.png", FileSize: 4, Tier: resource.TierEssential},`,
+		`AudioMusicTheme: {Path: "music/theme.ogg", FileSize: 4},`,
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code doesn't contain %q:\n%s", want, src)
+		}
+	}
+}
+
+func TestGenerateKinds(t *testing.T) {
+	tests := []struct {
+		file string
+		want string
+	}{
+		{"bg.png", `ImageBg: {Path: "bg.png", FileSize: 4},`},
+		{"sfx/jump.WAV", `AudioSfxJump: {Path: "sfx/jump.WAV", FileSize: 4},`},
+		{"music/theme.mp3", `RawMusicTheme: {Path: "music/theme.mp3", FileSize: 4},`},
+		{"fonts/mono.ttf", `FontFontsMono: {Path: "fonts/mono.ttf", FileSize: 4},`},
+		{"shaders/blur.kage", `ShaderShadersBlur: {Path: "shaders/blur.kage", FileSize: 4},`},
+		{"shaders/util.go", `RawShadersUtil: {Path: "shaders/util.go", FileSize: 4},`},
+		{"levels/1.json", `RawLevels1: {Path: "levels/1.json", FileSize: 4},`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTestAsset(t, dir, test.file)
+			src, err := generate(dir, "assets", "", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(src), test.want) {
+				t.Fatalf("generated code doesn't contain %q:\n%s", test.want, src)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	tests := []struct {
+		files []string
+		want  string
+	}{
+		{[]string{"none.png"}, `"none.png" maps to ImageNone, which is reserved for the zero ID`},
+		{[]string{"None.wav"}, `"None.wav" maps to AudioNone, which is reserved for the zero ID`},
+		{[]string{"a_b.png", "a/b.png"}, "both map to ImageAB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.files[0], func(t *testing.T) {
+			dir := t.TempDir()
+			for _, f := range test.files {
+				writeTestAsset(t, dir, f)
+			}
+			_, err := generate(dir, "assets", "", nil)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("error mismatch:\nhave: %v\nwant: %q", err, test.want)
+			}
+		})
+	}
+}
+
+func writeTestAsset(t *testing.T, dir, name string) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
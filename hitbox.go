@@ -0,0 +1,107 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+)
+
+// Hitbox is a named collision box of the animation frame.
+// The box coordinates are relative to the frame origin.
+type Hitbox struct {
+	// Name is a box kind, like "hit" or "hurt".
+	Name string
+
+	Rect image.Rectangle
+}
+
+type hitboxRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+func (r hitboxRect) rect() image.Rectangle {
+	return image.Rect(r.X, r.Y, r.X+r.W, r.Y+r.H)
+}
+
+type hitboxesDocument struct {
+	// The simple sidecar format.
+	Frames json.RawMessage `json:"frames"`
+
+	// The Aseprite JSON export format.
+	Meta *struct {
+		Slices []struct {
+			Name string `json:"name"`
+			Keys []struct {
+				Frame  int        `json:"frame"`
+				Bounds hitboxRect `json:"bounds"`
+			} `json:"keys"`
+		} `json:"slices"`
+	} `json:"meta"`
+}
+
+func decodeHitboxes(r io.Reader) ([][]Hitbox, error) {
+	var doc hitboxesDocument
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if doc.Meta != nil {
+		return decodeAsepriteHitboxes(doc)
+	}
+	var frames [][]struct {
+		Name string `json:"name"`
+		hitboxRect
+	}
+	if err := json.Unmarshal(doc.Frames, &frames); err != nil {
+		return nil, fmt.Errorf("decode frames: %w", err)
+	}
+	result := make([][]Hitbox, len(frames))
+	for i, boxes := range frames {
+		for _, b := range boxes {
+			result[i] = append(result[i], Hitbox{Name: b.Name, Rect: b.rect()})
+		}
+	}
+	return result, nil
+}
+
+func decodeAsepriteHitboxes(doc hitboxesDocument) ([][]Hitbox, error) {
+	// Aseprite exports frames either as an array or as an object
+	// keyed by the frame file names (in the frame order).
+	var numFrames int
+	var framesArray []json.RawMessage
+	var framesMap map[string]json.RawMessage
+	if err := json.Unmarshal(doc.Frames, &framesArray); err == nil {
+		numFrames = len(framesArray)
+	} else if err := json.Unmarshal(doc.Frames, &framesMap); err == nil {
+		numFrames = len(framesMap)
+	} else {
+		return nil, errors.New("decode frames: array or object expected")
+	}
+
+	result := make([][]Hitbox, numFrames)
+	for _, slice := range doc.Meta.Slices {
+		keys := slice.Keys
+		sort.SliceStable(keys, func(i, j int) bool {
+			return keys[i].Frame < keys[j].Frame
+		})
+		// A slice key applies to all frames until the next key.
+		for i, k := range keys {
+			if k.Frame < 0 || k.Frame >= numFrames {
+				return nil, fmt.Errorf("slice %q: key frame %d is out of range", slice.Name, k.Frame)
+			}
+			end := numFrames
+			if i+1 < len(keys) {
+				end = keys[i+1].Frame
+			}
+			for frame := k.Frame; frame < end; frame++ {
+				result[frame] = append(result[frame], Hitbox{Name: slice.Name, Rect: k.Bounds.rect()})
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Icon is an IconSet entry.
+// The icon is either a font glyph or an atlas region.
+type Icon struct {
+	// Rune is the icon font glyph; it's 0 for the atlas icons.
+	Rune rune
+
+	// Rect is the icon atlas region; it's empty for the font icons.
+	Rect image.Rectangle
+}
+
+// parseIconRune parses "U+E001" and the single-rune strings.
+func parseIconRune(s string) (rune, error) {
+	if hex := strings.TrimPrefix(s, "U+"); hex != s {
+		v, err := strconv.ParseUint(hex, 16, 32)
+		if err != nil || !utf8.ValidRune(rune(v)) {
+			return 0, fmt.Errorf("bad code point %q", s)
+		}
+		return rune(v), nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError || size != len(s) {
+		return 0, fmt.Errorf("expected a single rune or U+XXXX code point, found %q", s)
+	}
+	return r, nil
+}
+
+func decodeIconSet(r io.Reader, info IconSetInfo) (map[string]Icon, error) {
+	var entries map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		names = append(names, name)
+	}
+	// Sort the names to report the errors deterministically.
+	sort.Strings(names)
+
+	icons := make(map[string]Icon, len(entries))
+	for _, name := range names {
+		var s string
+		var rect []int
+		switch {
+		case json.Unmarshal(entries[name], &s) == nil:
+			if info.Font == 0 {
+				return nil, fmt.Errorf("%q: font icon requires IconSetInfo.Font", name)
+			}
+			r, err := parseIconRune(s)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %w", name, err)
+			}
+			icons[name] = Icon{Rune: r}
+		case json.Unmarshal(entries[name], &rect) == nil && len(rect) == 4:
+			if info.Atlas == 0 {
+				return nil, fmt.Errorf("%q: atlas icon requires IconSetInfo.Atlas", name)
+			}
+			if rect[2] <= 0 || rect[3] <= 0 {
+				return nil, fmt.Errorf("%q: empty atlas region", name)
+			}
+			icons[name] = Icon{Rect: image.Rect(rect[0], rect[1], rect[0]+rect[2], rect[1]+rect[3])}
+		default:
+			return nil, fmt.Errorf("%q: expected a rune string or a [x, y, width, height] region", name)
+		}
+	}
+	return icons, nil
+}
@@ -0,0 +1,139 @@
+package resource
+
+import (
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIconRune(t *testing.T) {
+	tests := []struct {
+		s    string
+		want rune
+		err  string
+	}{
+		{s: "A", want: 'A'},
+		{s: "★", want: '★'},
+		{s: "U+E001", want: 0xE001},
+		{s: "U+e001", want: 0xE001},
+		{s: "U+1F600", want: 0x1F600},
+		{s: "", err: `expected a single rune or U+XXXX code point, found ""`},
+		{s: "AB", err: `expected a single rune or U+XXXX code point, found "AB"`},
+		{s: "\xff", err: `expected a single rune or U+XXXX code point, found "\xff"`},
+		{s: "U+", err: `bad code point "U+"`},
+		{s: "U+XYZ", err: `bad code point "U+XYZ"`},
+		{s: "U+D800", err: `bad code point "U+D800"`},
+		{s: "U+110000", err: `bad code point "U+110000"`},
+	}
+
+	for _, test := range tests {
+		have, err := parseIconRune(test.s)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("parse %q:\nhave error: %v\nwant error: %q", test.s, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parse %q: %v", test.s, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("parse %q: have %U, want %U", test.s, have, test.want)
+		}
+	}
+}
+
+func TestDecodeIconSet(t *testing.T) {
+	both := IconSetInfo{Font: 1, Atlas: 1}
+	tests := []struct {
+		name string
+		data string
+		info IconSetInfo
+		want map[string]Icon
+		err  string
+	}{
+		{
+			name: "empty",
+			data: `{}`,
+			want: map[string]Icon{},
+		},
+		{
+			name: "mixed",
+			data: `{"ok": "U+E001", "star": "★", "coin": [16, 0, 16, 8]}`,
+			info: both,
+			want: map[string]Icon{
+				"ok":   {Rune: 0xE001},
+				"star": {Rune: '★'},
+				"coin": {Rect: image.Rect(16, 0, 32, 8)},
+			},
+		},
+		{
+			name: "syntax",
+			data: `{"ok": "A"`,
+			info: both,
+			err:  "unexpected EOF",
+		},
+		{
+			name: "no font",
+			data: `{"ok": "A"}`,
+			info: IconSetInfo{Atlas: 1},
+			err:  `"ok": font icon requires IconSetInfo.Font`,
+		},
+		{
+			name: "no atlas",
+			data: `{"coin": [0, 0, 8, 8]}`,
+			info: IconSetInfo{Font: 1},
+			err:  `"coin": atlas icon requires IconSetInfo.Atlas`,
+		},
+		{
+			name: "bad rune",
+			data: `{"ok": "U+D800"}`,
+			info: both,
+			err:  `"ok": bad code point "U+D800"`,
+		},
+		{
+			name: "empty region",
+			data: `{"coin": [0, 0, 0, 8]}`,
+			info: both,
+			err:  `"coin": empty atlas region`,
+		},
+		{
+			name: "short region",
+			data: `{"coin": [0, 0, 8]}`,
+			info: both,
+			err:  `"coin": expected a rune string or a [x, y, width, height] region`,
+		},
+		{
+			name: "bad value",
+			data: `{"coin": 10}`,
+			info: both,
+			err:  `"coin": expected a rune string or a [x, y, width, height] region`,
+		},
+		{
+			name: "first error by name",
+			data: `{"b": 1, "a": 2}`,
+			info: both,
+			err:  `"a": expected a rune string or a [x, y, width, height] region`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			have, err := decodeIconSet(strings.NewReader(test.data), test.info)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("have error: %v\nwant error: %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(have, test.want) {
+				t.Fatalf("have: %+v\nwant: %+v", have, test.want)
+			}
+		})
+	}
+}
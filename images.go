@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	xdraw "golang.org/x/image/draw"
+)
+
+func newEbitenImage(img image.Image, info ImageInfo) *ebiten.Image {
+	return ebiten.NewImageFromImageWithOptions(img, &ebiten.NewImageFromImageOptions{
+		Unmanaged: info.Unmanaged,
+	})
+}
+
+func generateMipmaps(img image.Image, info ImageInfo) []*ebiten.Image {
+	levels := scaleMipmaps(img, info.Mipmaps)
+	mipmaps := make([]*ebiten.Image, len(levels))
+	for i, level := range levels {
+		mipmaps[i] = newEbitenImage(level, info)
+	}
+	return mipmaps
+}
+
+func scaleMipmaps(img image.Image, n int) []image.Image {
+	levels := make([]image.Image, 0, n)
+	src := img
+	for i := 0; i < n; i++ {
+		b := src.Bounds()
+		w := b.Dx() / 2
+		h := b.Dy() / 2
+		if w == 0 || h == 0 {
+			break
+		}
+		dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+		xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, b, xdraw.Src, nil)
+		levels = append(levels, dst)
+		src = dst
+	}
+	return levels
+}
+
+// writeEbitenImage uploads the img pixels into the existing texture.
+// It reports false if their sizes don't match.
+func writeEbitenImage(dst *ebiten.Image, img image.Image) bool {
+	b := img.Bounds()
+	if w, h := dst.Size(); w != b.Dx() || h != b.Dy() {
+		return false
+	}
+	// WritePixels expects the premultiplied alpha RGBA data.
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, b.Min, draw.Src)
+	dst.WritePixels(rgba.Pix)
+	return true
+}
+
+// subImage returns a rect region of img.
+// The pixels are shared with img when it's possible.
+func subImage(img image.Image, rect image.Rectangle) image.Image {
+	if s, ok := img.(interface {
+		SubImage(r image.Rectangle) image.Image
+	}); ok {
+		return s.SubImage(rect)
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, rect.Min, draw.Src)
+	return dst
+}
+
+// processImage applies the decoded image transformations
+// that are requested by the image info.
+func processImage(img image.Image, info ImageInfo) image.Image {
+	if info.Alpha != AlphaAuto {
+		img = applyAlphaMode(img, info.Alpha)
+	}
+	if info.ColorKey != nil {
+		img = applyColorKey(img, info.ColorKey)
+	}
+	return img
+}
+
+// applyAlphaMode reinterprets the stored color values according to the mode.
+func applyAlphaMode(img image.Image, mode AlphaMode) image.Image {
+	switch mode {
+	case AlphaPremultiplied:
+		// The stored colors are already premultiplied,
+		// so they're used as is without another premultiplication.
+		src := toNRGBA(img)
+		pix := src.Pix
+		for i := 0; i < len(pix); i += 4 {
+			a := pix[i+3]
+			// Premultiplied color components can't exceed the alpha.
+			for j := 0; j < 3; j++ {
+				if pix[i+j] > a {
+					pix[i+j] = a
+				}
+			}
+		}
+		return &image.RGBA{Pix: pix, Stride: src.Stride, Rect: src.Rect}
+	case AlphaStraight:
+		// The decoder reported the colors as premultiplied,
+		// but they're not: use them as is without un-premultiplication.
+		src, ok := img.(*image.RGBA)
+		if !ok {
+			return img
+		}
+		b := src.Bounds()
+		dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		for y := 0; y < b.Dy(); y++ {
+			copy(dst.Pix[y*dst.Stride:], src.Pix[src.PixOffset(b.Min.X, b.Min.Y+y):][:b.Dx()*4])
+		}
+		return dst
+	default:
+		return img
+	}
+}
+
+// toNRGBA returns img as *image.NRGBA.
+// The result is always a copy, so it's safe to modify it.
+func toNRGBA(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	dst := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
+	return dst
+}
+
+func applyColorKey(img image.Image, key color.Color) *image.NRGBA {
+	k := color.NRGBAModel.Convert(key).(color.NRGBA)
+	dst := toNRGBA(img)
+	pix := dst.Pix
+	for i := 0; i < len(pix); i += 4 {
+		if pix[i+0] == k.R && pix[i+1] == k.G && pix[i+2] == k.B {
+			pix[i+0] = 0
+			pix[i+1] = 0
+			pix[i+2] = 0
+			pix[i+3] = 0
+		}
+	}
+	return dst
+}
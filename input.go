@@ -0,0 +1,155 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// InputDevice is an input binding device type.
+type InputDevice int
+
+const (
+	InputKeyboard InputDevice = iota
+	InputGamepad
+	InputMouse
+)
+
+// InputBinding binds an action to a single key or button.
+type InputBinding struct {
+	Device InputDevice
+
+	// Key is set for the InputKeyboard bindings.
+	Key ebiten.Key
+
+	// GamepadButton is set for the InputGamepad bindings.
+	GamepadButton ebiten.StandardGamepadButton
+
+	// MouseButton is set for the InputMouse bindings.
+	MouseButton ebiten.MouseButton
+}
+
+// IsPressed reports whether the bound key or button is pressed.
+// The gamepad argument is only used for the gamepad bindings.
+func (b InputBinding) IsPressed(gamepad ebiten.GamepadID) bool {
+	switch b.Device {
+	case InputGamepad:
+		return ebiten.IsStandardGamepadButtonPressed(gamepad, b.GamepadButton)
+	case InputMouse:
+		return ebiten.IsMouseButtonPressed(b.MouseButton)
+	default:
+		return ebiten.IsKeyPressed(b.Key)
+	}
+}
+
+var gamepadButtonNames = map[string]ebiten.StandardGamepadButton{
+	"RightBottom":      ebiten.StandardGamepadButtonRightBottom,
+	"RightRight":       ebiten.StandardGamepadButtonRightRight,
+	"RightLeft":        ebiten.StandardGamepadButtonRightLeft,
+	"RightTop":         ebiten.StandardGamepadButtonRightTop,
+	"FrontTopLeft":     ebiten.StandardGamepadButtonFrontTopLeft,
+	"FrontTopRight":    ebiten.StandardGamepadButtonFrontTopRight,
+	"FrontBottomLeft":  ebiten.StandardGamepadButtonFrontBottomLeft,
+	"FrontBottomRight": ebiten.StandardGamepadButtonFrontBottomRight,
+	"CenterLeft":       ebiten.StandardGamepadButtonCenterLeft,
+	"CenterRight":      ebiten.StandardGamepadButtonCenterRight,
+	"LeftStick":        ebiten.StandardGamepadButtonLeftStick,
+	"RightStick":       ebiten.StandardGamepadButtonRightStick,
+	"LeftTop":          ebiten.StandardGamepadButtonLeftTop,
+	"LeftBottom":       ebiten.StandardGamepadButtonLeftBottom,
+	"LeftLeft":         ebiten.StandardGamepadButtonLeftLeft,
+	"LeftRight":        ebiten.StandardGamepadButtonLeftRight,
+	"CenterCenter":     ebiten.StandardGamepadButtonCenterCenter,
+}
+
+var mouseButtonNames = map[string]ebiten.MouseButton{
+	"Left":   ebiten.MouseButtonLeft,
+	"Right":  ebiten.MouseButtonRight,
+	"Middle": ebiten.MouseButtonMiddle,
+}
+
+// parseInputBinding parses the binding strings:
+// "Space" (ebiten.Key names), "gamepad:RightBottom"
+// (ebiten.StandardGamepadButton names without a prefix)
+// and "mouse:Left".
+func parseInputBinding(s string) (InputBinding, error) {
+	device, name, ok := strings.Cut(s, ":")
+	if !ok {
+		var key ebiten.Key
+		if err := key.UnmarshalText([]byte(s)); err != nil {
+			return InputBinding{}, fmt.Errorf("unknown key %q", s)
+		}
+		return InputBinding{Device: InputKeyboard, Key: key}, nil
+	}
+	switch device {
+	case "gamepad":
+		button, ok := gamepadButtonNames[name]
+		if !ok {
+			return InputBinding{}, fmt.Errorf("unknown gamepad button %q", name)
+		}
+		return InputBinding{Device: InputGamepad, GamepadButton: button}, nil
+	case "mouse":
+		button, ok := mouseButtonNames[name]
+		if !ok {
+			return InputBinding{}, fmt.Errorf("unknown mouse button %q", name)
+		}
+		return InputBinding{Device: InputMouse, MouseButton: button}, nil
+	default:
+		return InputBinding{}, fmt.Errorf("unknown input device %q", device)
+	}
+}
+
+func decodeInputProfile(r io.Reader, info InputProfileInfo) (map[string][]InputBinding, error) {
+	var doc struct {
+		Actions map[string][]string `json:"actions"`
+	}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	actions := make(map[string][]InputBinding, len(doc.Actions))
+	names := make([]string, 0, len(doc.Actions))
+	for name := range doc.Actions {
+		names = append(names, name)
+	}
+	// Sort the actions to report the errors deterministically.
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("empty action name")
+		}
+		if len(info.Actions) != 0 && !containsString(info.Actions, name) {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+		bindings := make([]InputBinding, len(doc.Actions[name]))
+		for i, s := range doc.Actions[name] {
+			b, err := parseInputBinding(s)
+			if err != nil {
+				return nil, fmt.Errorf("action %q: %w", name, err)
+			}
+			bindings[i] = b
+		}
+		actions[name] = bindings
+	}
+	for _, name := range info.Actions {
+		if _, ok := actions[name]; !ok {
+			return nil, fmt.Errorf("action %q is not bound", name)
+		}
+	}
+	return actions, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -1,26 +1,65 @@
 package resource
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
+	"image/color"
 	"io"
 	"math"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
-	"github.com/hajimehoshi/ebiten/v2/audio/wav"
 	"github.com/hajimehoshi/ebiten/v2/text"
 	"golang.org/x/image/font"
 	"golang.org/x/image/font/opentype"
 )
 
+// OpenAssetFunc is used to open an asset resource identified by its path.
+// See Loader.OpenAssetFunc.
+type OpenAssetFunc func(path string) io.ReadCloser
+
+// WriteAssetFunc is used to write an asset resource identified by its path.
+// See Loader.WriteAssetFunc.
+type WriteAssetFunc func(path string, data []byte) error
+
 // Loader is used to load and cache game resources like images and audio files.
 type Loader struct {
 	// OpenAssetFunc is used to open an asset resource identified by its path.
 	// The returned resource will be closed after it will be loaded.
-	OpenAssetFunc func(path string) io.ReadCloser
+	//
+	// Use the Use method to add extra behavior on top of this function
+	// instead of overwriting it.
+	OpenAssetFunc OpenAssetFunc
+
+	// OpenAssetErrFunc is like OpenAssetFunc, but it can report an error.
+	// If it's set, it's used instead of the OpenAssetFunc.
+	//
+	// The load methods panic with an *Error value when this function fails.
+	// The panic can be recovered to inspect the error, for example,
+	// to report a missing file without crashing the game.
+	// The Use middlewares are applied to this function as well.
+	OpenAssetErrFunc func(path string) (io.ReadCloser, error)
+
+	// WriteAssetFunc is used to persist the assets, see SaveRaw and WriteAsset.
+	// If it's nil, the loader is read-only.
+	//
+	// The path is rewritten the same way as for the OpenAssetFunc
+	// (base path, RewritePathFunc), except for the Fingerprints that are not applied.
+	WriteAssetFunc WriteAssetFunc
 
 	// CustomAudioLoader allows LoadAudio to load audio formats that are not supported by default.
 	// If it's nil, LoadAudio() will support only ".ogg" and ".wav" formats.
@@ -49,21 +88,402 @@ type Loader struct {
 	// as this function is called after the default loaders and it's by design.
 	CustomAudioLoader func(r io.Reader, info AudioInfo) io.ReadSeeker
 
+	// CompileScriptFunc is called once per Script resource after its source is read.
+	// Its result is stored in the Script.Compiled field.
+	//
+	// This function makes it possible to plug in any scripting language
+	// implementation, like gopher-lua:
+	//
+	//	l.CompileScriptFunc = func(info resource.ScriptInfo, src []byte) (any, error) {
+	//		chunk, err := parse.Parse(bytes.NewReader(src), info.Path)
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		return lua.Compile(chunk, info.Path)
+	//	}
+	//
+	// If it's nil, scripts are loaded as plain sources.
+	CompileScriptFunc func(info ScriptInfo, src []byte) (any, error)
+
+	// Platform is used to select the platform-specific resource paths
+	// (see PathByPlatform field of the resource info types).
+	// If a resource has no path override for the current platform,
+	// its default Path is used.
+	//
+	// NewLoader initializes it with runtime.GOOS value ("android", "ios", "js", etc.),
+	// but it can be set to any string, like "mobile" or "steamdeck".
+	Platform string
+
+	// RewritePathFunc is an optional hook that can be used to rewrite
+	// the resource path right before it's passed to the OpenAssetFunc.
+	// It receives a path with the base path prefix already applied (see SetBasePath).
+	//
+	// A nil function leaves paths unchanged.
+	RewritePathFunc func(path string) string
+
+	// Fingerprints maps logical resource paths to their content-addressed
+	// (hashed) counterparts, like "images/hero.png" => "images/hero.3f2a9c.png".
+	//
+	// The mapping is applied transparently before any other path rewriting,
+	// so the game code (and the registries) can keep using the clean logical paths
+	// while the web builds use immutable hashed asset URLs.
+	//
+	// Paths that are not present in this map are used as is.
+	// See also: LoadFingerprints.
+	Fingerprints map[string]string
+
+	// Checksums maps logical resource paths to their expected
+	// hex-encoded SHA-256 sums.
+	// Resources that have a checksum are verified upon their loading.
+	// This can be useful to catch corrupted downloads and casual
+	// tampering of the data-driven game rules.
+	//
+	// Note that verified resources are read into memory entirely
+	// before they're decoded (this includes streamed audio).
+	Checksums map[string]string
+
+	// VariantFunc is an optional hook that can replace the resource info
+	// right before the resource data is read (that is, before its first load).
+	// It receives the registered info value (like ImageInfo)
+	// and it should return an info of the same type.
+	//
+	// It allows the experiments and seasonal reskins to redirect
+	// the specific resources to the alternative paths in one place
+	// instead of branching at every call site:
+	//
+	//	l.VariantFunc = func(ref resource.Ref, info any) any {
+	//		if imageInfo, ok := info.(resource.ImageInfo); ok && ref == ImageLogo.Ref() && isWinter {
+	//			imageInfo.Path = "seasonal/winter_logo.png"
+	//			return imageInfo
+	//		}
+	//		return info
+	//	}
+	//
+	// It's only consulted for the resources that are read by their paths.
+	// A nil function means "use the registered info".
+	VariantFunc func(ref Ref, info any) any
+
+	// ScheduledOverrides replace the resource paths during the given date ranges,
+	// so the holiday-themed art activates automatically.
+	// The overrides are resolved when the resource is loaded (see NowFunc),
+	// they're applied before the VariantFunc.
+	// If several overrides are active for the same resource, the last one wins.
+	ScheduledOverrides []ScheduledOverride
+
+	// NowFunc returns the current time for the ScheduledOverrides.
+	// It can be replaced to test the scheduled content.
+	// A nil function means time.Now.
+	NowFunc func() time.Time
+
+	// Patches maps logical resource paths to the binary patch file paths.
+	// The patches are applied to the original assets when they're opened,
+	// so the small content updates don't require re-shipping the whole asset packs.
+	//
+	// The patch files use the bsdiff format (BSDIFF40).
+	// The Checksums are verified for the original (unpatched) assets.
+	// The patched data is kept in memory, so the patch is applied only once.
+	Patches map[string]string
+
+	// PathRedirects maps the old (renamed) asset paths to the new ones.
+	// It allows restructuring the asset directories incrementally
+	// without breaking the saved manifests or mods that refer to the old paths.
+	//
+	// The redirects are applied before any other path mapping,
+	// so the Checksums, Patches and Fingerprints use the new paths.
+	// The redirects can be chained (old => older => new).
+	// Every redirected path is reported once via the WarningFunc
+	// and the EventDeprecated event (so it's logged by SetLogger).
+	PathRedirects map[string]string
+
+	// PathNormalization makes the loader normalize the resource paths
+	// before opening them (see NormalizePath).
+	// It helps with the assets that were authored on Windows,
+	// where the paths are case-insensitive and can use backslashes.
+	//
+	// The normalization is applied after the path variables expansion
+	// (see SetPathVar) and before any other path mapping,
+	// so the PathRedirects, Checksums, Patches and Fingerprints keys
+	// should use the normalized paths.
+	// Use ValidatePaths to find the paths that become ambiguous.
+	//
+	// A zero value means that the paths are used as is.
+	PathNormalization PathNormalization
+
+	// IntegrityErrorFunc is called when a resource checksum verification fails.
+	// If it returns normally, the resource is loaded as is.
+	// This function may panic to abort the loading.
+	//
+	// A nil function means "panic on any integrity error".
+	IntegrityErrorFunc func(path string, err error)
+
+	// WarningFunc is called for the non-fatal problems found by the loader,
+	// like the failed debug checks (see ValidateLoop).
+	// A nil function means "ignore the warnings".
+	WarningFunc func(err error)
+
+	// DiskCacheDir enables a persistent cache of decoded resources.
+	// If it's empty, nothing is cached on disk.
+	//
+	// The cached resources are:
+	//
+	//   - OGG audio: the decoded PCM is keyed by the OGG content hash;
+	//     it's streamed from the cache file directly, skipping the decoding.
+	//     The cache file is kept open while the resource is loaded.
+	//   - SDF fonts: the rasterized atlas and the glyph metrics are keyed by
+	//     the font content hash and the SDFFontInfo generation parameters.
+	//
+	// This can drop the second launch load times dramatically on slow devices.
+	//
+	// A good candidate for this directory is a subdirectory
+	// of os.UserCacheDir() result.
+	// Cache write errors are not fatal: a resource is decoded
+	// as usual if it can't be cached.
+	DiskCacheDir string
+
+	// GroupStreamDecorators maps an audio group to its default stream decorator.
+	// It's used for the audio resources that have no StreamDecorator set.
+	// For example, all music (group 1) could be looped by default.
+	//
+	// See AudioInfo.StreamDecorator for more details about decorators.
+	GroupStreamDecorators map[uint]func(stream io.ReadSeeker) io.ReadSeeker
+
+	// ConfigDecoders maps a file extension (like ".yaml") to a function
+	// that decodes the Config resource data into v.
+	// The signature matches the popular unmarshal functions,
+	// so it's possible to use yaml.Unmarshal or toml.Unmarshal directly:
+	//
+	//	l.ConfigDecoders[".yaml"] = yaml.Unmarshal
+	//	l.ConfigDecoders[".toml"] = toml.Unmarshal
+	//
+	// NewLoader registers the ".json" decoder (json.Unmarshal) by default.
+	// The YAML and TOML decoders are not provided, as they would make
+	// every user of this package depend on these libraries.
+	ConfigDecoders map[string]func(data []byte, v any) error
+
+	// TextureBudget is an approximate texture memory limit (in bytes).
+	// Exceeding it calls the TextureBudgetFunc; the textures are not unloaded.
+	// A zero value disables the budget checks.
+	//
+	// See TextureMemory for the details on how the memory usage is calculated.
+	TextureBudget int64
+
+	// TextureBudgetFunc is called when a texture loading makes
+	// the memory usage exceed the TextureBudget.
+	// It's only called again after the usage goes below the budget.
+	//
+	// This is useful to catch the memory creep on the
+	// low-end targets early, like by logging a warning.
+	TextureBudgetFunc func(used, budget int64)
+
+	// LRUCacheLimit enables the automatic eviction of the least recently
+	// loaded images and raws when their total size exceeds this limit (in bytes).
+	// A zero value disables the eviction.
+	//
+	// Every Load call (even the one that returns a cached resource)
+	// marks the resource as recently used; for the images, this includes
+	// their derived variants (like LoadImageTinted).
+	// The evicted resources are unloaded (see Unload),
+	// so the next Load for them will decode them again.
+	//
+	// Unlike Unload, the eviction doesn't dispose the image textures:
+	// the loader drops its references and the textures are released
+	// by the garbage collector once the game objects drop theirs too.
+	// The images that are still being drawn stay valid, but loading
+	// them again creates a new texture, so the limit should be high
+	// enough to fit the working set.
+	//
+	// Therefore the limit bounds the memory retained by the loader,
+	// not the VRAM usage: the evicted textures stay allocated until
+	// they're garbage collected. Call Unload for the images that are
+	// known to be unused to release their textures right away.
+	//
+	// This is useful for the games that stream a lot of assets,
+	// like open world region textures.
+	LRUCacheLimit int64
+
+	// SoftCacheLimit is a max total size (in bytes) of the soft-cached
+	// resources (see RawInfo.Soft and ConfigInfo.Soft).
+	// When it's exceeded, the oldest soft-cached resources are unloaded.
+	// A zero value means "keep only the last loaded soft resource".
+	SoftCacheLimit int64
+
+	// SharedCache is an optional decoded data cache that can be shared
+	// between several loaders, see SharedCache type.
+	// Right now it's used for the images.
+	SharedCache *SharedCache
+
+	// UsageTracker records the loaded resources.
+	// It's nil by default (the tracking is disabled).
+	// See NewUsageTracker.
+	UsageTracker *UsageTracker
+
+	// MaxTextureSize is a max image width and height that can be
+	// uploaded as a single texture. A zero value disables the check.
+	//
+	// Loading a bigger image fails with a descriptive error,
+	// unless it's marked as ImageInfo.Tiled.
+	// The actual limit depends on the GPU; 4096 is a safe value
+	// for most platforms (including mobile and WebGL).
+	MaxTextureSize int
+
+	// Profiler records the resource loading durations.
+	// It's nil by default (the profiling is disabled).
+	// See NewProfiler.
+	Profiler *Profiler
+
+	// Tracer wraps the resource loading phases into the tracing spans.
+	// It's nil by default (the tracing is disabled).
+	// See Tracer type documentation for an OpenTelemetry example.
+	Tracer Tracer
+
+	// SaveRoot is a writable directory for the savegame slots.
+	// On wasm, it's used as a localStorage key prefix instead.
+	// See Saves method.
+	//
+	// A good candidate for this directory is a subdirectory
+	// of os.UserConfigDir() result.
+	SaveRoot string
+
 	ImageRegistry  registry[ImageID, ImageInfo]
 	AudioRegistry  registry[AudioID, AudioInfo]
 	FontRegistry   registry[FontID, FontInfo]
 	ShaderRegistry registry[ShaderID, ShaderInfo]
 	RawRegistry    registry[RawID, RawInfo]
 
+	PaletteRegistry      registry[PaletteID, PaletteInfo]
+	MaterialRegistry     registry[MaterialID, MaterialInfo]
+	SDFFontRegistry      registry[SDFFontID, SDFFontInfo]
+	TableRegistry        registry[TableID, TableInfo]
+	ConfigRegistry       registry[ConfigID, ConfigInfo]
+	TemplateRegistry     registry[TemplateID, TemplateInfo]
+	ScriptRegistry       registry[ScriptID, ScriptInfo]
+	ParticleDefRegistry  registry[ParticleDefID, ParticleDefInfo]
+	HitboxesRegistry     registry[HitboxesID, HitboxesInfo]
+	TileSetRegistry      registry[TileSetID, TileSetInfo]
+	CompositeRegistry    registry[CompositeID, CompositeInfo]
+	ShaderImageRegistry  registry[ShaderImageID, ShaderImageInfo]
+	LUTRegistry          registry[LUTID, LUTInfo]
+	GradientRegistry     registry[GradientID, GradientInfo]
+	CurveRegistry        registry[CurveID, CurveInfo]
+	InputProfileRegistry registry[InputProfileID, InputProfileInfo]
+	DefinitionRegistry   registry[DefinitionID, DefinitionInfo]
+	DocumentRegistry     registry[DocumentID, DocumentInfo]
+	IconSetRegistry      registry[IconSetID, IconSetInfo]
+	ThemeRegistry        registry[ThemeID, ThemeInfo]
+	VariantSetRegistry   registry[VariantSetID, VariantSetInfo]
+
 	audioContext *audio.Context
 
-	images      map[ImageID]Image
-	shaders     map[ShaderID]Shader
-	wavs        map[AudioID]Audio
-	oggs        map[AudioID]Audio
-	customAudio map[AudioID]Audio
-	fonts       map[FontID]Font
-	raws        map[RawID]Raw
+	basePath string
+
+	middlewares []func(next OpenAssetFunc) OpenAssetFunc
+
+	// textureMemory is the TextureMemory running total.
+	textureMemory int64
+
+	// lruSize is the total size of the LRU-managed resources (see LRUCacheLimit).
+	lruSize int64
+
+	// openChain is the middlewares chain built by openFunc.
+	openChain OpenAssetFunc
+
+	// depStack are the resources that are loading their dependencies.
+	// It's used to detect the dependency cycles.
+	depStack []Ref
+
+	// packStack are the images that are packing their channels.
+	// It's used to detect the ImageInfo.PackChannels cycles.
+	packStack []Ref
+
+	// subscribers are the event listeners, see Subscribe.
+	subscribers      []eventSubscriber
+	nextSubscriberID int
+	loadStarts       map[Ref]time.Time
+
+	// unsubscribeLogger removes the SetLogger subscription.
+	unsubscribeLogger func()
+
+	// pathVars are the path variable values (see SetPathVar).
+	pathVars map[string]string
+
+	// warnedRedirects are the redirected paths that were reported.
+	warnedRedirects map[string]struct{}
+
+	// history is a ring buffer of the recent events, see DumpState.
+	history    []historyEntry
+	historyPos int
+
+	// mounts are the content roots added by MountContent.
+	mounts []*contentMount
+
+	// openedPaths are the asset paths opened by the resources,
+	// after all path mappings (see mapAssetPath).
+	// It's used to find the resources overridden by the mounts.
+	openedPaths map[Ref][]string
+
+	// patchedAssets caches the patched assets data, see Patches.
+	patchedAssets map[patchKey][]byte
+
+	// pcmFiles are the opened decoded OGG cache entries, see DiskCacheDir.
+	pcmFiles map[AudioID]*pcmFile
+
+	saves *SaveStore
+
+	// loadQueue holds the queued resources, indexed by their priority.
+	loadQueue [numPriorities][]Ref
+
+	// loadWaiters are the WhenLoaded channels to close.
+	loadWaiters map[Ref][]chan struct{}
+
+	textureBudgetExceeded bool
+
+	// lastUsed maps the LRU-managed resources to their last use tick.
+	lastUsed map[Ref]uint64
+	useTick  uint64
+
+	// softEntries are the soft-cached resources, oldest first.
+	softEntries []softEntry
+	softSize    int64
+
+	images        map[ImageID]Image
+	shaders       map[ShaderID]Shader
+	wavs          map[AudioID]Audio
+	oggs          map[AudioID]Audio
+	customAudio   map[AudioID]Audio
+	fonts         map[FontID]Font
+	raws          map[RawID]Raw
+	palettes      map[PaletteID]Palette
+	materials     map[MaterialID]Material
+	sdfFonts      map[SDFFontID]SDFFont
+	tables        map[TableID]Table
+	templates     map[TemplateID]Template
+	scripts       map[ScriptID]Script
+	particleDefs  map[ParticleDefID]ParticleDef
+	hitboxes      map[HitboxesID]Hitboxes
+	tileSets      map[TileSetID]TileSet
+	composites    map[CompositeID]Composite
+	shaderImages  map[ShaderImageID]ShaderImage
+	luts          map[LUTID]LUT
+	gradients     map[GradientID]Gradient
+	curves        map[CurveID]Curve
+	inputProfiles map[InputProfileID]InputProfile
+	definitions   map[DefinitionID]Definition
+	documents     map[DocumentID]Document
+	iconSets      map[IconSetID]IconSet
+	themes        map[ThemeID]Theme
+	variantSets   map[VariantSetID]VariantSet
+	configs       map[ConfigID]*Config
+
+	// plugins are the registered plugin kinds, see RegisterKind.
+	plugins map[Kind]*pluginKind
+
+	// imageVariants caches the derived images, like tinted ones.
+	imageVariants map[imageVariantKey]Image
+
+	// deps maps a loaded resource to its loaded dependencies.
+	// depRefs counts the loaded dependents of a resource.
+	deps    map[Ref][]Ref
+	depRefs map[Ref]int
 }
 
 // NewLoader creates a new resources loader that serves as both
@@ -74,49 +494,238 @@ type Loader struct {
 // be created without an initialized Ebitengine audio context.
 func NewLoader(audioContext *audio.Context) *Loader {
 	l := &Loader{
-		images:      make(map[ImageID]Image),
-		shaders:     make(map[ShaderID]Shader),
-		wavs:        make(map[AudioID]Audio),
-		oggs:        make(map[AudioID]Audio),
-		customAudio: make(map[AudioID]Audio),
-		fonts:       make(map[FontID]Font),
-		raws:        make(map[RawID]Raw),
+		images:        make(map[ImageID]Image),
+		shaders:       make(map[ShaderID]Shader),
+		wavs:          make(map[AudioID]Audio),
+		oggs:          make(map[AudioID]Audio),
+		customAudio:   make(map[AudioID]Audio),
+		fonts:         make(map[FontID]Font),
+		raws:          make(map[RawID]Raw),
+		palettes:      make(map[PaletteID]Palette),
+		materials:     make(map[MaterialID]Material),
+		sdfFonts:      make(map[SDFFontID]SDFFont),
+		tables:        make(map[TableID]Table),
+		templates:     make(map[TemplateID]Template),
+		scripts:       make(map[ScriptID]Script),
+		particleDefs:  make(map[ParticleDefID]ParticleDef),
+		hitboxes:      make(map[HitboxesID]Hitboxes),
+		tileSets:      make(map[TileSetID]TileSet),
+		composites:    make(map[CompositeID]Composite),
+		shaderImages:  make(map[ShaderImageID]ShaderImage),
+		luts:          make(map[LUTID]LUT),
+		gradients:     make(map[GradientID]Gradient),
+		curves:        make(map[CurveID]Curve),
+		inputProfiles: make(map[InputProfileID]InputProfile),
+		definitions:   make(map[DefinitionID]Definition),
+		documents:     make(map[DocumentID]Document),
+		iconSets:      make(map[IconSetID]IconSet),
+		themes:        make(map[ThemeID]Theme),
+		variantSets:   make(map[VariantSetID]VariantSet),
+		configs:       make(map[ConfigID]*Config),
+
+		plugins:       make(map[Kind]*pluginKind),
+		imageVariants: make(map[imageVariantKey]Image),
+		deps:          make(map[Ref][]Ref),
+		depRefs:       make(map[Ref]int),
 	}
 	l.audioContext = audioContext
+	l.Platform = runtime.GOOS
+	l.ConfigDecoders = map[string]func(data []byte, v any) error{
+		".json": json.Unmarshal,
+	}
 	l.AudioRegistry.mapping = make(map[AudioID]AudioInfo)
 	l.ImageRegistry.mapping = make(map[ImageID]ImageInfo)
 	l.ShaderRegistry.mapping = make(map[ShaderID]ShaderInfo)
 	l.FontRegistry.mapping = make(map[FontID]FontInfo)
 	l.RawRegistry.mapping = make(map[RawID]RawInfo)
+	l.PaletteRegistry.mapping = make(map[PaletteID]PaletteInfo)
+	l.MaterialRegistry.mapping = make(map[MaterialID]MaterialInfo)
+	l.SDFFontRegistry.mapping = make(map[SDFFontID]SDFFontInfo)
+	l.TableRegistry.mapping = make(map[TableID]TableInfo)
+	l.ConfigRegistry.mapping = make(map[ConfigID]ConfigInfo)
+	l.TemplateRegistry.mapping = make(map[TemplateID]TemplateInfo)
+	l.ScriptRegistry.mapping = make(map[ScriptID]ScriptInfo)
+	l.ParticleDefRegistry.mapping = make(map[ParticleDefID]ParticleDefInfo)
+	l.HitboxesRegistry.mapping = make(map[HitboxesID]HitboxesInfo)
+	l.TileSetRegistry.mapping = make(map[TileSetID]TileSetInfo)
+	l.CompositeRegistry.mapping = make(map[CompositeID]CompositeInfo)
+	l.ShaderImageRegistry.mapping = make(map[ShaderImageID]ShaderImageInfo)
+	l.LUTRegistry.mapping = make(map[LUTID]LUTInfo)
+	l.GradientRegistry.mapping = make(map[GradientID]GradientInfo)
+	l.CurveRegistry.mapping = make(map[CurveID]CurveInfo)
+	l.InputProfileRegistry.mapping = make(map[InputProfileID]InputProfileInfo)
+	l.DefinitionRegistry.mapping = make(map[DefinitionID]DefinitionInfo)
+	l.DocumentRegistry.mapping = make(map[DocumentID]DocumentInfo)
+	l.IconSetRegistry.mapping = make(map[IconSetID]IconSetInfo)
+	l.ThemeRegistry.mapping = make(map[ThemeID]ThemeInfo)
+	l.VariantSetRegistry.mapping = make(map[VariantSetID]VariantSetInfo)
 	return l
 }
 
+// CloneRegistries returns a new loader with a copy of all registries
+// and the loader settings (like OpenAssetFunc and middlewares), but with empty caches.
+//
+// It's useful for tools that need to decode the same assets with
+// different options side by side, like a headless export
+// along with the in-game preview.
+//
+// The registries are copied, so the later registrations
+// (and settings changes) are not shared between the loaders.
+func (l *Loader) CloneRegistries() *Loader {
+	c := NewLoader(l.audioContext)
+	c.OpenAssetFunc = l.OpenAssetFunc
+	c.OpenAssetErrFunc = l.OpenAssetErrFunc
+	c.WriteAssetFunc = l.WriteAssetFunc
+	c.CustomAudioLoader = l.CustomAudioLoader
+	c.CompileScriptFunc = l.CompileScriptFunc
+	c.Platform = l.Platform
+	c.RewritePathFunc = l.RewritePathFunc
+	c.VariantFunc = l.VariantFunc
+	c.ScheduledOverrides = append([]ScheduledOverride(nil), l.ScheduledOverrides...)
+	c.NowFunc = l.NowFunc
+	c.Fingerprints = cloneMap(l.Fingerprints)
+	c.Checksums = cloneMap(l.Checksums)
+	c.Patches = cloneMap(l.Patches)
+	c.PathRedirects = cloneMap(l.PathRedirects)
+	c.PathNormalization = l.PathNormalization
+	c.pathVars = cloneMap(l.pathVars)
+	c.IntegrityErrorFunc = l.IntegrityErrorFunc
+	c.WarningFunc = l.WarningFunc
+	c.DiskCacheDir = l.DiskCacheDir
+	c.GroupStreamDecorators = cloneMap(l.GroupStreamDecorators)
+	c.ConfigDecoders = cloneMap(l.ConfigDecoders)
+	c.SaveRoot = l.SaveRoot
+	c.Profiler = l.Profiler
+	c.Tracer = l.Tracer
+	c.UsageTracker = l.UsageTracker
+	c.SharedCache = l.SharedCache
+	c.TextureBudget = l.TextureBudget
+	c.MaxTextureSize = l.MaxTextureSize
+	c.TextureBudgetFunc = l.TextureBudgetFunc
+	c.LRUCacheLimit = l.LRUCacheLimit
+	c.SoftCacheLimit = l.SoftCacheLimit
+	c.basePath = l.basePath
+	c.mounts = make([]*contentMount, len(l.mounts))
+	for i, m := range l.mounts {
+		mountCopy := *m
+		c.mounts[i] = &mountCopy
+	}
+	c.middlewares = append([]func(next OpenAssetFunc) OpenAssetFunc(nil), l.middlewares...)
+	c.ImageRegistry = l.ImageRegistry.clone()
+	c.AudioRegistry = l.AudioRegistry.clone()
+	c.FontRegistry = l.FontRegistry.clone()
+	c.ShaderRegistry = l.ShaderRegistry.clone()
+	c.RawRegistry = l.RawRegistry.clone()
+	c.PaletteRegistry = l.PaletteRegistry.clone()
+	c.MaterialRegistry = l.MaterialRegistry.clone()
+	c.SDFFontRegistry = l.SDFFontRegistry.clone()
+	c.TableRegistry = l.TableRegistry.clone()
+	c.ConfigRegistry = l.ConfigRegistry.clone()
+	c.TemplateRegistry = l.TemplateRegistry.clone()
+	c.ScriptRegistry = l.ScriptRegistry.clone()
+	c.ParticleDefRegistry = l.ParticleDefRegistry.clone()
+	c.HitboxesRegistry = l.HitboxesRegistry.clone()
+	c.TileSetRegistry = l.TileSetRegistry.clone()
+	c.CompositeRegistry = l.CompositeRegistry.clone()
+	c.ShaderImageRegistry = l.ShaderImageRegistry.clone()
+	c.LUTRegistry = l.LUTRegistry.clone()
+	c.GradientRegistry = l.GradientRegistry.clone()
+	c.CurveRegistry = l.CurveRegistry.clone()
+	c.InputProfileRegistry = l.InputProfileRegistry.clone()
+	c.DefinitionRegistry = l.DefinitionRegistry.clone()
+	c.DocumentRegistry = l.DocumentRegistry.clone()
+	c.IconSetRegistry = l.IconSetRegistry.clone()
+	c.ThemeRegistry = l.ThemeRegistry.clone()
+	c.VariantSetRegistry = l.VariantSetRegistry.clone()
+	for kind, k := range l.plugins {
+		c.plugins[kind] = &pluginKind{
+			plugin:   k.plugin,
+			registry: k.registry.clone(),
+			cache:    make(map[int]any),
+		}
+	}
+	return c
+}
+
+// SetBasePath sets a prefix that is added to every resource path
+// before it's opened.
+//
+// It makes it possible to use the same registry for different
+// asset locations: "assets/", an absolute installation directory
+// or even a CDN URL prefix (like "https://cdn.example.com/game").
+//
+// A leading slash of the resource path is ignored when the prefix is set,
+// so "/ui/button.png" becomes "assets/ui/button.png".
+// An empty prefix disables this path rewriting.
+func (l *Loader) SetBasePath(prefix string) {
+	l.basePath = prefix
+}
+
+// Use adds a middleware to the asset opening chain.
+//
+// A middleware wraps the next OpenAssetFunc in the chain and
+// can add any extra behavior to it: logging, caching, decryption, throttling, etc.
+// The last function in the chain is Loader.OpenAssetFunc.
+//
+// Middlewares are applied in the order they were added:
+// the first one added is the outermost one (it's called first).
+// All middlewares receive the path after all rewritings were applied
+// (see SetBasePath, RewritePathFunc and Fingerprints).
+//
+// The chain is built once, so a middleware can keep its state
+// (like a cache or a connection pool) in its closure.
+// Adding a middleware rebuilds the chain, so all Use calls
+// are expected to happen during the loader setup.
+func (l *Loader) Use(middleware func(next OpenAssetFunc) OpenAssetFunc) {
+	l.middlewares = append(l.middlewares, middleware)
+	l.openChain = nil
+}
+
+// LoadFingerprints reads a JSON object of logical path => hashed path
+// pairs from r and adds them to the Fingerprints map.
+// It's intended to be called at startup, before any resource is loaded.
+func (l *Loader) LoadFingerprints(r io.Reader) error {
+	var m map[string]string
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return fmt.Errorf("decode fingerprints: %w", err)
+	}
+	if l.Fingerprints == nil {
+		l.Fingerprints = make(map[string]string, len(m))
+	}
+	for k, v := range m {
+		l.Fingerprints[k] = v
+	}
+	return nil
+}
+
 // LoadAudio is a helper method that will use an appropriate
 // Load method depending on the filename extension.
 //
 // For example, it will use LoadOGG for ".ogg" files.
 func (l *Loader) LoadAudio(id AudioID) Audio {
+	id = l.AudioRegistry.resolve(id)
+	for _, m := range []map[AudioID]Audio{l.wavs, l.oggs, l.customAudio} {
+		if a, ok := m[id]; ok {
+			return l.cachedAudio(m, a)
+		}
+	}
+	// The info is resolved once and passed down to the format loaders.
 	audioInfo := l.getAudioInfo(id)
 	if strings.HasSuffix(audioInfo.Path, ".ogg") {
-		return l.LoadOGG(id)
+		return l.loadOGG(id, audioInfo)
 	}
 	if strings.HasSuffix(audioInfo.Path, ".wav") {
-		return l.LoadWAV(id)
+		return l.loadWAV(id, audioInfo)
 	}
-	if len(l.customAudio) != 0 || l.CustomAudioLoader != nil {
-		// Even if CustomAudioLoader is nil at this point, we might still have
-		// cached custom audio resources.
-		// Let them a chance to be fetched.
-		a, ok := l.loadCustomAudio(id, audioInfo)
-		if ok {
-			return a
-		}
+	if a, ok := l.loadCustomAudio(id, audioInfo); ok {
+		return a
 	}
-	panic(fmt.Sprintf("load %q audio: unrecognized format", audioInfo.Path))
+	panic(newError("decode", id.Ref(), audioInfo.Path, errors.New("unrecognized format")))
 }
 
 // GetFontInfo extracts the audio info associated with a given key.
 func (l *Loader) GetAudioInfo(id AudioID) AudioInfo {
+	id = l.AudioRegistry.resolve(id)
 	return l.AudioRegistry.mapping[id]
 }
 
@@ -124,39 +733,49 @@ func (l *Loader) GetAudioInfo(id AudioID) AudioInfo {
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
 func (l *Loader) LoadWAV(id AudioID) Audio {
-	a, ok := l.wavs[id]
-	if !ok {
-		wavInfo := l.getAudioInfo(id)
-		r := l.OpenAssetFunc(wavInfo.Path)
-		defer func() {
-			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q wav reader: %v", wavInfo.Path, err))
-			}
-		}()
-		stream, err := wav.DecodeWithoutResampling(r)
-		if err != nil {
-			panic(fmt.Sprintf("decode %q wav: %v", wavInfo.Path, err))
+	id = l.AudioRegistry.resolve(id)
+	if a, ok := l.wavs[id]; ok {
+		return l.cachedAudio(l.wavs, a)
+	}
+	return l.loadWAV(id, l.getAudioInfo(id))
+}
+
+func (l *Loader) loadWAV(id AudioID, wavInfo AudioInfo) Audio {
+	r := l.openResource(id.Ref(), wavInfo.Path, wavInfo.Data)
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(newError("read", id.Ref(), wavInfo.Path, err))
 		}
-		var player *audio.Player
-		if wavInfo.StreamDecorator == nil {
-			// Good, can read it into the memory.
-			wavData := make([]byte, stream.Length())
-			if _, err := io.ReadFull(stream, wavData); err != nil {
-				panic(fmt.Sprintf("read %q wav: %v", wavInfo.Path, err))
-			}
-			player = l.audioContext.NewPlayerFromBytes(wavData)
-		} else {
-			// This is an explicit way to tell "don't read it into the memory".
-			// Also, some streams can have external dependencies to affect the
-			// sound, so we can't rely on the bytes being the same every time.
-			player, err = l.audioContext.NewPlayer(wavInfo.StreamDecorator(stream))
-			if err != nil {
-				panic(err.Error())
-			}
+	}()
+	done := l.profileStart(id.Ref(), wavInfo.Path, stageDecode)
+	stream, err := decodeWAV(r)
+	if err != nil {
+		panic(l.decodeError("decode", id.Ref(), wavInfo.Path, wavInfo.Data, err))
+	}
+	var player *audio.Player
+	var wavData []byte
+	if wavInfo.StreamDecorator == nil {
+		// Good, can read it into the memory.
+		wavData = make([]byte, stream.Length())
+		if _, err := io.ReadFull(stream, wavData); err != nil {
+			panic(newError("read", id.Ref(), wavInfo.Path, err))
+		}
+		player = l.audioContext.NewPlayerFromBytes(wavData)
+	} else {
+		// This is an explicit way to tell "don't read it into the memory".
+		// Also, some streams can have external dependencies to affect the
+		// sound, so we can't rely on the bytes being the same every time.
+		player, err = l.audioContext.NewPlayer(wavInfo.StreamDecorator(stream))
+		if err != nil {
+			panic(err.Error())
 		}
-		a = l.createAudioObject(player, id, wavInfo)
-		l.wavs[id] = a
 	}
+	done()
+	a := l.createAudioObject(player, id, wavInfo, stream)
+	a.data = wavData
+	l.loadDependencies(id.Ref(), wavInfo.DependsOn)
+	l.wavs[id] = a
+	l.emit(EventLoad, id.Ref())
 	return a
 }
 
@@ -164,100 +783,354 @@ func (l *Loader) LoadWAV(id AudioID) Audio {
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
 func (l *Loader) LoadOGG(id AudioID) Audio {
-	a, ok := l.oggs[id]
-	if !ok {
-		oggInfo := l.getAudioInfo(id)
-		// Do not close this reader as it would break the stream with "file already closed".
-		r := l.OpenAssetFunc(oggInfo.Path)
-		var err error
-		oggStream, err := vorbis.DecodeWithoutResampling(r)
-		if err != nil {
-			panic(fmt.Sprintf("decode %q ogg: %v", oggInfo.Path, err))
+	id = l.AudioRegistry.resolve(id)
+	if a, ok := l.oggs[id]; ok {
+		return l.cachedAudio(l.oggs, a)
+	}
+	return l.loadOGG(id, l.getAudioInfo(id))
+}
+
+func (l *Loader) loadOGG(id AudioID, oggInfo AudioInfo) Audio {
+	stream := l.openOGGStream(id, oggInfo)
+	player, err := l.audioContext.NewPlayer(l.maybeWrapAudioStream(stream, oggInfo))
+	if err != nil {
+		panic(err.Error())
+	}
+	a := l.createAudioObject(player, id, oggInfo, stream)
+	l.loadDependencies(id.Ref(), oggInfo.DependsOn)
+	l.oggs[id] = a
+	l.emit(EventLoad, id.Ref())
+	return a
+}
+
+// NewAudioPlayer creates a new audio player for the Audio resource
+// associated with a given key.
+// Unlike the Audio.Player, this player is not cached: every call
+// creates a new independent player.
+// This is useful when the same sound needs to be played several
+// times simultaneously (see SoundPlayer).
+//
+// The in-memory WAV resources share their decoded data between the players.
+// Other audio resources are decoded again for every new player.
+func (l *Loader) NewAudioPlayer(id AudioID) *audio.Player {
+	return l.newAudioPlayer(l.LoadAudio(id))
+}
+
+func (l *Loader) newAudioPlayer(a Audio) *audio.Player {
+	if a.data != nil {
+		return l.audioContext.NewPlayerFromBytes(a.data)
+	}
+	player, err := l.audioContext.NewPlayer(l.newAudioStream(a))
+	if err != nil {
+		panic(err.Error())
+	}
+	return player
+}
+
+// ReleaseAudioPlayer closes the cached player of the loaded Audio resource.
+// Unlike Unload, it keeps the decoded audio data, so the next Load
+// re-creates the player cheaply (the streamed resources are re-opened).
+//
+// It reports whether the player was released.
+func (l *Loader) ReleaseAudioPlayer(id AudioID) bool {
+	id = l.AudioRegistry.resolve(id)
+	for _, m := range []map[AudioID]Audio{l.wavs, l.oggs, l.customAudio} {
+		if a, ok := m[id]; ok && a.Player != nil {
+			a.Player.Close()
+			a.Player = nil
+			m[id] = a
+			return true
 		}
-		player, err := l.audioContext.NewPlayer(l.maybeWrapAudioStream(oggStream, oggInfo))
-		if err != nil {
-			panic(err.Error())
+	}
+	return false
+}
+
+// ReleaseIdleAudioPlayers releases the cached players of all loaded
+// Audio resources that are not playing right now (see ReleaseAudioPlayer).
+//
+// This is useful for the games with hundreds of rarely used sounds,
+// like voice lines: it can be called during the scene transitions.
+// It returns the number of released players.
+func (l *Loader) ReleaseIdleAudioPlayers() int {
+	n := 0
+	for _, m := range []map[AudioID]Audio{l.wavs, l.oggs, l.customAudio} {
+		for id, a := range m {
+			if a.Player == nil || a.Player.IsPlaying() {
+				continue
+			}
+			a.Player.Close()
+			a.Player = nil
+			m[id] = a
+			n++
 		}
-		a = l.createAudioObject(player, id, oggInfo)
-		l.oggs[id] = a
+	}
+	return n
+}
+
+// cachedAudio returns the loaded audio resource,
+// its released player is re-created (see ReleaseAudioPlayer).
+func (l *Loader) cachedAudio(m map[AudioID]Audio, a Audio) Audio {
+	if a.Player == nil {
+		return l.recreateAudioPlayer(m, a)
 	}
 	return a
 }
 
-func (l *Loader) loadCustomAudio(id AudioID, info AudioInfo) (Audio, bool) {
-	a, ok := l.customAudio[id]
-	if !ok {
+func (l *Loader) recreateAudioPlayer(m map[AudioID]Audio, a Audio) Audio {
+	a.Player = l.newAudioPlayer(a)
+	m[a.ID] = a
+	return a
+}
+
+// newAudioStream creates a new independent stream for the loaded audio resource.
+// The stream decorator (if any) is already applied.
+func (l *Loader) newAudioStream(a Audio) io.ReadSeeker {
+	if a.data != nil {
+		return bytes.NewReader(a.data)
+	}
+	info := l.getAudioInfo(a.ID)
+	return l.maybeWrapAudioStream(l.decodeAudioStream(a.ID, info), info)
+}
+
+// decodeAudioStream creates a new decoded stream for the audio resource.
+// Unlike newAudioStream, it never applies the stream decorator.
+func (l *Loader) decodeAudioStream(id AudioID, info AudioInfo) io.ReadSeeker {
+	var stream io.ReadSeeker
+	switch {
+	case strings.HasSuffix(info.Path, ".ogg"):
+		stream = l.openOGGStream(id, info)
+	case strings.HasSuffix(info.Path, ".wav"):
+		// Do not close this reader as it's used by the stream.
+		wavStream, err := decodeWAV(l.openResource(id.Ref(), info.Path, info.Data))
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), info.Path, info.Data, err))
+		}
+		stream = wavStream
+	default:
 		if l.CustomAudioLoader == nil {
-			// Can't load a new custom audio resource without this function.
-			return a, false
+			panic(newError("decode", id.Ref(), info.Path, errors.New("unrecognized format")))
 		}
-		r := l.OpenAssetFunc(info.Path)
+		r := l.openResource(id.Ref(), info.Path, info.Data)
 		defer func() {
 			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q custom audio reader: %v", info.Path, err))
+				panic(newError("read", id.Ref(), info.Path, err))
 			}
 		}()
-		stream := l.CustomAudioLoader(r, info)
+		stream = l.CustomAudioLoader(r, info)
 		if stream == nil {
-			return a, false
+			panic(newError("decode", id.Ref(), info.Path, errors.New("unrecognized format")))
 		}
-		player, err := l.audioContext.NewPlayer(l.maybeWrapAudioStream(stream, info))
+	}
+	return stream
+}
+
+func (l *Loader) openOGGStream(id AudioID, info AudioInfo) io.ReadSeeker {
+	if l.DiskCacheDir != "" {
+		return l.loadCachedOGG(id, info)
+	}
+	// Do not close this reader as it would break the stream with "file already closed".
+	r := l.openResource(id.Ref(), info.Path, info.Data)
+	defer l.profileStart(id.Ref(), info.Path, stageDecode)()
+	sampleRate := oggSampleRate(info.Data)
+	if info.Data == nil {
+		var err error
+		sampleRate, err = peekOGGSampleRate(r)
 		if err != nil {
-			panic(err.Error())
+			panic(newError("read", id.Ref(), info.Path, err))
+		}
+	}
+	stream, err := vorbis.DecodeWithoutResampling(r)
+	if err != nil {
+		panic(l.decodeError("decode", id.Ref(), info.Path, info.Data, err))
+	}
+	return &oggStream{Stream: stream, src: r, sampleRate: sampleRate}
+}
+
+func (l *Loader) loadCustomAudio(id AudioID, info AudioInfo) (Audio, bool) {
+	if l.CustomAudioLoader == nil {
+		// Can't load a new custom audio resource without this function.
+		return Audio{}, false
+	}
+	r := l.openResource(id.Ref(), info.Path, info.Data)
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(newError("read", id.Ref(), info.Path, err))
 		}
-		a = l.createAudioObject(player, id, info)
-		l.customAudio[id] = a
+	}()
+	stream := l.CustomAudioLoader(r, info)
+	if stream == nil {
+		return Audio{}, false
 	}
+	player, err := l.audioContext.NewPlayer(l.maybeWrapAudioStream(stream, info))
+	if err != nil {
+		panic(err.Error())
+	}
+	a := l.createAudioObject(player, id, info, stream)
+	l.loadDependencies(id.Ref(), info.DependsOn)
+	l.customAudio[id] = a
+	l.emit(EventLoad, id.Ref())
 	return a, true
 }
 
+// LoadConfig returns a Config resource associated with a given key.
+// Only a first call for this id will lead to resource reading,
+// all next calls return the cached result.
+//
+// Use DecodeConfig function to get the decoded config value.
+func (l *Loader) LoadConfig(id ConfigID) Config {
+	return *l.loadConfig(id)
+}
+
+func (l *Loader) loadConfig(id ConfigID) *Config {
+	id = l.ConfigRegistry.resolve(id)
+	c, ok := l.configs[id]
+	if !ok {
+		configInfo, ok := l.ConfigRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		configInfo = applyVariant(l, id.Ref(), configInfo)
+		configInfo.Path = l.platformPath(configInfo.Path, configInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), configInfo.Path, configInfo.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), configInfo.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), configInfo.Path, err))
+		}
+		c = &Config{
+			ID:     id,
+			Data:   data,
+			path:   configInfo.Path,
+			values: make(map[reflect.Type]any),
+		}
+		l.loadDependencies(id.Ref(), configInfo.DependsOn)
+		l.configs[id] = c
+		l.emit(EventLoad, id.Ref())
+		if configInfo.Soft {
+			l.addSoft(id.Ref(), int64(len(data)))
+		}
+	}
+	return c
+}
+
+// GetConfigInfo extracts the config info associated with a given key.
+func (l *Loader) GetConfigInfo(id ConfigID) ConfigInfo {
+	id = l.ConfigRegistry.resolve(id)
+	return l.ConfigRegistry.mapping[id]
+}
+
+// DecodeConfig returns the Config resource value decoded as T.
+// The decoder is selected by the config path extension (see Loader.ConfigDecoders);
+// the path is resolved when the config is loaded.
+//
+// Only JSON is decoded out of the box: this package doesn't depend on
+// the YAML and TOML libraries, so these formats require a registered decoder.
+//
+// Only a first call for this (id, T) pair will lead to the decoding,
+// all next calls return the cached result.
+// Note that the cached value is returned as is, so if T is a map or a
+// pointer type, the returned values share the same underlying data.
+func DecodeConfig[T any](l *Loader, id ConfigID) T {
+	c := l.loadConfig(id)
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if v, ok := c.values[typ]; ok {
+		return v.(T)
+	}
+	path := c.path
+	decode, ok := l.ConfigDecoders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		panic(newError("decode", c.ID.Ref(), path, fmt.Errorf("no decoder for %q extension", filepath.Ext(path))))
+	}
+	var v T
+	if err := decode(c.Data, &v); err != nil {
+		panic(l.decodeError("decode", c.ID.Ref(), path, c.Data, err))
+	}
+	c.values[typ] = v
+	return v
+}
+
+// fontDPI is used to rasterize both Font and SDFFont glyphs,
+// so the same Size results in the same glyph metrics.
+const fontDPI = 96
+
 // LoadFont returns a Font resource associated with a given key.
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
 func (l *Loader) LoadFont(id FontID) Font {
+	id = l.FontRegistry.resolve(id)
 	f, ok := l.fonts[id]
 	if !ok {
 		fontInfo, ok := l.FontRegistry.mapping[id]
 		if !ok {
-			panic(fmt.Sprintf("unregistered font with id=%d", id))
+			panic(l.notRegisteredError(id.Ref()))
 		}
-		r := l.OpenAssetFunc(fontInfo.Path)
+		fontInfo = applyVariant(l, id.Ref(), fontInfo)
+		fontInfo.Path = l.platformPath(fontInfo.Path, fontInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), fontInfo.Path, fontInfo.Data)
 		defer func() {
 			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q font reader: %v", fontInfo.Path, err))
+				panic(newError("read", id.Ref(), fontInfo.Path, err))
 			}
 		}()
 		fontData, err := io.ReadAll(r)
 		if err != nil {
-			panic(fmt.Sprintf("reading %q data: %v", fontInfo.Path, err))
+			panic(newError("read", id.Ref(), fontInfo.Path, err))
 		}
+		done := l.profileStart(id.Ref(), fontInfo.Path, stageDecode)
 		tt, err := opentype.Parse(fontData)
 		if err != nil {
-			panic(fmt.Sprintf("parsing %q font: %v", fontInfo.Path, err))
+			panic(l.decodeError("parse", id.Ref(), fontInfo.Path, fontData, err))
 		}
 		face, err := opentype.NewFace(tt, &opentype.FaceOptions{
 			Size:    float64(fontInfo.Size),
-			DPI:     96,
+			DPI:     fontDPI,
 			Hinting: font.HintingFull,
 		})
 		if err != nil {
-			panic(fmt.Sprintf("creating a font face for %q: %v", fontInfo.Path, err))
+			panic(l.decodeError("decode", id.Ref(), fontInfo.Path, fontData, err))
+		}
+		done()
+		if fontInfo.Instance != "" || len(fontInfo.Axes) != 0 {
+			coords, err := fontVariation(tt, fontData, fontInfo.Instance, fontInfo.Axes)
+			if err != nil {
+				panic(newError("validate", id.Ref(), fontInfo.Path, err))
+			}
+			if l.WarningFunc != nil {
+				err := fmt.Errorf("%w, using the default instance instead of %s", errFontVariations, coords)
+				l.WarningFunc(newError("decode", id.Ref(), fontInfo.Path, err))
+			}
 		}
 		if fontInfo.LineSpacing != 0 && fontInfo.LineSpacing != 1 {
 			h := float64(face.Metrics().Height.Round()) * fontInfo.LineSpacing
 			face = text.FaceWithLineHeight(face, math.Round(h))
 		}
+		if len(fontInfo.GlyphFilter) != 0 {
+			face = &filteredFace{Face: face, ranges: fontInfo.GlyphFilter}
+		}
+		if fontInfo.PreloadGlyphs != "" {
+			text.CacheGlyphs(face, fontInfo.PreloadGlyphs)
+		}
+		for _, rawID := range fontInfo.PreloadGlyphsFrom {
+			text.CacheGlyphs(face, uniqueRunes(l.LoadRaw(rawID).Data))
+		}
 		f = Font{
 			ID:   id,
 			Face: face,
 		}
+		l.loadDependencies(id.Ref(), fontInfo.DependsOn)
 		l.fonts[id] = f
+		l.emit(EventLoad, id.Ref())
 	}
 	return f
 }
 
 // GetFontInfo extracts the font info associated with a given key.
 func (l *Loader) GetFontInfo(id FontID) FontInfo {
+	id = l.FontRegistry.resolve(id)
 	return l.FontRegistry.mapping[id]
 }
 
@@ -265,127 +1138,2404 @@ func (l *Loader) GetFontInfo(id FontID) FontInfo {
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
 func (l *Loader) LoadImage(id ImageID) Image {
+	id = l.ImageRegistry.resolve(id)
+	l.touch(id.Ref())
 	img, ok := l.images[id]
 	if !ok {
 		imageInfo, ok := l.ImageRegistry.mapping[id]
 		if !ok {
-			panic(fmt.Sprintf("unregistered image with id=%d", id))
+			panic(l.notRegisteredError(id.Ref()))
 		}
-		r := l.OpenAssetFunc(imageInfo.Path)
-		defer func() {
-			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q image reader: %v", imageInfo.Path, err))
-			}
-		}()
-		rawImage, _, err := image.Decode(r)
-		if err != nil {
-			panic(fmt.Sprintf("decode %q image: %v", imageInfo.Path, err))
+		rawImage := l.decodeImage(id, imageInfo)
+		rawImage = processImage(rawImage, imageInfo)
+		done := l.profileStart(id.Ref(), "", stageUpload)
+		img = l.uploadImage(id, imageInfo, rawImage)
+		done()
+		l.loadDependencies(id.Ref(), imageInfo.DependsOn)
+		l.images[id] = img
+		l.addTextureMemory(img.textureBytes())
+		l.lruSize += img.textureBytes()
+		l.emit(EventLoad, id.Ref())
+		if imageInfo.SplitFrames {
+			l.splitImageFrames(id, img)
 		}
-		data := ebiten.NewImageFromImage(rawImage)
-		img = Image{
-			ID:                 id,
-			Data:               data,
-			DefaultFrameWidth:  imageInfo.FrameWidth,
-			DefaultFrameHeight: imageInfo.FrameHeight,
+		l.checkTextureBudget()
+		l.evictLRU(id.Ref())
+	}
+	return img
+}
+
+// ReloadImage decodes the Image resource associated with a given key again
+// and uploads the new pixels into the already allocated textures.
+// If the image is not loaded yet, it's identical to LoadImage.
+//
+// Since the *ebiten.Image objects are reused, the references held by
+// the game objects remain valid and see the new pixels.
+// This is useful for the hot reloading and the streaming worlds
+// that replace the region textures of the same size.
+//
+// If the new image size is different, a new texture is allocated instead
+// and the old one is disposed, so the stale references must not be used.
+// The derived images (like tinted ones and frames) are disposed too,
+// they're created again on the next use.
+func (l *Loader) ReloadImage(id ImageID) Image {
+	id = l.ImageRegistry.resolve(id)
+	img, ok := l.images[id]
+	if !ok {
+		return l.LoadImage(id)
+	}
+	oldBytes := img.textureBytes()
+	l.touch(id.Ref())
+	imageInfo, ok := l.ImageRegistry.mapping[id]
+	if !ok {
+		panic(l.notRegisteredError(id.Ref()))
+	}
+	rawImage := l.decodeImage(id, imageInfo)
+	rawImage = processImage(rawImage, imageInfo)
+	l.unloadImageVariants(id, true)
+	done := l.profileStart(id.Ref(), "", stageUpload)
+	defer done()
+	old := img
+	if img.Tiled != nil || l.exceedsMaxTextureSize(rawImage) {
+		if img.Tiled == nil || !writeTiledImage(img.Tiled, rawImage) {
+			img = l.uploadImage(id, imageInfo, rawImage)
 		}
+		disposeReplacedTextures(old, img)
 		l.images[id] = img
+		l.addTextureMemory(img.textureBytes() - oldBytes)
+		l.lruSize += img.textureBytes() - oldBytes
+		l.emit(EventReload, id.Ref())
+		l.checkTextureBudget()
+		return img
+	}
+	if !writeEbitenImage(img.Data, rawImage) {
+		img.Data = newEbitenImage(rawImage, imageInfo)
+	}
+	levels := scaleMipmaps(rawImage, imageInfo.Mipmaps)
+	mipmaps := make([]*ebiten.Image, len(levels))
+	for i, level := range levels {
+		if i < len(img.Mipmaps) && writeEbitenImage(img.Mipmaps[i], level) {
+			mipmaps[i] = img.Mipmaps[i]
+		} else {
+			mipmaps[i] = newEbitenImage(level, imageInfo)
+		}
 	}
+	if len(mipmaps) == 0 {
+		mipmaps = nil
+	}
+	img.Mipmaps = mipmaps
+	disposeReplacedTextures(old, img)
+	l.images[id] = img
+	l.addTextureMemory(img.textureBytes() - oldBytes)
+	l.lruSize += img.textureBytes() - oldBytes
+	l.emit(EventReload, id.Ref())
+	if imageInfo.SplitFrames {
+		l.splitImageFrames(id, img)
+	}
+	l.checkTextureBudget()
 	return img
 }
 
-// GetImageInfo extracts the image info associated with a given key.
-func (l *Loader) GetImageInfo(id ImageID) ImageInfo {
-	return l.ImageRegistry.mapping[id]
+// disposeReplacedTextures disposes the old image textures
+// that are not reused by the new image.
+func disposeReplacedTextures(old, img Image) {
+	reused := make(map[*ebiten.Image]struct{})
+	for _, t := range img.textures() {
+		reused[t] = struct{}{}
+	}
+	for _, t := range old.textures() {
+		if _, ok := reused[t]; !ok {
+			t.Dispose()
+		}
+	}
 }
 
-// LoadShader returns a Shader resource associated with a given key.
+func (l *Loader) decodeImage(id ImageID, imageInfo ImageInfo) image.Image {
+	rawImage := l.decodeFullImage(id, imageInfo)
+	if imageInfo.SourceRect.Empty() {
+		return rawImage
+	}
+	b := rawImage.Bounds()
+	rect := imageInfo.SourceRect.Add(b.Min)
+	if !rect.In(b) {
+		err := fmt.Errorf("source rect %v is out of the image bounds %v", imageInfo.SourceRect, b.Sub(b.Min))
+		panic(newError("decode", id.Ref(), imageInfo.Path, err))
+	}
+	return subImage(rawImage, rect)
+}
+
+func (l *Loader) decodeFullImage(id ImageID, imageInfo ImageInfo) image.Image {
+	if imageInfo.PackChannels != [4]ImageID{} {
+		l.trackUsage(id.Ref())
+		return l.packChannels(id, imageInfo)
+	}
+	if imageInfo.Generate != nil {
+		l.trackUsage(id.Ref())
+		return imageInfo.Generate()
+	}
+	imageInfo = applyVariant(l, id.Ref(), imageInfo)
+	imageInfo.Path = l.platformPath(imageInfo.Path, imageInfo.PathByPlatform)
+	r := l.openResource(id.Ref(), imageInfo.Path, imageInfo.Data)
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(newError("read", id.Ref(), imageInfo.Path, err))
+		}
+	}()
+	done := l.profileStart(id.Ref(), imageInfo.Path, stageDecode)
+	var rawImage image.Image
+	var err error
+	if l.SharedCache != nil {
+		rawImage, err = l.decodeSharedImage(r)
+	} else {
+		rawImage, _, err = image.Decode(r)
+	}
+	if err != nil {
+		panic(l.decodeError("decode", id.Ref(), imageInfo.Path, imageInfo.Data, err))
+	}
+	done()
+	return rawImage
+}
+
+// LoadImageTinted returns a recolored variant of the Image resource
+// associated with a given key.
+// The variant pixels are the original pixels multiplied by the tint color,
+// the same way ColorM.ScaleWithColor would do that.
+//
+// Every unique (id, tint) pair is rendered only once and cached,
+// so it's cheaper to use than applying a color matrix on every draw call.
+// This is useful for team colors and status effects.
+//
+// Unloading the original image unloads all of its variants too.
+func (l *Loader) LoadImageTinted(id ImageID, tint color.Color) Image {
+	id = l.ImageRegistry.resolve(id)
+	key := imageVariantKey{id: id, variant: color.RGBAModel.Convert(tint).(color.RGBA)}
+	img, ok := l.imageVariant(key)
+	if !ok {
+		src := l.LoadImage(id)
+		if src.Tiled != nil {
+			panic(newError("validate", id.Ref(), l.refPath(id.Ref()), errors.New("tiled images can't be tinted")))
+		}
+		var options ebiten.DrawImageOptions
+		options.ColorM.ScaleWithColor(tint)
+		w, h := src.Data.Size()
+		data := ebiten.NewImageWithOptions(image.Rect(0, 0, w, h), &ebiten.NewImageOptions{
+			Unmanaged: l.ImageRegistry.mapping[id].Unmanaged,
+		})
+		data.DrawImage(src.Data, &options)
+		img = src
+		img.Data = data
+		l.storeImageVariant(key, img)
+	}
+	return img
+}
+
+// LoadSDFFont returns an SDFFont resource associated with a given key.
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
-func (l *Loader) LoadShader(id ShaderID) Shader {
-	shader, ok := l.shaders[id]
+//
+// Note that the signed distance field generation is quite expensive,
+// so it's better to preload these resources.
+func (l *Loader) LoadSDFFont(id SDFFontID) SDFFont {
+	id = l.SDFFontRegistry.resolve(id)
+	f, ok := l.sdfFonts[id]
 	if !ok {
-		shaderInfo, ok := l.ShaderRegistry.mapping[id]
+		fontInfo, ok := l.SDFFontRegistry.mapping[id]
 		if !ok {
-			panic(fmt.Sprintf("unregistered shader with id=%d", id))
+			panic(l.notRegisteredError(id.Ref()))
 		}
-		r := l.OpenAssetFunc(shaderInfo.Path)
+		fontInfo = applyVariant(l, id.Ref(), fontInfo)
+		fontInfo.Path = l.platformPath(fontInfo.Path, fontInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), fontInfo.Path, fontInfo.Data)
 		defer func() {
 			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q shader reader: %v", shaderInfo.Path, err))
+				panic(newError("read", id.Ref(), fontInfo.Path, err))
 			}
 		}()
-		data, err := io.ReadAll(r)
+		fontData, err := io.ReadAll(r)
 		if err != nil {
-			panic(fmt.Sprintf("read %q shader: %v", shaderInfo.Path, err))
+			panic(newError("read", id.Ref(), fontInfo.Path, err))
+		}
+		var atlas *image.Alpha
+		f, atlas = l.rasterizeSDFFont(id, fontInfo, fontData)
+		f.ID = id
+		f.Atlas = newSDFAtlas(atlas)
+		l.loadDependencies(id.Ref(), fontInfo.DependsOn)
+		l.sdfFonts[id] = f
+		l.addTextureMemory(textureBytes(f.Atlas))
+		l.emit(EventLoad, id.Ref())
+		l.checkTextureBudget()
+	}
+	return f
+}
+
+// rasterizeSDFFont generates the SDF font glyphs.
+// The result is read from the disk cache if it's enabled (see DiskCacheDir).
+func (l *Loader) rasterizeSDFFont(id SDFFontID, info SDFFontInfo, fontData []byte) (SDFFont, *image.Alpha) {
+	var cachePath string
+	if l.DiskCacheDir != "" {
+		cachePath = filepath.Join(l.DiskCacheDir, sdfCacheKey(info, fontData)+".sdf")
+		if f, atlas, ok := readSDFCache(cachePath); ok {
+			return f, atlas
+		}
+	}
+	tt, err := opentype.Parse(fontData)
+	if err != nil {
+		panic(l.decodeError("parse", id.Ref(), info.Path, fontData, err))
+	}
+	f, atlas, err := generateSDFFont(tt, info)
+	if err != nil {
+		panic(l.decodeError("decode", id.Ref(), info.Path, fontData, err))
+	}
+	if cachePath != "" {
+		writeSDFCache(cachePath, f, atlas)
+	}
+	return f, atlas
+}
+
+// GetSDFFontInfo extracts the sdf font info associated with a given key.
+func (l *Loader) GetSDFFontInfo(id SDFFontID) SDFFontInfo {
+	id = l.SDFFontRegistry.resolve(id)
+	return l.SDFFontRegistry.mapping[id]
+}
+
+// GetImageInfo extracts the image info associated with a given key.
+func (l *Loader) GetImageInfo(id ImageID) ImageInfo {
+	id = l.ImageRegistry.resolve(id)
+	return l.ImageRegistry.mapping[id]
+}
+
+// LoadShader returns a Shader resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadShader(id ShaderID) Shader {
+	id = l.ShaderRegistry.resolve(id)
+	shader, ok := l.shaders[id]
+	if !ok {
+		shaderInfo, ok := l.ShaderRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		shaderInfo = applyVariant(l, id.Ref(), shaderInfo)
+		shaderInfo.Path = l.platformPath(shaderInfo.Path, shaderInfo.PathByPlatform)
+		shader = l.compileShader(id, shaderInfo, l.shaderSource(id, shaderInfo))
+	}
+	return shader
+}
+
+// CompileAllShaders loads (and compiles) every registered shader.
+// It's intended to be called during the loading screen, so the first
+// frame that uses a shader effect doesn't hitch on the Kage compilation.
+//
+// If parallel is true, the shader sources are read concurrently
+// while the compilation itself is still sequential.
+// The assets are opened on the caller goroutine, so OpenAssetFunc
+// (and the Use middlewares) don't have to be safe for a concurrent use,
+// but the returned readers are read (and closed) by other goroutines.
+//
+// If a shader can't be loaded, the shaders compiled before it
+// stay loaded and the error is re-panicked on the caller goroutine.
+func (l *Loader) CompileAllShaders(parallel bool) {
+	var ids []ShaderID
+	var infos []ShaderInfo
+	for _, id := range l.ShaderRegistry.OrderedIDs() {
+		if _, ok := l.shaders[id]; ok {
+			continue
+		}
+		info := l.ShaderRegistry.mapping[id]
+		info = applyVariant(l, id.Ref(), info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		ids = append(ids, id)
+		infos = append(infos, info)
+	}
+	if !parallel {
+		for i, id := range ids {
+			l.compileShader(id, infos[i], l.shaderSource(id, infos[i]))
+		}
+		return
+	}
+
+	sources := make([]shaderSourceReader, len(ids))
+	defer func() {
+		// Only the readers that were not read are closed here:
+		// it's a panic while opening the shader sources.
+		for i := range sources {
+			sources[i].closeAll()
+		}
+	}()
+	for i, id := range ids {
+		l.openShaderSource(&sources[i], id, infos[i])
+	}
+	var wg sync.WaitGroup
+	wg.Add(len(ids))
+	for i := range sources {
+		go func(src *shaderSourceReader) {
+			defer wg.Done()
+			src.read()
+		}(&sources[i])
+	}
+	wg.Wait()
+	for i, id := range ids {
+		if sources[i].err != nil {
+			panic(sources[i].err)
+		}
+		l.compileShader(id, infos[i], sources[i].data)
+	}
+}
+
+// shaderSourceReader reads the shader source files that are
+// opened by the Loader, so they can be read outside of the caller goroutine.
+type shaderSourceReader struct {
+	ref   Ref
+	paths []string
+
+	// readers are the opened paths.
+	// A nil reader stands for the info.Data.
+	readers []io.ReadCloser
+
+	infoData []byte
+
+	data []byte
+	err  *Error
+}
+
+func (l *Loader) openShaderSource(src *shaderSourceReader, id ShaderID, info ShaderInfo) {
+	src.ref = id.Ref()
+	for _, path := range info.LibraryPaths {
+		src.paths = append(src.paths, path)
+		src.readers = append(src.readers, l.openAsset(src.ref, path))
+	}
+	src.paths = append(src.paths, info.Path)
+	if info.Data != nil {
+		src.infoData = info.Data
+		src.readers = append(src.readers, nil)
+	} else {
+		src.readers = append(src.readers, l.openAsset(src.ref, info.Path))
+	}
+}
+
+// read joins the shader sources just like the Loader.shaderSource does.
+// It doesn't access the loader state, so it can be called concurrently.
+func (src *shaderSourceReader) read() {
+	defer src.closeAll()
+	for i, r := range src.readers {
+		if i != 0 {
+			src.data = append(src.data, '\n')
+		}
+		if r == nil {
+			src.data = append(src.data, src.infoData...)
+			continue
+		}
+		data, err := io.ReadAll(r)
+		if closeErr := r.Close(); err == nil {
+			err = closeErr
 		}
-		rawShader, err := ebiten.NewShader(data)
+		src.readers[i] = nil
 		if err != nil {
-			panic(fmt.Sprintf("compile %q shader: %v", shaderInfo.Path, err))
+			src.err = newError("read", src.ref, src.paths[i], err)
+			return
 		}
-		shader = Shader{
-			ID:   id,
-			Data: rawShader,
+		src.data = append(src.data, data...)
+	}
+}
+
+func (src *shaderSourceReader) closeAll() {
+	for i, r := range src.readers {
+		if r != nil {
+			r.Close()
+			src.readers[i] = nil
+		}
+	}
+}
+
+func (l *Loader) shaderSource(id ShaderID, info ShaderInfo) []byte {
+	var data []byte
+	for _, path := range info.LibraryPaths {
+		data = append(data, l.readShaderSource(id, path)...)
+		data = append(data, '\n')
+	}
+	if info.Data != nil {
+		data = append(data, info.Data...)
+	} else {
+		data = append(data, l.readShaderSource(id, info.Path)...)
+	}
+	return data
+}
+
+func (l *Loader) compileShader(id ShaderID, info ShaderInfo, data []byte) Shader {
+	if len(info.DefaultUniforms) != 0 {
+		if err := validateShaderUniforms(data, info.DefaultUniforms); err != nil {
+			panic(newError("validate", id.Ref(), info.Path, fmt.Errorf("shader uniforms: %w", err)))
 		}
-		l.shaders[id] = shader
 	}
+	done := l.profileStart(id.Ref(), info.Path, stageDecode)
+	rawShader, err := ebiten.NewShader(data)
+	done()
+	if err != nil {
+		panic(newError("compile", id.Ref(), info.Path, err))
+	}
+	l.trackUsage(id.Ref())
+	shader := Shader{
+		ID:              id,
+		Data:            rawShader,
+		DefaultUniforms: info.DefaultUniforms,
+	}
+	l.loadDependencies(id.Ref(), info.DependsOn)
+	l.shaders[id] = shader
+	l.emit(EventLoad, id.Ref())
 	return shader
 }
 
+func (l *Loader) readShaderSource(id ShaderID, path string) []byte {
+	r := l.openAsset(id.Ref(), path)
+	defer func() {
+		if err := r.Close(); err != nil {
+			panic(newError("read", id.Ref(), path, err))
+		}
+	}()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		panic(newError("read", id.Ref(), path, err))
+	}
+	return data
+}
+
+// GetShaderInfo extracts the shader info associated with a given key.
+func (l *Loader) GetShaderInfo(id ShaderID) ShaderInfo {
+	id = l.ShaderRegistry.resolve(id)
+	return l.ShaderRegistry.mapping[id]
+}
+
 // LoadRaw returns a Raw resource associated with a given key.
 // Only a first call for this id will lead to resource decoding,
 // all next calls return the cached result.
 func (l *Loader) LoadRaw(id RawID) Raw {
+	id = l.RawRegistry.resolve(id)
+	l.touch(id.Ref())
 	raw, ok := l.raws[id]
 	if !ok {
 		rawInfo, ok := l.RawRegistry.mapping[id]
 		if !ok {
-			panic(fmt.Sprintf("unregistered raw with id=%d", id))
+			panic(l.notRegisteredError(id.Ref()))
 		}
-		r := l.OpenAssetFunc(rawInfo.Path)
+		rawInfo = applyVariant(l, id.Ref(), rawInfo)
+		rawInfo.Path = l.platformPath(rawInfo.Path, rawInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), rawInfo.Path, rawInfo.Data)
 		defer func() {
 			if err := r.Close(); err != nil {
-				panic(fmt.Sprintf("closing %q raw reader: %v", rawInfo.Path, err))
+				panic(newError("read", id.Ref(), rawInfo.Path, err))
 			}
 		}()
 		data, err := io.ReadAll(r)
 		if err != nil {
-			panic(fmt.Sprintf("read %q raw: %v", rawInfo.Path, err))
+			panic(newError("read", id.Ref(), rawInfo.Path, err))
 		}
 		raw = Raw{
 			ID:   id,
 			Data: data,
 		}
+		l.loadDependencies(id.Ref(), rawInfo.DependsOn)
 		l.raws[id] = raw
+		l.lruSize += int64(len(data))
+		l.emit(EventLoad, id.Ref())
+		if rawInfo.Soft {
+			l.addSoft(id.Ref(), int64(len(data)))
+		}
+		l.evictLRU(id.Ref())
 	}
 	return raw
 }
 
+// LoadTemplate returns a Template resource associated with a given key.
+// Only a first call for this id will lead to resource parsing,
+// all next calls return the cached result.
+func (l *Loader) LoadTemplate(id TemplateID) Template {
+	id = l.TemplateRegistry.resolve(id)
+	t, ok := l.templates[id]
+	if !ok {
+		templateInfo, ok := l.TemplateRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		templateInfo = applyVariant(l, id.Ref(), templateInfo)
+		templateInfo.Path = l.platformPath(templateInfo.Path, templateInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), templateInfo.Path, templateInfo.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), templateInfo.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), templateInfo.Path, err))
+		}
+		tmpl, err := template.New(templateInfo.Path).Funcs(templateInfo.Funcs).Parse(string(data))
+		if err != nil {
+			panic(l.decodeError("parse", id.Ref(), templateInfo.Path, data, err))
+		}
+		t = Template{
+			ID:       id,
+			Template: tmpl,
+		}
+		l.loadDependencies(id.Ref(), templateInfo.DependsOn)
+		l.templates[id] = t
+		l.emit(EventLoad, id.Ref())
+	}
+	return t
+}
+
+// GetTemplateInfo extracts the text template info associated with a given key.
+func (l *Loader) GetTemplateInfo(id TemplateID) TemplateInfo {
+	id = l.TemplateRegistry.resolve(id)
+	return l.TemplateRegistry.mapping[id]
+}
+
+// LoadScript returns a Script resource associated with a given key.
+// Only a first call for this id will lead to resource reading and compilation,
+// all next calls return the cached result.
+//
+// See also: Loader.CompileScriptFunc.
+func (l *Loader) LoadScript(id ScriptID) Script {
+	id = l.ScriptRegistry.resolve(id)
+	script, ok := l.scripts[id]
+	if !ok {
+		scriptInfo, ok := l.ScriptRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		scriptInfo = applyVariant(l, id.Ref(), scriptInfo)
+		scriptInfo.Path = l.platformPath(scriptInfo.Path, scriptInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), scriptInfo.Path, scriptInfo.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), scriptInfo.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), scriptInfo.Path, err))
+		}
+		script = Script{
+			ID:     id,
+			Source: data,
+		}
+		if l.CompileScriptFunc != nil {
+			compiled, err := l.CompileScriptFunc(scriptInfo, data)
+			if err != nil {
+				panic(newError("compile", id.Ref(), scriptInfo.Path, err))
+			}
+			script.Compiled = compiled
+		}
+		l.loadDependencies(id.Ref(), scriptInfo.DependsOn)
+		l.scripts[id] = script
+		l.emit(EventLoad, id.Ref())
+	}
+	return script
+}
+
+// GetScriptInfo extracts the script info associated with a given key.
+func (l *Loader) GetScriptInfo(id ScriptID) ScriptInfo {
+	id = l.ScriptRegistry.resolve(id)
+	return l.ScriptRegistry.mapping[id]
+}
+
+// LoadParticleDef returns a ParticleDef resource associated with a given key.
+// Only a first call for this id will lead to resource decoding and validation,
+// all next calls return the cached result.
+func (l *Loader) LoadParticleDef(id ParticleDefID) ParticleDef {
+	return loadDecoded(l, &l.ParticleDefRegistry, l.particleDefs, id, decodedKind[ParticleDefID, ParticleDefInfo, ParticleDef]{
+		op: "decode",
+		fields: func(info *ParticleDefInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id ParticleDefID, info ParticleDefInfo, r io.Reader) (ParticleDef, []Ref, error) {
+			def, err := decodeParticleDef(r, info, &l.ImageRegistry)
+			def.ID = id
+			deps := make([]Ref, 0, len(def.Emitters))
+			for _, e := range def.Emitters {
+				deps = append(deps, e.Image.Ref())
+			}
+			return def, deps, err
+		},
+	})
+}
+
+// GetParticleDefInfo extracts the particle def info associated with a given key.
+func (l *Loader) GetParticleDefInfo(id ParticleDefID) ParticleDefInfo {
+	id = l.ParticleDefRegistry.resolve(id)
+	return l.ParticleDefRegistry.mapping[id]
+}
+
+// LoadHitboxes returns a Hitboxes resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadHitboxes(id HitboxesID) Hitboxes {
+	return loadDecoded(l, &l.HitboxesRegistry, l.hitboxes, id, decodedKind[HitboxesID, HitboxesInfo, Hitboxes]{
+		op: "decode",
+		fields: func(info *HitboxesInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id HitboxesID, info HitboxesInfo, r io.Reader) (Hitboxes, []Ref, error) {
+			frames, err := decodeHitboxes(r)
+			return Hitboxes{ID: id, Image: info.Image, frames: frames}, nil, err
+		},
+	})
+}
+
+// GetHitboxesInfo extracts the hitboxes info associated with a given key.
+func (l *Loader) GetHitboxesInfo(id HitboxesID) HitboxesInfo {
+	id = l.HitboxesRegistry.resolve(id)
+	return l.HitboxesRegistry.mapping[id]
+}
+
+// LoadTileSet returns a TileSet resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadTileSet(id TileSetID) TileSet {
+	return loadDecoded(l, &l.TileSetRegistry, l.tileSets, id, decodedKind[TileSetID, TileSetInfo, TileSet]{
+		op: "decode",
+		fields: func(info *TileSetInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id TileSetID, info TileSetInfo, r io.Reader) (TileSet, []Ref, error) {
+			ts, err := decodeTileSet(r, info.Path)
+			ts.ID = id
+			ts.Image = info.Image
+			return ts, []Ref{info.Image.Ref()}, err
+		},
+	})
+}
+
+// GetTileSetInfo extracts the tile set info associated with a given key.
+func (l *Loader) GetTileSetInfo(id TileSetID) TileSetInfo {
+	id = l.TileSetRegistry.resolve(id)
+	return l.TileSetRegistry.mapping[id]
+}
+
+// LoadTable returns a Table resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadTable(id TableID) Table {
+	return loadDecoded(l, &l.TableRegistry, l.tables, id, decodedKind[TableID, TableInfo, Table]{
+		op: "parse",
+		fields: func(info *TableInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id TableID, info TableInfo, r io.Reader) (Table, []Ref, error) {
+			t, err := parseTable(r, info)
+			t.ID = id
+			return t, nil, err
+		},
+	})
+}
+
+// GetTableInfo extracts the table info associated with a given key.
+func (l *Loader) GetTableInfo(id TableID) TableInfo {
+	id = l.TableRegistry.resolve(id)
+	return l.TableRegistry.mapping[id]
+}
+
+// SaveRaw writes data as the new contents of the Raw resource.
+// If this resource is loaded, its cached value is replaced with data as well.
+//
+// It's intended to be used by the level editors and other tools
+// that modify the game content during the run time.
+// Note that a resource with a checksum would fail the verification
+// after it's modified, so its Checksums entry should be updated too.
+//
+// This method panics if the WriteAssetFunc is not set.
+func (l *Loader) SaveRaw(id RawID, data []byte) error {
+	id = l.RawRegistry.resolve(id)
+	rawInfo, ok := l.RawRegistry.mapping[id]
+	if !ok {
+		panic(l.notRegisteredError(id.Ref()))
+	}
+	rawInfo.Path = l.platformPath(rawInfo.Path, rawInfo.PathByPlatform)
+	if err := l.writeAsset(id.Ref(), rawInfo.Path, data); err != nil {
+		return err
+	}
+	if raw, ok := l.raws[id]; ok {
+		l.lruSize += int64(len(data) - len(raw.Data))
+		raw.Data = data
+		l.raws[id] = raw
+		l.emit(EventReload, id.Ref())
+	}
+	return nil
+}
+
+// WriteAsset writes data to the given path using the WriteAssetFunc.
+// Unlike SaveRaw, it can be used for the unregistered assets, like screenshots.
+//
+// The path is mapped in the same way as it's done for the reading
+// (path variables, normalization and redirects), so the written asset
+// is found by the next load.
+//
+// This method panics if the WriteAssetFunc is not set.
+func (l *Loader) WriteAsset(path string, data []byte) error {
+	return l.writeAsset(Ref{}, path, data)
+}
+
+// writeAsset implements WriteAsset.
+// The ref is the resource being written, it can be a zero value.
+func (l *Loader) writeAsset(ref Ref, path string, data []byte) error {
+	if l.WriteAssetFunc == nil {
+		panic(fmt.Sprintf("write %q: WriteAssetFunc is not set", path))
+	}
+	mapped := l.mapAssetPath(ref, path)
+	if err := l.WriteAssetFunc(l.rewritePath(mapped), data); err != nil {
+		return fmt.Errorf("write %q: %w", path, err)
+	}
+	l.evictPatchedAssets(mapped)
+	return nil
+}
+
 // GetRawInfo extracts the raw info associated with a given key.
 func (l *Loader) GetRawInfo(id RawID) RawInfo {
+	id = l.RawRegistry.resolve(id)
 	return l.RawRegistry.mapping[id]
 }
 
-func (l *Loader) getAudioInfo(id AudioID) AudioInfo {
-	info, ok := l.AudioRegistry.mapping[id]
+// LoadPalette returns a Palette resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadPalette(id PaletteID) Palette {
+	id = l.PaletteRegistry.resolve(id)
+	pal, ok := l.palettes[id]
+	if !ok {
+		paletteInfo, ok := l.PaletteRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		paletteInfo = applyVariant(l, id.Ref(), paletteInfo)
+		paletteInfo.Path = l.platformPath(paletteInfo.Path, paletteInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), paletteInfo.Path, paletteInfo.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), paletteInfo.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), paletteInfo.Path, err))
+		}
+		colors, err := decodePalette(paletteInfo.Path, data)
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), paletteInfo.Path, data, err))
+		}
+		pal = Palette{
+			ID:     id,
+			Colors: colors,
+		}
+		l.loadDependencies(id.Ref(), paletteInfo.DependsOn)
+		l.palettes[id] = pal
+		l.emit(EventLoad, id.Ref())
+	}
+	return pal
+}
+
+// GetPaletteInfo extracts the palette info associated with a given key.
+func (l *Loader) GetPaletteInfo(id PaletteID) PaletteInfo {
+	id = l.PaletteRegistry.resolve(id)
+	return l.PaletteRegistry.mapping[id]
+}
+
+// LoadMaterial returns a Material resource associated with a given key.
+// Only a first call for this id will lead to resource loading,
+// all next calls return the cached result.
+func (l *Loader) LoadMaterial(id MaterialID) Material {
+	id = l.MaterialRegistry.resolve(id)
+	m, ok := l.materials[id]
 	if !ok {
-		panic(fmt.Sprintf("unregistered audio with id=%d", id))
+		materialInfo, ok := l.MaterialRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		l.trackUsage(id.Ref())
+		deps := []Ref{materialInfo.Albedo.Ref()}
+		m = Material{
+			ID:     id,
+			Albedo: l.LoadImage(materialInfo.Albedo),
+		}
+		if materialInfo.Normal != 0 {
+			m.Normal = l.LoadImage(materialInfo.Normal)
+			deps = append(deps, materialInfo.Normal.Ref())
+		}
+		if materialInfo.Emissive != 0 {
+			m.Emissive = l.LoadImage(materialInfo.Emissive)
+			deps = append(deps, materialInfo.Emissive.Ref())
+		}
+		if materialInfo.Shader != 0 {
+			m.Shader = l.LoadShader(materialInfo.Shader)
+			deps = append(deps, materialInfo.Shader.Ref())
+		}
+		l.loadDependencies(id.Ref(), deps)
+		l.materials[id] = m
+		l.emit(EventLoad, id.Ref())
 	}
-	return info
+	return m
 }
 
-func (l *Loader) createAudioObject(p *audio.Player, id AudioID, info AudioInfo) Audio {
-	volume := (info.Volume / 2) + 0.5
-	return Audio{
-		ID:     id,
-		Player: p,
-		Volume: volume,
-		Group:  info.Group,
+// GetMaterialInfo extracts the material info associated with a given key.
+func (l *Loader) GetMaterialInfo(id MaterialID) MaterialInfo {
+	id = l.MaterialRegistry.resolve(id)
+	return l.MaterialRegistry.mapping[id]
+}
+
+// LoadComposite returns a Composite resource associated with a given key.
+// Only a first call for this id will lead to resource composition,
+// all next calls return the cached result.
+func (l *Loader) LoadComposite(id CompositeID) Composite {
+	id = l.CompositeRegistry.resolve(id)
+	c, ok := l.composites[id]
+	if !ok {
+		compositeInfo, ok := l.CompositeRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		l.trackUsage(id.Ref())
+		layers := make([]Image, len(compositeInfo.Layers))
+		size := image.Pt(compositeInfo.Width, compositeInfo.Height)
+		for i, layer := range compositeInfo.Layers {
+			layers[i] = l.LoadImage(layer.Image)
+			if layers[i].Tiled != nil {
+				panic(newError("validate", id.Ref(), "", fmt.Errorf("layer image with id=%d is tiled", layer.Image)))
+			}
+			if compositeInfo.Width == 0 && compositeInfo.Height == 0 {
+				w, h := layers[i].Data.Size()
+				size = image.Rectangle{Max: size}.Union(image.Rect(0, 0, w, h).Add(layer.Offset)).Max
+			}
+		}
+		if size.X <= 0 || size.Y <= 0 {
+			panic(newError("validate", id.Ref(), "", errors.New("empty size")))
+		}
+		data := ebiten.NewImage(size.X, size.Y)
+		for i, layer := range compositeInfo.Layers {
+			var options ebiten.DrawImageOptions
+			options.GeoM.Translate(float64(layer.Offset.X), float64(layer.Offset.Y))
+			options.CompositeMode = layer.CompositeMode
+			data.DrawImage(layers[i].Data, &options)
+		}
+		c = Composite{ID: id, Data: data}
+		l.composites[id] = c
+		l.addTextureMemory(textureBytes(c.Data))
+		l.emit(EventLoad, id.Ref())
+		l.checkTextureBudget()
 	}
+	return c
 }
 
-func (l *Loader) maybeWrapAudioStream(r io.ReadSeeker, info AudioInfo) io.ReadSeeker {
-	if info.StreamDecorator != nil {
-		return info.StreamDecorator(r)
+// GetCompositeInfo extracts the composite info associated with a given key.
+func (l *Loader) GetCompositeInfo(id CompositeID) CompositeInfo {
+	id = l.CompositeRegistry.resolve(id)
+	return l.CompositeRegistry.mapping[id]
+}
+
+// LoadShaderImage returns a ShaderImage resource associated with a given key.
+// Only a first call for this id will lead to the image rendering,
+// all next calls return the cached result.
+func (l *Loader) LoadShaderImage(id ShaderImageID) ShaderImage {
+	id = l.ShaderImageRegistry.resolve(id)
+	img, ok := l.shaderImages[id]
+	if !ok {
+		info, ok := l.ShaderImageRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		if info.Width <= 0 || info.Height <= 0 {
+			panic(newError("validate", id.Ref(), "", errors.New("empty size")))
+		}
+		l.trackUsage(id.Ref())
+		shader := l.LoadShader(info.Shader)
+		var options ebiten.DrawRectShaderOptions
+		options.Uniforms = shader.Uniforms()
+		for k, v := range info.Uniforms {
+			options.Uniforms[k] = v
+		}
+		for i, imageID := range info.Images {
+			if imageID == 0 {
+				continue
+			}
+			src := l.LoadImage(imageID)
+			if src.Tiled != nil {
+				panic(newError("validate", id.Ref(), "", fmt.Errorf("source image with id=%d is tiled", imageID)))
+			}
+			options.Images[i] = src.Data
+		}
+		data := ebiten.NewImage(info.Width, info.Height)
+		data.DrawRectShader(info.Width, info.Height, shader.Data, &options)
+		img = ShaderImage{ID: id, Data: data}
+		l.shaderImages[id] = img
+		l.addTextureMemory(textureBytes(img.Data))
+		l.emit(EventLoad, id.Ref())
+		l.checkTextureBudget()
 	}
-	return r
+	return img
+}
+
+// GetShaderImageInfo extracts the shader image info associated with a given key.
+func (l *Loader) GetShaderImageInfo(id ShaderImageID) ShaderImageInfo {
+	id = l.ShaderImageRegistry.resolve(id)
+	return l.ShaderImageRegistry.mapping[id]
+}
+
+// LoadLUT returns a LUT resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadLUT(id LUTID) LUT {
+	id = l.LUTRegistry.resolve(id)
+	lut, ok := l.luts[id]
+	if !ok {
+		lutInfo, ok := l.LUTRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		lutInfo = applyVariant(l, id.Ref(), lutInfo)
+		lutInfo.Path = l.platformPath(lutInfo.Path, lutInfo.PathByPlatform)
+		r := l.openResource(id.Ref(), lutInfo.Path, lutInfo.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), lutInfo.Path, err))
+			}
+		}()
+		done := l.profileStart(id.Ref(), lutInfo.Path, stageDecode)
+		img, size, err := decodeLUT(r, lutInfo.Path)
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), lutInfo.Path, lutInfo.Data, err))
+		}
+		done()
+		lut = LUT{
+			ID:   id,
+			Size: size,
+			Data: ebiten.NewImageFromImage(img),
+		}
+		l.loadDependencies(id.Ref(), lutInfo.DependsOn)
+		l.luts[id] = lut
+		l.addTextureMemory(textureBytes(lut.Data))
+		l.emit(EventLoad, id.Ref())
+		l.checkTextureBudget()
+	}
+	return lut
+}
+
+// GetLUTInfo extracts the LUT info associated with a given key.
+func (l *Loader) GetLUTInfo(id LUTID) LUTInfo {
+	id = l.LUTRegistry.resolve(id)
+	return l.LUTRegistry.mapping[id]
+}
+
+// LoadGradient returns a Gradient resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadGradient(id GradientID) Gradient {
+	return loadDecoded(l, &l.GradientRegistry, l.gradients, id, decodedKind[GradientID, GradientInfo, Gradient]{
+		op: "decode",
+		fields: func(info *GradientInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id GradientID, info GradientInfo, r io.Reader) (Gradient, []Ref, error) {
+			g, err := decodeGradient(r)
+			g.ID = id
+			return g, nil, err
+		},
+	})
+}
+
+// GetGradientInfo extracts the gradient info associated with a given key.
+func (l *Loader) GetGradientInfo(id GradientID) GradientInfo {
+	id = l.GradientRegistry.resolve(id)
+	return l.GradientRegistry.mapping[id]
+}
+
+// LoadCurve returns a Curve resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadCurve(id CurveID) Curve {
+	return loadDecoded(l, &l.CurveRegistry, l.curves, id, decodedKind[CurveID, CurveInfo, Curve]{
+		op: "decode",
+		fields: func(info *CurveInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id CurveID, info CurveInfo, r io.Reader) (Curve, []Ref, error) {
+			c, err := decodeCurve(r)
+			c.ID = id
+			return c, nil, err
+		},
+	})
+}
+
+// GetCurveInfo extracts the curve info associated with a given key.
+func (l *Loader) GetCurveInfo(id CurveID) CurveInfo {
+	id = l.CurveRegistry.resolve(id)
+	return l.CurveRegistry.mapping[id]
+}
+
+// LoadInputProfile returns an InputProfile resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+//
+// To apply the edited bindings (hot reload), Unload the profile and load it again.
+func (l *Loader) LoadInputProfile(id InputProfileID) InputProfile {
+	return loadDecoded(l, &l.InputProfileRegistry, l.inputProfiles, id, decodedKind[InputProfileID, InputProfileInfo, InputProfile]{
+		op: "decode",
+		fields: func(info *InputProfileInfo) infoFields {
+			return infoFields{&info.Path, info.PathByPlatform, info.Data, info.DependsOn}
+		},
+		decode: func(id InputProfileID, info InputProfileInfo, r io.Reader) (InputProfile, []Ref, error) {
+			actions, err := decodeInputProfile(r, info)
+			return InputProfile{ID: id, Actions: actions}, nil, err
+		},
+	})
+}
+
+// GetInputProfileInfo extracts the input profile info associated with a given key.
+func (l *Loader) GetInputProfileInfo(id InputProfileID) InputProfileInfo {
+	id = l.InputProfileRegistry.resolve(id)
+	return l.InputProfileRegistry.mapping[id]
+}
+
+// LoadDefinition returns a Definition resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+//
+// If the definition doesn't match its schema, this method panics
+// with an *Error that wraps the *SchemaError.
+func (l *Loader) LoadDefinition(id DefinitionID) Definition {
+	id = l.DefinitionRegistry.resolve(id)
+	def, ok := l.definitions[id]
+	if !ok {
+		info, ok := l.DefinitionRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		info = applyVariant(l, id.Ref(), info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		var schema *jsonSchema
+		if info.Schema != 0 {
+			schemaRaw := l.LoadRaw(info.Schema)
+			s, err := parseJSONSchema(schemaRaw.Data)
+			if err != nil {
+				schemaInfo := l.GetRawInfo(info.Schema)
+				panic(l.decodeError("compile", info.Schema.Ref(), schemaInfo.Path, schemaRaw.Data, err))
+			}
+			schema = s
+		}
+		r := l.openResource(id.Ref(), info.Path, info.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), info.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), info.Path, err))
+		}
+		if err := validateDefinition(schema, data); err != nil {
+			panic(l.decodeError("validate", id.Ref(), info.Path, data, err))
+		}
+		def = Definition{ID: id, Data: data}
+		l.loadDependencies(id.Ref(), info.DependsOn)
+		l.definitions[id] = def
+		l.emit(EventLoad, id.Ref())
+	}
+	return def
+}
+
+// GetDefinitionInfo extracts the definition info associated with a given key.
+func (l *Loader) GetDefinitionInfo(id DefinitionID) DefinitionInfo {
+	id = l.DefinitionRegistry.resolve(id)
+	return l.DefinitionRegistry.mapping[id]
+}
+
+// LoadDocument returns a Document resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+//
+// The document fonts are tracked as its dependencies,
+// so they can't be unloaded while the document is loaded.
+func (l *Loader) LoadDocument(id DocumentID) Document {
+	id = l.DocumentRegistry.resolve(id)
+	doc, ok := l.documents[id]
+	if !ok {
+		info, ok := l.DocumentRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		info = applyVariant(l, id.Ref(), info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		r := l.openResource(id.Ref(), info.Path, info.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), info.Path, err))
+			}
+		}()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(newError("read", id.Ref(), info.Path, err))
+		}
+		lines, err := parseDocument(string(data), info)
+		if err != nil {
+			panic(l.decodeError("parse", id.Ref(), info.Path, data, err))
+		}
+		deps := append([]Ref(nil), info.DependsOn...)
+		faces := make(map[FontID]font.Face)
+		for _, line := range lines {
+			for i, span := range line {
+				face, ok := faces[span.Font]
+				if !ok {
+					face = l.LoadFont(span.Font).Face
+					faces[span.Font] = face
+					deps = append(deps, span.Font.Ref())
+				}
+				line[i].Face = face
+			}
+		}
+		doc = Document{ID: id, Lines: lines}
+		l.loadDependencies(id.Ref(), deps)
+		l.documents[id] = doc
+		l.emit(EventLoad, id.Ref())
+	}
+	return doc
+}
+
+// GetDocumentInfo extracts the document info associated with a given key.
+func (l *Loader) GetDocumentInfo(id DocumentID) DocumentInfo {
+	id = l.DocumentRegistry.resolve(id)
+	return l.DocumentRegistry.mapping[id]
+}
+
+// LoadIconSet returns an IconSet resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+//
+// The icon font and atlas are tracked as the icon set dependencies,
+// so they can't be unloaded while the icon set is loaded.
+func (l *Loader) LoadIconSet(id IconSetID) IconSet {
+	id = l.IconSetRegistry.resolve(id)
+	set, ok := l.iconSets[id]
+	if !ok {
+		info, ok := l.IconSetRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		info = applyVariant(l, id.Ref(), info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		r := l.openResource(id.Ref(), info.Path, info.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), info.Path, err))
+			}
+		}()
+		icons, err := decodeIconSet(r, info)
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), info.Path, info.Data, err))
+		}
+		set = IconSet{ID: id, Icons: icons}
+		deps := append([]Ref(nil), info.DependsOn...)
+		if info.Font != 0 {
+			set.Face = l.LoadFont(info.Font).Face
+			deps = append(deps, info.Font.Ref())
+		}
+		if info.Atlas != 0 {
+			atlas := l.LoadImage(info.Atlas)
+			if atlas.Tiled != nil {
+				panic(newError("validate", id.Ref(), info.Path, fmt.Errorf("atlas image with id=%d is tiled", info.Atlas)))
+			}
+			set.Atlas = atlas.Data
+			deps = append(deps, info.Atlas.Ref())
+		}
+		l.loadDependencies(id.Ref(), deps)
+		l.iconSets[id] = set
+		l.emit(EventLoad, id.Ref())
+	}
+	return set
+}
+
+// GetIconSetInfo extracts the icon set info associated with a given key.
+func (l *Loader) GetIconSetInfo(id IconSetID) IconSetInfo {
+	id = l.IconSetRegistry.resolve(id)
+	return l.IconSetRegistry.mapping[id]
+}
+
+// LoadTheme returns a Theme resource associated with a given key.
+// Only a first call for this id will lead to resource decoding,
+// all next calls return the cached result.
+//
+// The theme fonts and images are tracked as its dependencies,
+// so they can't be unloaded while the theme is loaded.
+// To apply the edited theme (hot reload), Unload it and load it again.
+func (l *Loader) LoadTheme(id ThemeID) Theme {
+	id = l.ThemeRegistry.resolve(id)
+	theme, ok := l.themes[id]
+	if !ok {
+		info, ok := l.ThemeRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		info = applyVariant(l, id.Ref(), info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		r := l.openResource(id.Ref(), info.Path, info.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", id.Ref(), info.Path, err))
+			}
+		}()
+		data, err := decodeTheme(r)
+		if err != nil {
+			panic(l.decodeError("decode", id.Ref(), info.Path, info.Data, err))
+		}
+		t, deps, err := l.resolveTheme(id, info, data)
+		if err != nil {
+			panic(newError("decode", id.Ref(), info.Path, err))
+		}
+		theme = t
+		l.loadDependencies(id.Ref(), append(deps, info.DependsOn...))
+		l.themes[id] = theme
+		l.emit(EventLoad, id.Ref())
+	}
+	return theme
+}
+
+// GetThemeInfo extracts the theme info associated with a given key.
+func (l *Loader) GetThemeInfo(id ThemeID) ThemeInfo {
+	id = l.ThemeRegistry.resolve(id)
+	return l.ThemeRegistry.mapping[id]
+}
+
+// LoadVariantSet returns a VariantSet resource associated with a given key.
+// Only a first call for this id will lead to the set validation,
+// all next calls return the cached result.
+func (l *Loader) LoadVariantSet(id VariantSetID) VariantSet {
+	id = l.VariantSetRegistry.resolve(id)
+	set, ok := l.variantSets[id]
+	if !ok {
+		info, ok := l.VariantSetRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		l.trackUsage(id.Ref())
+		set = newVariantSet(id, info)
+		l.variantSets[id] = set
+		l.emit(EventLoad, id.Ref())
+	}
+	return set
+}
+
+// GetVariantSetInfo extracts the variant set info associated with a given key.
+func (l *Loader) GetVariantSetInfo(id VariantSetID) VariantSetInfo {
+	id = l.VariantSetRegistry.resolve(id)
+	return l.VariantSetRegistry.mapping[id]
+}
+
+// LoadImagePaletted returns a palette-swapped variant of the Image
+// resource associated with a given key.
+// The image file should be an indexed-color image (like a paletted PNG):
+// its color indexes are remapped through the specified palette.
+// Indexes that are out of the palette range keep their original colors.
+//
+// This makes it possible to have several palette-swapped
+// enemies that are using a single source sprite sheet.
+//
+// Every unique (id, palette) pair is decoded only once and cached.
+// Unloading the original image (or the palette) unloads the variant too.
+func (l *Loader) LoadImagePaletted(id ImageID, palette PaletteID) Image {
+	id = l.ImageRegistry.resolve(id)
+	palette = l.PaletteRegistry.resolve(palette)
+	key := imageVariantKey{id: id, variant: palette}
+	img, ok := l.imageVariant(key)
+	if !ok {
+		imageInfo, ok := l.ImageRegistry.mapping[id]
+		if !ok {
+			panic(l.notRegisteredError(id.Ref()))
+		}
+		rawImage := l.decodeImage(id, imageInfo)
+		paletted, ok := rawImage.(*image.Paletted)
+		if !ok {
+			panic(newError("validate", id.Ref(), imageInfo.Path, errors.New("not an indexed-color image")))
+		}
+		img = Image{
+			ID:                 id,
+			Data:               newEbitenImage(swapPalette(paletted, l.LoadPalette(palette).Colors), imageInfo),
+			DefaultFrameWidth:  imageInfo.FrameWidth,
+			DefaultFrameHeight: imageInfo.FrameHeight,
+		}
+		l.storeImageVariant(key, img)
+	}
+	return img
+}
+
+// Load loads a resource identified by ref.
+// It's a kind-agnostic way to call a respective Load method,
+// like LoadImage for image resources.
+func (l *Loader) Load(ref Ref) {
+	switch ref.Kind {
+	case KindAudio:
+		l.LoadAudio(AudioID(ref.ID))
+	case KindFont:
+		l.LoadFont(FontID(ref.ID))
+	case KindImage:
+		l.LoadImage(ImageID(ref.ID))
+	case KindRaw:
+		l.LoadRaw(RawID(ref.ID))
+	case KindShader:
+		l.LoadShader(ShaderID(ref.ID))
+	case KindPalette:
+		l.LoadPalette(PaletteID(ref.ID))
+	case KindMaterial:
+		l.LoadMaterial(MaterialID(ref.ID))
+	case KindSDFFont:
+		l.LoadSDFFont(SDFFontID(ref.ID))
+	case KindTable:
+		l.LoadTable(TableID(ref.ID))
+	case KindConfig:
+		l.LoadConfig(ConfigID(ref.ID))
+	case KindTemplate:
+		l.LoadTemplate(TemplateID(ref.ID))
+	case KindScript:
+		l.LoadScript(ScriptID(ref.ID))
+	case KindParticleDef:
+		l.LoadParticleDef(ParticleDefID(ref.ID))
+	case KindHitboxes:
+		l.LoadHitboxes(HitboxesID(ref.ID))
+	case KindTileSet:
+		l.LoadTileSet(TileSetID(ref.ID))
+	case KindComposite:
+		l.LoadComposite(CompositeID(ref.ID))
+	case KindShaderImage:
+		l.LoadShaderImage(ShaderImageID(ref.ID))
+	case KindLUT:
+		l.LoadLUT(LUTID(ref.ID))
+	case KindGradient:
+		l.LoadGradient(GradientID(ref.ID))
+	case KindCurve:
+		l.LoadCurve(CurveID(ref.ID))
+	case KindInputProfile:
+		l.LoadInputProfile(InputProfileID(ref.ID))
+	case KindDefinition:
+		l.LoadDefinition(DefinitionID(ref.ID))
+	case KindDocument:
+		l.LoadDocument(DocumentID(ref.ID))
+	case KindIconSet:
+		l.LoadIconSet(IconSetID(ref.ID))
+	case KindTheme:
+		l.LoadTheme(ThemeID(ref.ID))
+	case KindVariantSet:
+		l.LoadVariantSet(VariantSetID(ref.ID))
+	default:
+		if _, ok := l.plugins[ref.Kind]; ok {
+			l.LoadPlugin(ref)
+			return
+		}
+		panic(fmt.Sprintf("load resource with unexpected kind %d", ref.Kind))
+	}
+}
+
+// IsLoaded reports whether the resource is loaded (cached).
+func (l *Loader) IsLoaded(ref Ref) bool {
+	ref = l.resolveRef(ref)
+	switch ref.Kind {
+	case KindImage:
+		return hasKey(l.images, ImageID(ref.ID))
+	case KindShader:
+		return hasKey(l.shaders, ShaderID(ref.ID))
+	case KindAudio:
+		return hasKey(l.wavs, AudioID(ref.ID)) || hasKey(l.oggs, AudioID(ref.ID)) || hasKey(l.customAudio, AudioID(ref.ID))
+	case KindFont:
+		return hasKey(l.fonts, FontID(ref.ID))
+	case KindRaw:
+		return hasKey(l.raws, RawID(ref.ID))
+	case KindPalette:
+		return hasKey(l.palettes, PaletteID(ref.ID))
+	case KindMaterial:
+		return hasKey(l.materials, MaterialID(ref.ID))
+	case KindSDFFont:
+		return hasKey(l.sdfFonts, SDFFontID(ref.ID))
+	case KindTable:
+		return hasKey(l.tables, TableID(ref.ID))
+	case KindTemplate:
+		return hasKey(l.templates, TemplateID(ref.ID))
+	case KindScript:
+		return hasKey(l.scripts, ScriptID(ref.ID))
+	case KindParticleDef:
+		return hasKey(l.particleDefs, ParticleDefID(ref.ID))
+	case KindHitboxes:
+		return hasKey(l.hitboxes, HitboxesID(ref.ID))
+	case KindTileSet:
+		return hasKey(l.tileSets, TileSetID(ref.ID))
+	case KindConfig:
+		return hasKey(l.configs, ConfigID(ref.ID))
+	case KindComposite:
+		return hasKey(l.composites, CompositeID(ref.ID))
+	case KindShaderImage:
+		return hasKey(l.shaderImages, ShaderImageID(ref.ID))
+	case KindLUT:
+		return hasKey(l.luts, LUTID(ref.ID))
+	case KindGradient:
+		return hasKey(l.gradients, GradientID(ref.ID))
+	case KindCurve:
+		return hasKey(l.curves, CurveID(ref.ID))
+	case KindInputProfile:
+		return hasKey(l.inputProfiles, InputProfileID(ref.ID))
+	case KindDefinition:
+		return hasKey(l.definitions, DefinitionID(ref.ID))
+	case KindDocument:
+		return hasKey(l.documents, DocumentID(ref.ID))
+	case KindIconSet:
+		return hasKey(l.iconSets, IconSetID(ref.ID))
+	case KindTheme:
+		return hasKey(l.themes, ThemeID(ref.ID))
+	case KindVariantSet:
+		return hasKey(l.variantSets, VariantSetID(ref.ID))
+	}
+	if k, ok := l.plugins[ref.Kind]; ok {
+		return hasKey(k.cache, ref.ID)
+	}
+	return false
+}
+
+func hasKey[K comparable, V any](m map[K]V, k K) bool {
+	_, ok := m[k]
+	return ok
+}
+
+// TryLoad is like Load, but it returns an error instead of panicking
+// if the resource (or any of its dependencies) can't be loaded.
+// The returned error is always an *Error.
+//
+// Panics that are not caused by the resource loading failures
+// (like the OpenAssetFunc panics) are not recovered.
+func (l *Loader) TryLoad(ref Ref) (err error) {
+	defer func() {
+		if rv := recover(); rv != nil {
+			resourceErr, ok := rv.(*Error)
+			if !ok {
+				panic(rv)
+			}
+			l.emitError(ref, resourceErr)
+			err = resourceErr
+		}
+	}()
+	l.Load(ref)
+	return nil
+}
+
+// LoadBatch loads all given resources.
+// Unlike Load, it doesn't stop on the first failure: every resource
+// load is attempted and all failures are reported at once.
+//
+// This is useful for the startup validation, so every broken
+// asset is reported instead of just the first one.
+//
+// Unlike TryLoad, it also recovers the panics that are not *Error
+// (like the OpenAssetFunc panics), so one broken resource can't abort
+// the whole batch; they're reported as *Error with Op set to "load".
+//
+// A non-nil result is always a *BatchError.
+func (l *Loader) LoadBatch(refs []Ref) error {
+	var errs []*Error
+	for _, ref := range refs {
+		if err := l.tryLoadBatchItem(ref); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) != 0 {
+		return &BatchError{Errors: errs}
+	}
+	return nil
+}
+
+// tryLoadBatchItem implements the LoadBatch per-resource recovery.
+func (l *Loader) tryLoadBatchItem(ref Ref) (err *Error) {
+	defer func() {
+		rv := recover()
+		if rv == nil {
+			return
+		}
+		resourceErr, ok := rv.(*Error)
+		if !ok {
+			cause, ok := rv.(error)
+			if !ok {
+				cause = fmt.Errorf("%v", rv)
+			}
+			resourceErr = newError("load", ref, l.refPath(ref), cause)
+		}
+		l.emitError(ref, resourceErr)
+		err = resourceErr
+	}()
+	l.Load(ref)
+	return nil
+}
+
+// Unload removes the resource from the cache and releases its data
+// (images and shaders are disposed, audio players are closed).
+// The next Load for this resource will decode it again.
+//
+// Resources that are required by other loaded resources are not unloaded
+// (see DependsOn field of the resource info types).
+// When a resource is unloaded, it releases its own dependencies,
+// but they stay loaded until they're unloaded explicitly.
+//
+// It reports whether the resource was unloaded.
+func (l *Loader) Unload(ref Ref) bool {
+	return l.unload(ref, EventUnload)
+}
+
+// unload implements Unload.
+// The typ is an event type to emit if the resource is unloaded.
+func (l *Loader) unload(ref Ref, typ EventType) bool {
+	ref = l.resolveRef(ref)
+	if l.depRefs[ref] > 0 {
+		return false
+	}
+	// The evicted images are not disposed: unlike the explicit Unload,
+	// the eviction is not requested by the caller that may still hold them.
+	if !l.unloadResource(ref, typ != EventEvict) {
+		return false
+	}
+	l.emit(typ, ref)
+	for _, dep := range l.deps[ref] {
+		l.depRefs[dep]--
+		if l.depRefs[dep] == 0 {
+			delete(l.depRefs, dep)
+		}
+	}
+	delete(l.deps, ref)
+	for _, path := range l.openedPaths[ref] {
+		l.evictPatchedAssets(path)
+	}
+	delete(l.openedPaths, ref)
+	return true
+}
+
+// Close unloads all resources (see Unload), ignoring their dependencies.
+// It's intended to be used when the loader is not needed anymore,
+// so its GPU memory and audio players are released immediately.
+//
+// The players created by NewAudioPlayer are owned by the caller,
+// they're not closed by this method.
+//
+// The prefetch queue is cleared too (see Prefetch).
+//
+// The loader can still be used after Close: all resources
+// will be loaded again on demand.
+func (l *Loader) Close() {
+	for _, ref := range l.loadedRefs() {
+		l.unloadResource(ref, true)
+		l.emit(EventUnload, ref)
+	}
+	for key := range l.imageVariants {
+		l.deleteImageVariant(key, true)
+	}
+	for id := range l.pcmFiles {
+		l.closePCMFile(id)
+	}
+	l.deps = make(map[Ref][]Ref)
+	l.depRefs = make(map[Ref]int)
+	l.openedPaths = nil
+	l.patchedAssets = nil
+	l.lastUsed = nil
+	l.softEntries = nil
+	l.softSize = 0
+	l.loadStarts = nil
+	l.loadQueue = [numPriorities][]Ref{}
+}
+
+// Snapshot describes a set of loaded resources.
+// See Loader.Snapshot and Loader.Restore.
+type Snapshot struct {
+	Refs []Ref
+}
+
+// Snapshot returns a set of the currently loaded resources.
+//
+// It's intended to be used with Restore to switch between
+// the scenes without reloading the shared resources.
+func (l *Loader) Snapshot() Snapshot {
+	return Snapshot{Refs: l.loadedRefs()}
+}
+
+// Restore makes the loaded resources set match the snapshot:
+// the missing resources are loaded and the extra ones are unloaded.
+//
+// The extra resources that are required by the snapshot
+// resources are kept loaded (see Unload).
+func (l *Loader) Restore(s Snapshot) {
+	want := make(map[Ref]struct{}, len(s.Refs))
+	for _, ref := range s.Refs {
+		want[l.resolveRef(ref)] = struct{}{}
+	}
+
+	// Unloading a resource can make its dependencies unloadable,
+	// so repeat until there is no progress.
+	for {
+		progress := false
+		for _, ref := range l.loadedRefs() {
+			if _, ok := want[ref]; ok {
+				continue
+			}
+			if l.Unload(ref) {
+				progress = true
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	for _, ref := range s.Refs {
+		l.Load(ref)
+	}
+}
+
+// UnloadAudio is a typed version of Unload.
+func (l *Loader) UnloadAudio(id AudioID) bool { return l.Unload(id.Ref()) }
+
+// UnloadFont is a typed version of Unload.
+func (l *Loader) UnloadFont(id FontID) bool { return l.Unload(id.Ref()) }
+
+// UnloadImage is a typed version of Unload.
+func (l *Loader) UnloadImage(id ImageID) bool { return l.Unload(id.Ref()) }
+
+// UnloadRaw is a typed version of Unload.
+func (l *Loader) UnloadRaw(id RawID) bool { return l.Unload(id.Ref()) }
+
+// UnloadShader is a typed version of Unload.
+func (l *Loader) UnloadShader(id ShaderID) bool { return l.Unload(id.Ref()) }
+
+// unloadResource removes the resource from the cache.
+// The image textures are disposed only if dispose is true,
+// otherwise they're left to the garbage collector.
+func (l *Loader) unloadResource(ref Ref, dispose bool) bool {
+	switch ref.Kind {
+	case KindAudio:
+		id := AudioID(ref.ID)
+		l.closePCMFile(id)
+		for _, m := range []map[AudioID]Audio{l.wavs, l.oggs, l.customAudio} {
+			if a, ok := m[id]; ok {
+				if a.Player != nil {
+					// The player could be released by ReleaseAudioPlayer.
+					a.Player.Close()
+				}
+				delete(m, id)
+				return true
+			}
+		}
+	case KindFont:
+		id := FontID(ref.ID)
+		if _, ok := l.fonts[id]; ok {
+			delete(l.fonts, id)
+			return true
+		}
+	case KindImage:
+		id := ImageID(ref.ID)
+		unloaded := l.unloadImageVariants(id, dispose)
+		if img, ok := l.images[id]; ok {
+			l.addTextureMemory(-img.textureBytes())
+			l.lruSize -= img.textureBytes()
+			if dispose {
+				if img.Tiled != nil {
+					img.Tiled.dispose()
+				} else {
+					img.Data.Dispose()
+				}
+				for _, m := range img.Mipmaps {
+					m.Dispose()
+				}
+			}
+			delete(l.images, id)
+			unloaded = true
+		}
+		return unloaded
+	case KindRaw:
+		id := RawID(ref.ID)
+		if raw, ok := l.raws[id]; ok {
+			l.lruSize -= int64(len(raw.Data))
+			delete(l.raws, id)
+			return true
+		}
+	case KindShader:
+		id := ShaderID(ref.ID)
+		if shader, ok := l.shaders[id]; ok {
+			shader.Data.Dispose()
+			delete(l.shaders, id)
+			return true
+		}
+	case KindMaterial:
+		id := MaterialID(ref.ID)
+		if _, ok := l.materials[id]; ok {
+			delete(l.materials, id)
+			return true
+		}
+	case KindPalette:
+		id := PaletteID(ref.ID)
+		if _, ok := l.palettes[id]; ok {
+			delete(l.palettes, id)
+			for key := range l.imageVariants {
+				if key.variant == id {
+					l.deleteImageVariant(key, dispose)
+				}
+			}
+			return true
+		}
+	case KindSDFFont:
+		id := SDFFontID(ref.ID)
+		if res, ok := l.sdfFonts[id]; ok {
+			l.addTextureMemory(-textureBytes(res.Atlas))
+			res.Atlas.Dispose()
+			delete(l.sdfFonts, id)
+			return true
+		}
+	case KindTable:
+		id := TableID(ref.ID)
+		if _, ok := l.tables[id]; ok {
+			delete(l.tables, id)
+			return true
+		}
+	case KindConfig:
+		id := ConfigID(ref.ID)
+		if _, ok := l.configs[id]; ok {
+			delete(l.configs, id)
+			return true
+		}
+	case KindTemplate:
+		id := TemplateID(ref.ID)
+		if _, ok := l.templates[id]; ok {
+			delete(l.templates, id)
+			return true
+		}
+	case KindScript:
+		id := ScriptID(ref.ID)
+		if _, ok := l.scripts[id]; ok {
+			delete(l.scripts, id)
+			return true
+		}
+	case KindParticleDef:
+		id := ParticleDefID(ref.ID)
+		if _, ok := l.particleDefs[id]; ok {
+			delete(l.particleDefs, id)
+			return true
+		}
+	case KindHitboxes:
+		id := HitboxesID(ref.ID)
+		if _, ok := l.hitboxes[id]; ok {
+			delete(l.hitboxes, id)
+			return true
+		}
+	case KindTileSet:
+		id := TileSetID(ref.ID)
+		if _, ok := l.tileSets[id]; ok {
+			delete(l.tileSets, id)
+			return true
+		}
+	case KindComposite:
+		id := CompositeID(ref.ID)
+		if res, ok := l.composites[id]; ok {
+			l.addTextureMemory(-textureBytes(res.Data))
+			res.Data.Dispose()
+			delete(l.composites, id)
+			return true
+		}
+	case KindShaderImage:
+		id := ShaderImageID(ref.ID)
+		if res, ok := l.shaderImages[id]; ok {
+			l.addTextureMemory(-textureBytes(res.Data))
+			res.Data.Dispose()
+			delete(l.shaderImages, id)
+			return true
+		}
+	case KindLUT:
+		id := LUTID(ref.ID)
+		if res, ok := l.luts[id]; ok {
+			l.addTextureMemory(-textureBytes(res.Data))
+			res.Data.Dispose()
+			delete(l.luts, id)
+			return true
+		}
+	case KindGradient:
+		id := GradientID(ref.ID)
+		if _, ok := l.gradients[id]; ok {
+			delete(l.gradients, id)
+			return true
+		}
+	case KindCurve:
+		id := CurveID(ref.ID)
+		if _, ok := l.curves[id]; ok {
+			delete(l.curves, id)
+			return true
+		}
+	case KindInputProfile:
+		id := InputProfileID(ref.ID)
+		if _, ok := l.inputProfiles[id]; ok {
+			delete(l.inputProfiles, id)
+			return true
+		}
+	case KindDefinition:
+		id := DefinitionID(ref.ID)
+		if _, ok := l.definitions[id]; ok {
+			delete(l.definitions, id)
+			return true
+		}
+	case KindDocument:
+		id := DocumentID(ref.ID)
+		if _, ok := l.documents[id]; ok {
+			delete(l.documents, id)
+			return true
+		}
+	case KindIconSet:
+		id := IconSetID(ref.ID)
+		if _, ok := l.iconSets[id]; ok {
+			delete(l.iconSets, id)
+			return true
+		}
+	case KindTheme:
+		id := ThemeID(ref.ID)
+		if _, ok := l.themes[id]; ok {
+			delete(l.themes, id)
+			return true
+		}
+	case KindVariantSet:
+		id := VariantSetID(ref.ID)
+		if _, ok := l.variantSets[id]; ok {
+			delete(l.variantSets, id)
+			return true
+		}
+	default:
+		return l.unloadPluginResource(ref)
+	}
+	return false
+}
+
+// loadedRefs returns the references to all cached resources.
+// The derived images (like tinted ones) are not included.
+func (l *Loader) loadedRefs() []Ref {
+	var refs []Ref
+	refs = appendRefs(refs, KindImage, l.images)
+	refs = appendRefs(refs, KindShader, l.shaders)
+	refs = appendRefs(refs, KindAudio, l.wavs)
+	refs = appendRefs(refs, KindAudio, l.oggs)
+	refs = appendRefs(refs, KindAudio, l.customAudio)
+	refs = appendRefs(refs, KindFont, l.fonts)
+	refs = appendRefs(refs, KindRaw, l.raws)
+	refs = appendRefs(refs, KindPalette, l.palettes)
+	refs = appendRefs(refs, KindMaterial, l.materials)
+	refs = appendRefs(refs, KindSDFFont, l.sdfFonts)
+	refs = appendRefs(refs, KindTable, l.tables)
+	refs = appendRefs(refs, KindTemplate, l.templates)
+	refs = appendRefs(refs, KindScript, l.scripts)
+	refs = appendRefs(refs, KindParticleDef, l.particleDefs)
+	refs = appendRefs(refs, KindHitboxes, l.hitboxes)
+	refs = appendRefs(refs, KindTileSet, l.tileSets)
+	refs = appendRefs(refs, KindConfig, l.configs)
+	refs = appendRefs(refs, KindComposite, l.composites)
+	refs = appendRefs(refs, KindShaderImage, l.shaderImages)
+	refs = appendRefs(refs, KindLUT, l.luts)
+	refs = appendRefs(refs, KindGradient, l.gradients)
+	refs = appendRefs(refs, KindCurve, l.curves)
+	refs = appendRefs(refs, KindInputProfile, l.inputProfiles)
+	refs = appendRefs(refs, KindDefinition, l.definitions)
+	refs = appendRefs(refs, KindDocument, l.documents)
+	refs = appendRefs(refs, KindIconSet, l.iconSets)
+	refs = appendRefs(refs, KindTheme, l.themes)
+	refs = appendRefs(refs, KindVariantSet, l.variantSets)
+	for _, kind := range l.pluginKindsOrdered() {
+		refs = appendRefs(refs, kind, l.plugins[kind].cache)
+	}
+	return refs
+}
+
+func appendRefs[IDType ~int, T any](refs []Ref, kind Kind, m map[IDType]T) []Ref {
+	start := len(refs)
+	for id := range m {
+		refs = append(refs, Ref{Kind: kind, ID: int(id)})
+	}
+	// Keep the order stable, so the operations over
+	// all loaded resources are reproducible.
+	added := refs[start:]
+	sort.Slice(added, func(i, j int) bool {
+		return added[i].ID < added[j].ID
+	})
+	return refs
+}
+
+func (l *Loader) resolveRef(ref Ref) Ref {
+	switch ref.Kind {
+	case KindAudio:
+		ref.ID = int(l.AudioRegistry.resolve(AudioID(ref.ID)))
+	case KindFont:
+		ref.ID = int(l.FontRegistry.resolve(FontID(ref.ID)))
+	case KindImage:
+		ref.ID = int(l.ImageRegistry.resolve(ImageID(ref.ID)))
+	case KindRaw:
+		ref.ID = int(l.RawRegistry.resolve(RawID(ref.ID)))
+	case KindShader:
+		ref.ID = int(l.ShaderRegistry.resolve(ShaderID(ref.ID)))
+	case KindPalette:
+		ref.ID = int(l.PaletteRegistry.resolve(PaletteID(ref.ID)))
+	case KindMaterial:
+		ref.ID = int(l.MaterialRegistry.resolve(MaterialID(ref.ID)))
+	case KindSDFFont:
+		ref.ID = int(l.SDFFontRegistry.resolve(SDFFontID(ref.ID)))
+	case KindTable:
+		ref.ID = int(l.TableRegistry.resolve(TableID(ref.ID)))
+	case KindConfig:
+		ref.ID = int(l.ConfigRegistry.resolve(ConfigID(ref.ID)))
+	case KindTemplate:
+		ref.ID = int(l.TemplateRegistry.resolve(TemplateID(ref.ID)))
+	case KindScript:
+		ref.ID = int(l.ScriptRegistry.resolve(ScriptID(ref.ID)))
+	case KindParticleDef:
+		ref.ID = int(l.ParticleDefRegistry.resolve(ParticleDefID(ref.ID)))
+	case KindHitboxes:
+		ref.ID = int(l.HitboxesRegistry.resolve(HitboxesID(ref.ID)))
+	case KindTileSet:
+		ref.ID = int(l.TileSetRegistry.resolve(TileSetID(ref.ID)))
+	case KindComposite:
+		ref.ID = int(l.CompositeRegistry.resolve(CompositeID(ref.ID)))
+	case KindShaderImage:
+		ref.ID = int(l.ShaderImageRegistry.resolve(ShaderImageID(ref.ID)))
+	case KindLUT:
+		ref.ID = int(l.LUTRegistry.resolve(LUTID(ref.ID)))
+	case KindGradient:
+		ref.ID = int(l.GradientRegistry.resolve(GradientID(ref.ID)))
+	case KindCurve:
+		ref.ID = int(l.CurveRegistry.resolve(CurveID(ref.ID)))
+	case KindInputProfile:
+		ref.ID = int(l.InputProfileRegistry.resolve(InputProfileID(ref.ID)))
+	case KindDefinition:
+		ref.ID = int(l.DefinitionRegistry.resolve(DefinitionID(ref.ID)))
+	case KindDocument:
+		ref.ID = int(l.DocumentRegistry.resolve(DocumentID(ref.ID)))
+	case KindIconSet:
+		ref.ID = int(l.IconSetRegistry.resolve(IconSetID(ref.ID)))
+	case KindTheme:
+		ref.ID = int(l.ThemeRegistry.resolve(ThemeID(ref.ID)))
+	case KindVariantSet:
+		ref.ID = int(l.VariantSetRegistry.resolve(VariantSetID(ref.ID)))
+	default:
+		if k, ok := l.plugins[ref.Kind]; ok {
+			ref.ID = k.registry.resolve(ref.ID)
+		}
+	}
+	return ref
+}
+
+// registeredIDs returns all IDs that have a bound info
+// in the registry of a given kind.
+func (l *Loader) registeredIDs(kind Kind) []int {
+	if r := l.kindRegistry(kind); r != nil {
+		return r.ids()
+	}
+	return nil
+}
+
+// kindRegistry returns a registry of a given kind.
+// It returns nil for the unknown kinds.
+func (l *Loader) kindRegistry(kind Kind) anyRegistry {
+	switch kind {
+	case KindAudio:
+		return &l.AudioRegistry
+	case KindFont:
+		return &l.FontRegistry
+	case KindImage:
+		return &l.ImageRegistry
+	case KindRaw:
+		return &l.RawRegistry
+	case KindShader:
+		return &l.ShaderRegistry
+	case KindPalette:
+		return &l.PaletteRegistry
+	case KindMaterial:
+		return &l.MaterialRegistry
+	case KindSDFFont:
+		return &l.SDFFontRegistry
+	case KindTable:
+		return &l.TableRegistry
+	case KindConfig:
+		return &l.ConfigRegistry
+	case KindTemplate:
+		return &l.TemplateRegistry
+	case KindScript:
+		return &l.ScriptRegistry
+	case KindParticleDef:
+		return &l.ParticleDefRegistry
+	case KindHitboxes:
+		return &l.HitboxesRegistry
+	case KindTileSet:
+		return &l.TileSetRegistry
+	case KindComposite:
+		return &l.CompositeRegistry
+	case KindShaderImage:
+		return &l.ShaderImageRegistry
+	case KindLUT:
+		return &l.LUTRegistry
+	case KindGradient:
+		return &l.GradientRegistry
+	case KindCurve:
+		return &l.CurveRegistry
+	case KindInputProfile:
+		return &l.InputProfileRegistry
+	case KindDefinition:
+		return &l.DefinitionRegistry
+	case KindDocument:
+		return &l.DocumentRegistry
+	case KindIconSet:
+		return &l.IconSetRegistry
+	case KindTheme:
+		return &l.ThemeRegistry
+	case KindVariantSet:
+		return &l.VariantSetRegistry
+	}
+	if k, ok := l.plugins[kind]; ok {
+		return &k.registry
+	}
+	return nil
+}
+
+func (l *Loader) loadDependencies(ref Ref, deps []Ref) {
+	if len(deps) == 0 {
+		return
+	}
+	l.depStack = append(l.depStack, ref)
+	defer func() {
+		l.depStack = l.depStack[:len(l.depStack)-1]
+	}()
+	resolved := make([]Ref, 0, len(deps))
+	defer func() {
+		if len(resolved) == len(deps) {
+			return
+		}
+		// A dependency failed to load: the ref is not loaded,
+		// so it doesn't hold the already loaded dependencies.
+		for _, dep := range resolved {
+			l.depRefs[dep]--
+			if l.depRefs[dep] == 0 {
+				delete(l.depRefs, dep)
+			}
+		}
+	}()
+	for _, dep := range deps {
+		dep = l.resolveRef(dep)
+		l.checkLoadCycle(l.depStack, dep, "dependency")
+		l.Load(dep)
+		l.depRefs[dep]++
+		resolved = append(resolved, dep)
+	}
+	l.deps[ref] = resolved
+}
+
+// checkLoadCycle panics with an ErrCycle error if the ref is already
+// in the stack of the resources that are being loaded.
+// The error message contains the cycle path.
+func (l *Loader) checkLoadCycle(stack []Ref, ref Ref, what string) {
+	for i, r := range stack {
+		if r != ref {
+			continue
+		}
+		parts := make([]string, 0, len(stack)-i+1)
+		for _, r := range stack[i:] {
+			parts = append(parts, fmt.Sprintf("%s with id=%d", r.Kind, r.ID))
+		}
+		parts = append(parts, fmt.Sprintf("%s with id=%d", ref.Kind, ref.ID))
+		err := fmt.Errorf("%s %w: %s", what, ErrCycle, strings.Join(parts, " -> "))
+		panic(newError("load", ref, l.refPath(ref), err))
+	}
+}
+
+// refPath returns the registered path of the resource.
+func (l *Loader) refPath(ref Ref) string {
+	if r := l.kindRegistry(ref.Kind); r != nil {
+		return r.infoPath(ref.ID)
+	}
+	return ""
+}
+
+func (l *Loader) getAudioInfo(id AudioID) AudioInfo {
+	info, ok := l.AudioRegistry.mapping[id]
+	if !ok {
+		panic(l.notRegisteredError(id.Ref()))
+	}
+	info = applyVariant(l, id.Ref(), info)
+	info.Path = l.platformPath(info.Path, info.PathByPlatform)
+	if info.StreamDecorator == nil {
+		info.StreamDecorator = l.GroupStreamDecorators[info.Group]
+	}
+	return info
+}
+
+func (l *Loader) createAudioObject(p *audio.Player, id AudioID, info AudioInfo, stream io.Reader) Audio {
+	volume := (info.Volume / 2) + 0.5
+	sampleRate := l.audioStreamSampleRate(stream)
+	return Audio{
+		loader:   l,
+		ID:       id,
+		Player:   p,
+		Volume:   volume,
+		Group:    info.Group,
+		Duration: audioStreamDuration(stream, sampleRate),
+		Beatmap:  l.loadBeatmap(id, info, sampleRate),
+	}
+}
+
+// audioStreamSampleRate returns the stream sample rate if it's known
+// (SampleRate method), the audio context sample rate is returned otherwise.
+func (l *Loader) audioStreamSampleRate(stream io.Reader) int {
+	if withRate, ok := stream.(interface{ SampleRate() int }); ok {
+		if rate := withRate.SampleRate(); rate > 0 {
+			return rate
+		}
+	}
+	if l.audioContext != nil {
+		return l.audioContext.SampleRate()
+	}
+	return 0
+}
+
+// audioStreamDuration returns the stream duration if it has a Length method.
+func audioStreamDuration(stream io.Reader, sampleRate int) time.Duration {
+	s, ok := stream.(lengthStream)
+	if !ok {
+		return 0
+	}
+	return StreamDuration(s.Length(), sampleRate)
+}
+
+func (l *Loader) maybeWrapAudioStream(r io.ReadSeeker, info AudioInfo) io.ReadSeeker {
+	if info.StreamDecorator != nil {
+		return info.StreamDecorator(r)
+	}
+	return r
+}
+
+func (l *Loader) platformPath(path string, byPlatform map[string]string) string {
+	if p, ok := byPlatform[l.Platform]; ok {
+		return p
+	}
+	return path
+}
+
+// resourceID is a typed resource key, like ImageID.
+type resourceID interface {
+	~int
+	Ref() Ref
+}
+
+// infoFields are the resource info fields used by loadDecoded.
+type infoFields struct {
+	path           *string
+	pathByPlatform map[string]string
+	data           []byte
+	dependsOn      []Ref
+}
+
+// decodedKind describes a resource kind that is decoded
+// right from its data reader, see loadDecoded.
+type decodedKind[IDType resourceID, InfoType, T any] struct {
+	// op is the *Error operation reported for the decode failures.
+	op string
+
+	// fields returns the info fields used by the loader.
+	fields func(info *InfoType) infoFields
+
+	// decode decodes the resource.
+	// The returned refs are loaded as its dependencies along with the DependsOn.
+	decode func(id IDType, info InfoType, r io.Reader) (T, []Ref, error)
+}
+
+// loadDecoded implements the Load methods of the kinds
+// that are decoded right from their data reader (see decodedKind),
+// so they're resolved, opened, profiled and cached in the same way.
+func loadDecoded[IDType resourceID, InfoType, T any](l *Loader, r *registry[IDType, InfoType], cache map[IDType]T, id IDType, kind decodedKind[IDType, InfoType, T]) T {
+	id = r.resolve(id)
+	if v, ok := cache[id]; ok {
+		return v
+	}
+	info, ok := r.mapping[id]
+	if !ok {
+		panic(l.notRegisteredError(id.Ref()))
+	}
+	info = applyVariant(l, id.Ref(), info)
+	fields := kind.fields(&info)
+	*fields.path = l.platformPath(*fields.path, fields.pathByPlatform)
+	path := *fields.path
+	rc := l.openResource(id.Ref(), path, fields.data)
+	defer func() {
+		if err := rc.Close(); err != nil {
+			panic(newError("read", id.Ref(), path, err))
+		}
+	}()
+	done := l.profileStart(id.Ref(), path, stageDecode)
+	v, deps, err := kind.decode(id, info, rc)
+	if err != nil {
+		panic(l.decodeError(kind.op, id.Ref(), path, fields.data, err))
+	}
+	done()
+	if len(deps) != 0 {
+		deps = append(append([]Ref(nil), fields.dependsOn...), deps...)
+	} else {
+		deps = fields.dependsOn
+	}
+	l.loadDependencies(id.Ref(), deps)
+	cache[id] = v
+	l.emit(EventLoad, id.Ref())
+	return v
+}
+
+// applyVariant returns the info selected by the ScheduledOverrides
+// and the VariantFunc.
+func applyVariant[T any](l *Loader, ref Ref, info T) T {
+	info = applyScheduledOverride(l, ref, info)
+	if l.VariantFunc == nil {
+		return info
+	}
+	v := l.VariantFunc(ref, info)
+	result, ok := v.(T)
+	if !ok {
+		err := fmt.Errorf("VariantFunc returned %T instead of %T", v, info)
+		panic(newError("variant", ref, l.refPath(ref), err))
+	}
+	return result
+}
+
+// openResource opens the resource data.
+// If the in-memory data is available, it's used instead of the path.
+func (l *Loader) openResource(ref Ref, path string, data []byte) io.ReadCloser {
+	l.trackUsage(ref)
+	l.markLoadStart(ref)
+	if data != nil {
+		return io.NopCloser(bytes.NewReader(data))
+	}
+	return l.openAsset(ref, path)
+}
+
+// openAssetError is used to pass the OpenAssetErrFunc error
+// through the middlewares chain.
+type openAssetError struct {
+	err error
+}
+
+// openFunc returns the asset opening function wrapped into the middlewares.
+func (l *Loader) openFunc() OpenAssetFunc {
+	if l.openChain == nil {
+		open := OpenAssetFunc(l.openBase)
+		for i := len(l.middlewares) - 1; i >= 0; i-- {
+			open = l.middlewares[i](open)
+		}
+		l.openChain = open
+	}
+	return l.openChain
+}
+
+// openBase is the last function in the middlewares chain.
+// The OpenAssetFunc and OpenAssetErrFunc are selected on every call,
+// so they can be changed after the chain is built.
+func (l *Loader) openBase(path string) io.ReadCloser {
+	if l.OpenAssetErrFunc != nil {
+		r, err := l.OpenAssetErrFunc(path)
+		if err != nil {
+			panic(openAssetError{err: err})
+		}
+		return r
+	}
+	return l.OpenAssetFunc(path)
+}
+
+// mapAssetPath applies the path variables, normalization and redirects.
+func (l *Loader) mapAssetPath(ref Ref, path string) string {
+	return l.redirectPath(ref, l.normalizePath(l.expandPath(ref, path)))
+}
+
+func (l *Loader) openAsset(ref Ref, path string) (r io.ReadCloser) {
+	path = l.mapAssetPath(ref, path)
+	l.recordOpenedPath(ref, path)
+	open := l.openFunc()
+	if l.OpenAssetErrFunc != nil {
+		defer func() {
+			if rv := recover(); rv != nil {
+				openErr, ok := rv.(openAssetError)
+				if !ok {
+					panic(rv)
+				}
+				panic(newError("open", ref, path, openErr.err))
+			}
+		}()
+	}
+	defer l.profileStart(ref, path, stageOpen)()
+	if r, ok, err := l.openMounted(path); ok {
+		if err != nil {
+			panic(newError("open", ref, path, err))
+		}
+		return r
+	}
+	if patchPath, ok := l.Patches[path]; ok {
+		return l.openPatched(ref, path, patchPath, open)
+	}
+	r = open(l.assetPath(path))
+	if checksum, ok := l.Checksums[path]; ok {
+		return l.verifyAsset(ref, path, checksum, r)
+	}
+	return r
+}
+
+func (l *Loader) verifyAsset(ref Ref, path, checksum string, r io.ReadCloser) io.ReadCloser {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		panic(newError("read", ref, path, err))
+	}
+	if err := r.Close(); err != nil {
+		panic(newError("read", ref, path, err))
+	}
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); !strings.EqualFold(actual, checksum) {
+		err := fmt.Errorf("checksum mismatch: expected %s, got %s", checksum, actual)
+		if l.IntegrityErrorFunc == nil {
+			panic(newError("verify", ref, path, err))
+		}
+		l.IntegrityErrorFunc(path, err)
+	}
+	return io.NopCloser(bytes.NewReader(data))
+}
+
+// assetPath maps a resource path to a path that is passed to the OpenAssetFunc.
+func (l *Loader) assetPath(path string) string {
+	if hashed, ok := l.Fingerprints[path]; ok {
+		path = hashed
+	}
+	return l.rewritePath(path)
+}
+
+// rewritePath applies the base path and RewritePathFunc to the path.
+// Unlike assetPath, it doesn't apply the fingerprints:
+// the written assets are stored under their logical paths.
+func (l *Loader) rewritePath(path string) string {
+	if l.basePath != "" {
+		// The resource paths are relative to the base path
+		// even if they start with a slash.
+		path = strings.TrimLeft(path, "/")
+		if strings.HasSuffix(l.basePath, "/") {
+			path = l.basePath + path
+		} else {
+			path = l.basePath + "/" + path
+		}
+	}
+	if l.RewritePathFunc != nil {
+		path = l.RewritePathFunc(path)
+	}
+	return path
+}
+
+// imageVariantKey identifies a derived image.
+// The variant is a comparable value that describes the derivation
+// parameters; every variant kind uses its own type, so they never collide.
+type imageVariantKey struct {
+	id      ImageID
+	variant any
+}
+
+func (l *Loader) unloadImageVariants(id ImageID, dispose bool) bool {
+	unloaded := false
+	for key := range l.imageVariants {
+		if key.id == id {
+			l.deleteImageVariant(key, dispose)
+			unloaded = true
+		}
+	}
+	return unloaded
 }
@@ -0,0 +1,112 @@
+package resource
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// DefaultLoopClickThreshold is used by Loader.ValidateLoop
+// when the threshold argument is zero.
+const DefaultLoopClickThreshold = 0.05
+
+// CheckLoopBoundary analyzes the samples around the loop point
+// of a decoded stream: its last samples followed by its first samples.
+//
+// The stream is expected to be in the Ebitengine audio format
+// (16-bit signed stereo) and to have a Length method (OGG and WAV streams do).
+//
+// It returns the max amplitude discontinuity at the loop point
+// relative to the full scale, in [0, 2] range.
+// The discontinuity is measured against the value predicted from
+// the last two samples, so a smooth waveform crossing the boundary
+// is not treated as a click.
+//
+// The stream position is left at the beginning.
+func CheckLoopBoundary(stream io.ReadSeeker) (float64, error) {
+	s, ok := stream.(lengthStream)
+	if !ok {
+		return 0, fmt.Errorf("%T stream has no Length method", stream)
+	}
+	const frameSize = 4
+	length := s.Length() - s.Length()%frameSize
+	if length < 2*frameSize {
+		return 0, fmt.Errorf("the stream is too short")
+	}
+
+	var tail [2 * frameSize]byte
+	if _, err := stream.Seek(length-int64(len(tail)), io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(stream, tail[:]); err != nil {
+		return 0, err
+	}
+	var head [frameSize]byte
+	if _, err := stream.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	if _, err := io.ReadFull(stream, head[:]); err != nil {
+		return 0, err
+	}
+	if _, err := stream.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	sample := func(b []byte, channel int) float64 {
+		return float64(int16(binary.LittleEndian.Uint16(b[channel*2:]))) / 32768
+	}
+	delta := 0.0
+	for channel := 0; channel < 2; channel++ {
+		prev := sample(tail[:], channel)
+		last := sample(tail[frameSize:], channel)
+		first := sample(head[:], channel)
+		predicted := last + (last - prev)
+		delta = math.Max(delta, math.Abs(first-predicted))
+	}
+	return delta, nil
+}
+
+// ValidateLoop checks whether the audio resource can be looped without
+// an audible click at the loop point (see CheckLoopBoundary).
+//
+// The threshold is a max allowed discontinuity relative to the full scale;
+// zero means DefaultLoopClickThreshold.
+//
+// It's a debug utility: run it for the music tracks during the development
+// to catch the bad exports. The resource is decoded again for the analysis,
+// the stream decorator is not applied.
+//
+// A failed check is reported via WarningFunc (if it's set) and returned
+// as an *Error with "loop" operation.
+func (l *Loader) ValidateLoop(id AudioID, threshold float64) error {
+	if threshold == 0 {
+		threshold = DefaultLoopClickThreshold
+	}
+	id = l.AudioRegistry.resolve(id)
+	info := l.getAudioInfo(id)
+	loaded := l.IsLoaded(id.Ref())
+	stream := l.decodeAudioStream(id, info)
+	delta, err := CheckLoopBoundary(stream)
+	if c, ok := stream.(io.Closer); ok {
+		c.Close()
+	}
+	if !loaded {
+		l.closePCMFile(id)
+	}
+	if err == nil && delta > threshold {
+		err = fmt.Errorf("the loop point will click: discontinuity is %.3f (threshold is %.3f)", delta, threshold)
+	}
+	if err == nil {
+		return nil
+	}
+	e := newError("loop", id.Ref(), info.Path, err)
+	l.warn(e)
+	return e
+}
+
+func (l *Loader) warn(err error) {
+	if l.WarningFunc != nil {
+		l.WarningFunc(err)
+	}
+}
@@ -0,0 +1,45 @@
+package resource
+
+import (
+	"sort"
+)
+
+// touch marks the resource as recently used for the LRU eviction.
+func (l *Loader) touch(ref Ref) {
+	if l.LRUCacheLimit <= 0 {
+		return
+	}
+	if l.lastUsed == nil {
+		l.lastUsed = make(map[Ref]uint64)
+	}
+	l.useTick++
+	l.lastUsed[ref] = l.useTick
+}
+
+// evictLRU unloads the least recently used images and raws
+// until the LRUCacheLimit is satisfied.
+// The keep resource is never evicted (it's the one that was just loaded).
+func (l *Loader) evictLRU(keep Ref) {
+	if l.LRUCacheLimit <= 0 || l.lruSize <= l.LRUCacheLimit {
+		return
+	}
+	candidates := make([]Ref, 0, len(l.images)+len(l.raws))
+	candidates = appendRefs(candidates, KindImage, l.images)
+	candidates = appendRefs(candidates, KindRaw, l.raws)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return l.lastUsed[candidates[i]] < l.lastUsed[candidates[j]]
+	})
+	for _, ref := range candidates {
+		if l.lruSize <= l.LRUCacheLimit {
+			break
+		}
+		if ref == keep {
+			continue
+		}
+		// Unload refuses to unload the resources required by
+		// other loaded resources, they're skipped.
+		if l.unload(ref, EventEvict) {
+			delete(l.lastUsed, ref)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+func TestLRUVariantRecency(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 8, 4))); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLoader(nil)
+	// Enough for two 8x4 images.
+	l.LRUCacheLimit = 2 * 8 * 4 * 4
+	l.ImageRegistry.Assign(map[ImageID]ImageInfo{
+		1: {Path: "1.png", Data: buf.Bytes()},
+		2: {Path: "2.png", Data: buf.Bytes()},
+		3: {Path: "3.png", Data: buf.Bytes()},
+	})
+
+	red := color.RGBA{R: 0xff, A: 0xff}
+	l.LoadImage(1)
+	l.LoadImageTinted(1, red)
+	l.LoadImage(2)
+	// A cached variant hit makes the image 1 more recent than the image 2.
+	l.LoadImageTinted(1, red)
+	l.LoadImage(3)
+
+	for _, test := range []struct {
+		id     ImageID
+		loaded bool
+	}{
+		{1, true},
+		{2, false},
+		{3, true},
+	} {
+		if have := l.IsLoaded(test.id.Ref()); have != test.loaded {
+			t.Errorf("image %d: loaded=%v, want %v", test.id, have, test.loaded)
+		}
+	}
+}
+
+func TestCloseResetsLoadState(t *testing.T) {
+	l := NewLoader(nil)
+	l.LRUCacheLimit = 1 << 20
+	l.RawRegistry.Assign(map[RawID]RawInfo{
+		1: {Path: "1.txt", Data: []byte("x"), Soft: true},
+		2: {Path: "2.txt", Data: []byte("x")},
+	})
+	l.LoadRaw(1)
+	l.Prefetch(RawID(2).Ref())
+	l.Close()
+
+	if len(l.softEntries) != 0 || l.softSize != 0 {
+		t.Fatalf("soft cache is not reset: %v (%d bytes)", l.softEntries, l.softSize)
+	}
+	if len(l.lastUsed) != 0 {
+		t.Fatalf("LRU state is not reset: %v", l.lastUsed)
+	}
+	if len(l.loadStarts) != 0 {
+		t.Fatalf("load starts are not reset: %v", l.loadStarts)
+	}
+	l.PrefetchStep(time.Second)
+	if l.IsLoaded(RawID(2).Ref()) {
+		t.Fatal("the load queue is not reset")
+	}
+}
+
+func TestLRUSizeTracking(t *testing.T) {
+	l := NewLoader(nil)
+	l.LRUCacheLimit = 1 << 20
+	l.RawRegistry.Assign(map[RawID]RawInfo{
+		1: {Path: "1.txt", Data: []byte("abc")},
+		2: {Path: "2.txt", Data: []byte("de")},
+	})
+	l.WriteAssetFunc = func(path string, data []byte) error { return nil }
+
+	l.LoadRaw(1)
+	l.LoadRaw(2)
+	if l.lruSize != 5 {
+		t.Fatalf("after load: lruSize=%d, want 5", l.lruSize)
+	}
+	if err := l.SaveRaw(1, []byte("abcdef")); err != nil {
+		t.Fatal(err)
+	}
+	if l.lruSize != 8 {
+		t.Fatalf("after save: lruSize=%d, want 8", l.lruSize)
+	}
+	l.Unload(RawID(2).Ref())
+	if l.lruSize != 6 {
+		t.Fatalf("after unload: lruSize=%d, want 6", l.lruSize)
+	}
+	l.Close()
+	if l.lruSize != 0 {
+		t.Fatalf("after close: lruSize=%d, want 0", l.lruSize)
+	}
+}
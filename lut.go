@@ -0,0 +1,143 @@
+package resource
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// decodeLUT decodes a color lookup table.
+// The .cube files are parsed as Adobe/Resolve cube LUTs,
+// everything else is decoded as a strip image.
+//
+// The result is always a strip image: N blue slices of N*N size
+// that are laid out horizontally (N*N wide, N high).
+// Inside every slice, the red channel grows along the X axis
+// and the green channel grows along the Y axis.
+func decodeLUT(r io.Reader, path string) (image.Image, int, error) {
+	if strings.HasSuffix(path, ".cube") {
+		return decodeCubeLUT(r)
+	}
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	b := img.Bounds()
+	size := b.Dy()
+	if size < 2 || b.Dx() != size*size {
+		return nil, 0, fmt.Errorf("strip LUT image should be N*N wide and N high, got %dx%d", b.Dx(), b.Dy())
+	}
+	return img, size, nil
+}
+
+func decodeCubeLUT(r io.Reader) (image.Image, int, error) {
+	s := bufio.NewScanner(r)
+	size := 0
+	domainMin := [3]float64{0, 0, 0}
+	domainMax := [3]float64{1, 1, 1}
+	var img *image.NRGBA
+	i := 0
+	line := 0
+	for s.Scan() {
+		line++
+		text := strings.TrimSpace(s.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		switch fields[0] {
+		case "TITLE":
+			continue
+		case "LUT_1D_SIZE":
+			return nil, 0, errors.New("1D cube LUTs are not supported")
+		case "LUT_3D_SIZE":
+			if len(fields) != 2 {
+				return nil, 0, fmt.Errorf("line %d: invalid LUT_3D_SIZE", line)
+			}
+			n, err := strconv.Atoi(fields[1])
+			if err != nil || n < 2 || n > 256 {
+				return nil, 0, fmt.Errorf("line %d: invalid LUT_3D_SIZE value", line)
+			}
+			size = n
+			img = image.NewNRGBA(image.Rect(0, 0, size*size, size))
+			continue
+		case "DOMAIN_MIN", "DOMAIN_MAX":
+			values, err := parseCubeTriple(fields[1:])
+			if err != nil {
+				return nil, 0, fmt.Errorf("line %d: %s: %w", line, fields[0], err)
+			}
+			if fields[0] == "DOMAIN_MIN" {
+				domainMin = values
+			} else {
+				domainMax = values
+			}
+			continue
+		}
+		if img == nil {
+			return nil, 0, fmt.Errorf("line %d: table data before LUT_3D_SIZE", line)
+		}
+		if i == 0 {
+			for j := range domainMin {
+				if domainMax[j] <= domainMin[j] {
+					return nil, 0, fmt.Errorf("line %d: DOMAIN_MAX should be greater than DOMAIN_MIN", line)
+				}
+			}
+		}
+		values, err := parseCubeTriple(fields)
+		if err != nil {
+			return nil, 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		if i >= size*size*size {
+			return nil, 0, fmt.Errorf("line %d: too many table entries", line)
+		}
+		var c [3]uint8
+		for j, v := range values {
+			c[j] = cubeComponent((v - domainMin[j]) / (domainMax[j] - domainMin[j]))
+		}
+		// The red index changes fastest, then green, then blue.
+		red, green, blue := i%size, (i/size)%size, i/(size*size)
+		img.SetNRGBA(blue*size+red, green, color.NRGBA{R: c[0], G: c[1], B: c[2], A: 0xff})
+		i++
+	}
+	if err := s.Err(); err != nil {
+		return nil, 0, err
+	}
+	if img == nil {
+		return nil, 0, errors.New("LUT_3D_SIZE is missing")
+	}
+	if i != size*size*size {
+		return nil, 0, fmt.Errorf("expected %d table entries, found %d", size*size*size, i)
+	}
+	return img, size, nil
+}
+
+func parseCubeTriple(fields []string) ([3]float64, error) {
+	var result [3]float64
+	if len(fields) != 3 {
+		return result, errors.New("expected 3 values")
+	}
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return result, err
+		}
+		result[i] = v
+	}
+	return result, nil
+}
+
+func cubeComponent(v float64) uint8 {
+	switch {
+	case v <= 0:
+		return 0
+	case v >= 1:
+		return 0xff
+	default:
+		return uint8(v*0xff + 0.5)
+	}
+}
@@ -0,0 +1,136 @@
+package resource
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+// identityCube is a 2x2x2 identity LUT with the red index changing fastest.
+const identityCube = `
+0 0 0
+1 0 0
+0 1 0
+1 1 0
+0 0 1
+1 0 1
+0 1 1
+1 1 1
+`
+
+func TestDecodeCubeLUT(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		// want maps the strip pixel position to its color.
+		want map[image.Point]color.NRGBA
+	}{
+		{
+			name: "identity",
+			data: "TITLE \"identity\"\n# comment\nLUT_3D_SIZE 2\n" + identityCube,
+			want: map[image.Point]color.NRGBA{
+				{0, 0}: {0, 0, 0, 0xff},
+				{1, 0}: {0xff, 0, 0, 0xff},
+				{0, 1}: {0, 0xff, 0, 0xff},
+				{3, 1}: {0xff, 0xff, 0xff, 0xff},
+				{2, 0}: {0, 0, 0xff, 0xff},
+			},
+		},
+		{
+			name: "domain",
+			data: "LUT_3D_SIZE 2\nDOMAIN_MIN 0 0 0\nDOMAIN_MAX 2 2 2\n" + strings.ReplaceAll(identityCube, "1", "2"),
+			want: map[image.Point]color.NRGBA{
+				{1, 0}: {0xff, 0, 0, 0xff},
+				{3, 1}: {0xff, 0xff, 0xff, 0xff},
+			},
+		},
+		{
+			name: "clamp",
+			data: "LUT_3D_SIZE 2\n-1 0.5 2\n" + strings.Join(strings.Split(strings.TrimSpace(identityCube), "\n")[1:], "\n"),
+			want: map[image.Point]color.NRGBA{
+				{0, 0}: {0, 0x80, 0xff, 0xff},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img, size, err := decodeLUT(strings.NewReader(test.data), "grade.cube")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != 2 || img.Bounds() != image.Rect(0, 0, 4, 2) {
+				t.Fatalf("unexpected LUT size %d (bounds %v)", size, img.Bounds())
+			}
+			for pt, want := range test.want {
+				if have := color.NRGBAModel.Convert(img.At(pt.X, pt.Y)); have != want {
+					t.Errorf("pixel %v: have %v, want %v", pt, have, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeCubeLUTErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"empty", "", "LUT_3D_SIZE is missing"},
+		{"1d", "LUT_1D_SIZE 16", "1D cube LUTs are not supported"},
+		{"size args", "LUT_3D_SIZE", "line 1: invalid LUT_3D_SIZE"},
+		{"size value", "LUT_3D_SIZE 1", "line 1: invalid LUT_3D_SIZE value"},
+		{"size too big", "LUT_3D_SIZE 257", "line 1: invalid LUT_3D_SIZE value"},
+		{"data first", "0 0 0\nLUT_3D_SIZE 2", "line 1: table data before LUT_3D_SIZE"},
+		{"domain", "DOMAIN_MIN 0 0", "line 1: DOMAIN_MIN: expected 3 values"},
+		{"empty domain", "LUT_3D_SIZE 2\nDOMAIN_MAX 0 1 1\n0 0 0", "line 3: DOMAIN_MAX should be greater than DOMAIN_MIN"},
+		{"triple", "LUT_3D_SIZE 2\n0 0", "line 2: expected 3 values"},
+		{"number", "LUT_3D_SIZE 2\n0 0 x", `line 2: strconv.ParseFloat: parsing "x"`},
+		{"too few", "LUT_3D_SIZE 2\n0 0 0", "expected 8 table entries, found 1"},
+		{"too many", "LUT_3D_SIZE 2\n" + identityCube + "0 0 0", "line 11: too many table entries"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := decodeLUT(strings.NewReader(test.data), "grade.cube")
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("error mismatch:\nhave: %v\nwant: %q", err, test.want)
+			}
+		})
+	}
+}
+
+func TestDecodeStripLUT(t *testing.T) {
+	tests := []struct {
+		width, height int
+		err           string
+	}{
+		{16, 4, ""},
+		{64, 8, ""},
+		{16, 8, "strip LUT image should be N*N wide and N high, got 16x8"},
+		{1, 1, "strip LUT image should be N*N wide and N high, got 1x1"},
+	}
+
+	for _, test := range tests {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, test.width, test.height))); err != nil {
+			t.Fatal(err)
+		}
+		_, size, err := decodeLUT(&buf, "grade.png")
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%dx%d: error mismatch:\nhave: %v\nwant: %q", test.width, test.height, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%dx%d: unexpected error: %v", test.width, test.height, err)
+		} else if size != test.height {
+			t.Errorf("%dx%d: size mismatch: have %d, want %d", test.width, test.height, size, test.height)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package resource
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ZipAssets reads the assets from a zip archive,
+// like an Android APK or an OBB expansion file.
+//
+// Its Open method can be used as the Loader.OpenAssetErrFunc:
+//
+//	assets, err := resource.OpenZipAssets(resource.AndroidOBBPath("com.example.game", 12), "")
+//	if err != nil {
+//		// Handle the error.
+//	}
+//	l.OpenAssetErrFunc = assets.Open
+type ZipAssets struct {
+	archive *zip.ReadCloser
+	files   map[string]*zip.File
+}
+
+// OpenZipAssets opens the zip archive for reading.
+// The dir is an archive directory that is treated as the assets root;
+// an empty dir means the archive root.
+func OpenZipAssets(archivePath, dir string) (*ZipAssets, error) {
+	archive, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, err
+	}
+	a := &ZipAssets{
+		archive: archive,
+		files:   make(map[string]*zip.File, len(archive.File)),
+	}
+	prefix := ""
+	if dir = strings.Trim(dir, "/"); dir != "" {
+		prefix = dir + "/"
+	}
+	for _, f := range archive.File {
+		if name := strings.TrimPrefix(f.Name, prefix); name != f.Name || prefix == "" {
+			a.files[name] = f
+		}
+	}
+	return a, nil
+}
+
+// OpenAndroidAPKAssets opens the "assets" directory of the Android APK.
+//
+// The APK path should be provided by the Java side
+// (see ApplicationInfo.sourceDir), as it differs between the devices.
+func OpenAndroidAPKAssets(apkPath string) (*ZipAssets, error) {
+	return OpenZipAssets(apkPath, "assets")
+}
+
+// Open opens the asset file.
+// The path uses forward slashes and it's relative to the assets root.
+func (a *ZipAssets) Open(path string) (io.ReadCloser, error) {
+	f, ok := a.files[strings.TrimPrefix(path, "./")]
+	if !ok {
+		return nil, fmt.Errorf("open %q: %w", path, fs.ErrNotExist)
+	}
+	return f.Open()
+}
+
+// Close closes the zip archive.
+func (a *ZipAssets) Close() error {
+	return a.archive.Close()
+}
+
+// AndroidOBBPath returns the main expansion file path
+// for the given application package and version code.
+//
+// The expansion files are stored in the shared storage
+// as /sdcard/Android/obb/<package>/main.<version>.<package>.obb,
+// use OpenZipAssets to read them (the OBB should be a zip archive).
+func AndroidOBBPath(packageName string, versionCode int) string {
+	return fmt.Sprintf("/sdcard/Android/obb/%s/main.%d.%s.obb", packageName, versionCode, packageName)
+}
+
+// NewBundleOpener returns a function that can be used as the Loader.OpenAssetErrFunc
+// to read the assets that are shipped along with the executable.
+//
+// The dir is relative to the executable directory.
+// For the iOS app bundles, the resources are stored next to the executable,
+// so it's usually a bundle subdirectory like "assets".
+// For the macOS app bundles, the executable is in Contents/MacOS,
+// so the dir is "../Resources".
+//
+// Unlike the working directory, the executable location doesn't depend
+// on how the game was launched.
+func NewBundleOpener(dir string) func(path string) (io.ReadCloser, error) {
+	return func(path string) (io.ReadCloser, error) {
+		exe, err := os.Executable()
+		if err != nil {
+			return nil, err
+		}
+		return os.Open(filepath.Join(filepath.Dir(exe), dir, filepath.FromSlash(path)))
+	}
+}
@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+type contentMount struct {
+	name    string
+	fsys    fs.FS
+	enabled bool
+}
+
+func (m *contentMount) has(path string) bool {
+	if !fs.ValidPath(path) {
+		return false
+	}
+	_, err := fs.Stat(m.fsys, path)
+	return err == nil
+}
+
+// MountContent adds an extra content root, like a DLC
+// or a workshop item downloaded mid-session.
+//
+// The mounted files override the assets with the same paths:
+// the later mounts take precedence over the earlier ones
+// and all of them take precedence over the OpenAssetFunc.
+// The resources with the Data set in their infos are not affected.
+//
+// The loaded resources that are overridden by the new mount
+// are unloaded (along with the resources that depend on them),
+// so they're loaded from the mounted content on the next use.
+//
+// Mounting the content with the same name replaces the previous mount.
+func (l *Loader) MountContent(name string, fsys fs.FS) {
+	l.UnmountContent(name)
+	m := &contentMount{name: name, fsys: fsys, enabled: true}
+	l.mounts = append(l.mounts, m)
+	l.invalidateMounted(m)
+}
+
+// UnmountContent removes the content root added by MountContent.
+// The resources that were loaded from it are unloaded.
+//
+// It reports whether the content was mounted.
+func (l *Loader) UnmountContent(name string) bool {
+	for i, m := range l.mounts {
+		if m.name != name {
+			continue
+		}
+		l.mounts = append(l.mounts[:i], l.mounts[i+1:]...)
+		if m.enabled {
+			l.invalidateMounted(m)
+		}
+		return true
+	}
+	return false
+}
+
+// SetContentEnabled enables or disables the mounted content
+// without unmounting it, like a DLC toggle in the game settings.
+// The overridden resources are unloaded, see MountContent.
+func (l *Loader) SetContentEnabled(name string, enabled bool) {
+	for _, m := range l.mounts {
+		if m.name != name {
+			continue
+		}
+		if m.enabled != enabled {
+			m.enabled = enabled
+			l.invalidateMounted(m)
+		}
+		return
+	}
+	panic(fmt.Sprintf("content %q is not mounted", name))
+}
+
+// MountedContent returns the mounted content names in their mount order.
+func (l *Loader) MountedContent() []string {
+	names := make([]string, len(l.mounts))
+	for i, m := range l.mounts {
+		names[i] = m.name
+	}
+	return names
+}
+
+// openMounted opens the path from the enabled content mounts.
+// It reports false if none of them has this path.
+func (l *Loader) openMounted(path string) (io.ReadCloser, bool, error) {
+	for i := len(l.mounts) - 1; i >= 0; i-- {
+		m := l.mounts[i]
+		if !m.enabled || !m.has(path) {
+			continue
+		}
+		f, err := m.fsys.Open(path)
+		return f, true, err
+	}
+	return nil, false, nil
+}
+
+// invalidateMounted unloads the resources that are overridden by m.
+// The patched assets cache is invalidated for the overridden paths too.
+//
+// The resources are matched by the paths they have opened,
+// so all path mappings (like SetPathVar and PathRedirects) are taken into account.
+func (l *Loader) invalidateMounted(m *contentMount) {
+	for key := range l.patchedAssets {
+		if m.has(key.path) {
+			delete(l.patchedAssets, key)
+		}
+	}
+	for _, ref := range l.loadedRefs() {
+		for _, path := range l.openedPaths[ref] {
+			if m.has(path) {
+				l.unloadWithDependents(ref)
+				break
+			}
+		}
+	}
+}
+
+// recordOpenedPath remembers that the resource has opened the path.
+func (l *Loader) recordOpenedPath(ref Ref, path string) {
+	for _, p := range l.openedPaths[ref] {
+		if p == path {
+			return
+		}
+	}
+	if l.openedPaths == nil {
+		l.openedPaths = make(map[Ref][]string)
+	}
+	l.openedPaths[ref] = append(l.openedPaths[ref], path)
+}
+
+// unloadWithDependents unloads the resource along with
+// all loaded resources that depend on it.
+func (l *Loader) unloadWithDependents(ref Ref) {
+	for dependent, deps := range l.deps {
+		for _, dep := range deps {
+			if dep == ref {
+				l.unloadWithDependents(dependent)
+				break
+			}
+		}
+	}
+	l.Unload(ref)
+}
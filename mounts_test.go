@@ -0,0 +1,123 @@
+package resource
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMountContentMappedPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		setup     func(l *Loader)
+		mountPath string
+	}{
+		{
+			name:      "plain",
+			path:      "data/a.txt",
+			mountPath: "data/a.txt",
+		},
+		{
+			name:      "path var",
+			path:      "data/{lang}/a.txt",
+			setup:     func(l *Loader) { l.SetPathVar("lang", "en") },
+			mountPath: "data/en/a.txt",
+		},
+		{
+			name:      "redirect",
+			path:      "old/a.txt",
+			setup:     func(l *Loader) { l.PathRedirects = map[string]string{"old/a.txt": "data/a.txt"} },
+			mountPath: "data/a.txt",
+		},
+		{
+			name:      "normalization",
+			path:      `Data\A.txt`,
+			setup:     func(l *Loader) { l.PathNormalization = NormalizeAll },
+			mountPath: "data/a.txt",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := NewLoader(nil)
+			l.OpenAssetFunc = func(path string) io.ReadCloser {
+				return io.NopCloser(strings.NewReader("base"))
+			}
+			if test.setup != nil {
+				test.setup(l)
+			}
+			l.RawRegistry.Set(1, RawInfo{Path: test.path})
+
+			if data := string(l.LoadRaw(1).Data); data != "base" {
+				t.Fatalf("have %q before the mount, want %q", data, "base")
+			}
+			l.MountContent("dlc", fstest.MapFS{
+				test.mountPath: {Data: []byte("dlc")},
+			})
+			if l.IsLoaded(RawID(1).Ref()) {
+				t.Fatal("the overridden resource is still loaded")
+			}
+			if data := string(l.LoadRaw(1).Data); data != "dlc" {
+				t.Fatalf("have %q after the mount, want %q", data, "dlc")
+			}
+
+			clone := l.CloneRegistries()
+			if data := string(clone.LoadRaw(1).Data); data != "dlc" {
+				t.Fatalf("clone: have %q, want %q", data, "dlc")
+			}
+			clone.SetContentEnabled("dlc", false)
+			if data := string(l.LoadRaw(1).Data); data != "dlc" {
+				t.Fatalf("disabling the clone content affected the original loader")
+			}
+		})
+	}
+}
+
+func TestWriteAssetMappedPaths(t *testing.T) {
+	l := NewLoader(nil)
+	l.SetBasePath("assets")
+	l.SetPathVar("lang", "en")
+	l.PathRedirects = map[string]string{"old/a.txt": "data/en/a.txt"}
+	l.PathNormalization = NormalizeAll
+	var written []string
+	l.WriteAssetFunc = func(path string, data []byte) error {
+		written = append(written, path)
+		return nil
+	}
+	l.RawRegistry.Set(1, RawInfo{Path: `Data\{lang}\B.txt`})
+
+	if err := l.WriteAsset("old/a.txt", []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.SaveRaw(1, []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"assets/data/en/a.txt", "assets/data/en/b.txt"}
+	if strings.Join(written, ",") != strings.Join(want, ",") {
+		t.Fatalf("written paths mismatch:\nhave: %q\nwant: %q", written, want)
+	}
+}
+
+func TestBasePathLeadingSlash(t *testing.T) {
+	tests := []struct {
+		base string
+		path string
+		want string
+	}{
+		{base: "", path: "/a.txt", want: "/a.txt"},
+		{base: "assets", path: "a.txt", want: "assets/a.txt"},
+		{base: "assets", path: "/a.txt", want: "assets/a.txt"},
+		{base: "assets/", path: "/a.txt", want: "assets/a.txt"},
+		{base: "https://cdn.example.com/game", path: "/ui/a.png", want: "https://cdn.example.com/game/ui/a.png"},
+	}
+
+	for _, test := range tests {
+		l := NewLoader(nil)
+		l.SetBasePath(test.base)
+		if have := l.rewritePath(test.path); have != test.want {
+			t.Errorf("rewritePath(%q) with %q base: have %q, want %q", test.path, test.base, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package resource
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+)
+
+// oggStream is a decoded OGG stream that knows its source sample rate.
+type oggStream struct {
+	*vorbis.Stream
+
+	// src is the encoded data reader, it's nil for the in-memory data.
+	src io.Closer
+
+	sampleRate int
+}
+
+// SampleRate returns the source OGG sample rate.
+// It's zero if the rate is unknown.
+func (s *oggStream) SampleRate() int { return s.sampleRate }
+
+// Close closes the encoded data reader.
+func (s *oggStream) Close() error {
+	if s.src == nil {
+		return nil
+	}
+	return s.src.Close()
+}
+
+// oggHeaderPeekSize is enough to read the first OGG page
+// that contains the Vorbis identification header.
+const oggHeaderPeekSize = 512
+
+// oggSampleRate returns the sample rate from the Vorbis identification
+// header that starts the OGG data.
+// It returns 0 if the header can't be parsed.
+func oggSampleRate(data []byte) int {
+	const pageHeaderSize = 27
+	if len(data) < pageHeaderSize || string(data[:4]) != "OggS" {
+		return 0
+	}
+	packetStart := pageHeaderSize + int(data[pageHeaderSize-1])
+	if len(data) < packetStart {
+		return 0
+	}
+	// The identification packet starts with its type (1) and "vorbis",
+	// followed by the version (4 bytes), the channels (1 byte)
+	// and the sample rate (4 bytes).
+	packet := data[packetStart:]
+	if len(packet) < 16 || packet[0] != 1 || string(packet[1:7]) != "vorbis" {
+		return 0
+	}
+	return int(binary.LittleEndian.Uint32(packet[12:]))
+}
+
+// peekOGGSampleRate reads the sample rate from the OGG stream header
+// and rewinds the stream.
+// It returns 0 if r is not an io.Seeker or if the header can't be parsed.
+func peekOGGSampleRate(r io.Reader) (int, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		return 0, nil
+	}
+	buf := make([]byte, oggHeaderPeekSize)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return 0, err
+	}
+	if _, err := seeker.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return oggSampleRate(buf[:n]), nil
+}
@@ -0,0 +1,71 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// makeTestOGGHeader returns the first OGG page with the Vorbis identification header.
+func makeTestOGGHeader(sampleRate uint32) []byte {
+	packet := make([]byte, 30)
+	packet[0] = 1
+	copy(packet[1:], "vorbis")
+	packet[11] = 2 // channels
+	binary.LittleEndian.PutUint32(packet[12:], sampleRate)
+	packet[29] = 1 // framing flag
+
+	page := make([]byte, 27, 27+1+len(packet))
+	copy(page, "OggS")
+	page[5] = 0x02 // beginning of stream
+	page[26] = 1   // segments number
+	page = append(page, byte(len(packet)))
+	return append(page, packet...)
+}
+
+func TestOGGSampleRate(t *testing.T) {
+	valid := makeTestOGGHeader(22050)
+	badPacket := makeTestOGGHeader(22050)
+	badPacket[28] = 3 // the comment header type
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{"valid", valid, 22050},
+		{"44100", makeTestOGGHeader(44100), 44100},
+		{"trailing data", append(makeTestOGGHeader(48000), "more pages"...), 48000},
+		{"nil", nil, 0},
+		{"not ogg", []byte("RIFF....WAVEfmt ........................"), 0},
+		{"short page", valid[:20], 0},
+		{"short packet", valid[:len(valid)-16], 0},
+		{"not identification packet", badPacket, 0},
+	}
+
+	for _, test := range tests {
+		if have := oggSampleRate(test.data); have != test.want {
+			t.Errorf("%s: have %d, want %d", test.name, have, test.want)
+		}
+	}
+}
+
+func TestPeekOGGSampleRate(t *testing.T) {
+	data := append(makeTestOGGHeader(32000), make([]byte, 1000)...)
+	r := bytes.NewReader(data)
+	rate, err := peekOGGSampleRate(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rate != 32000 {
+		t.Fatalf("have %d rate, want 32000", rate)
+	}
+	if r.Len() != len(data) {
+		t.Fatal("the stream is not rewound")
+	}
+
+	// The rate can't be peeked without seeking.
+	rate, err = peekOGGSampleRate(bytes.NewBuffer(data))
+	if err != nil || rate != 0 {
+		t.Fatalf("non-seeker: have (%d, %v), want (0, nil)", rate, err)
+	}
+}
@@ -0,0 +1,95 @@
+package resource
+
+import (
+	"reflect"
+	"time"
+)
+
+// ScheduledOverride replaces the resource path during the given date range,
+// like the holiday-themed menu art.
+// See Loader.ScheduledOverrides.
+type ScheduledOverride struct {
+	// Ref is the resource to override.
+	Ref Ref
+
+	// Path is used instead of the resource info path while the override is active.
+	Path string
+
+	// From and Until define the [From, Until) active time range.
+	// A zero value means the range is not bounded from that side.
+	From  time.Time
+	Until time.Time
+
+	// Yearly makes the override repeat every year:
+	// only the month, day and time parts of From and Until are used.
+	// The ranges that cross the year boundary (Dec 20 - Jan 5) are permitted.
+	Yearly bool
+}
+
+// IsActive reports whether the override is active at the given time.
+func (o ScheduledOverride) IsActive(now time.Time) bool {
+	if !o.Yearly {
+		return (o.From.IsZero() || !now.Before(o.From)) && (o.Until.IsZero() || now.Before(o.Until))
+	}
+	if o.From.IsZero() || o.Until.IsZero() {
+		return true
+	}
+	from := sameYear(o.From, now)
+	until := sameYear(o.Until, now)
+	if until.Before(from) {
+		// The range wraps around the year end.
+		return !now.Before(from) || now.Before(until)
+	}
+	return !now.Before(from) && now.Before(until)
+}
+
+func sameYear(t, now time.Time) time.Time {
+	return time.Date(now.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), now.Location())
+}
+
+func (l *Loader) now() time.Time {
+	if l.NowFunc != nil {
+		return l.NowFunc()
+	}
+	return time.Now()
+}
+
+// scheduledPath returns the path of the active override for ref.
+// The last active override wins.
+func (l *Loader) scheduledPath(ref Ref) (string, bool) {
+	if len(l.ScheduledOverrides) == 0 {
+		return "", false
+	}
+	now := l.now()
+	for i := len(l.ScheduledOverrides) - 1; i >= 0; i-- {
+		o := l.ScheduledOverrides[i]
+		if l.resolveRef(o.Ref) == ref && o.IsActive(now) {
+			return o.Path, true
+		}
+	}
+	return "", false
+}
+
+// applyScheduledOverride sets the info Path field to the active override path.
+func applyScheduledOverride[T any](l *Loader, ref Ref, info T) T {
+	path, ok := l.scheduledPath(ref)
+	if !ok {
+		return info
+	}
+	v := reflect.ValueOf(&info).Elem()
+	if v.Kind() != reflect.Struct {
+		return info
+	}
+	if f := v.FieldByName("Path"); f.IsValid() && f.Kind() == reflect.String {
+		f.SetString(path)
+		// The override path applies to all platforms.
+		if byPlatform := v.FieldByName("PathByPlatform"); byPlatform.IsValid() && byPlatform.Kind() == reflect.Map {
+			byPlatform.Set(reflect.Zero(byPlatform.Type()))
+		}
+		// Data would take precedence over the path.
+		if data := v.FieldByName("Data"); data.IsValid() && data.Kind() == reflect.Slice {
+			data.Set(reflect.Zero(data.Type()))
+		}
+	}
+	return info
+}
@@ -0,0 +1,146 @@
+package resource
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func decodePalette(path string, data []byte) (color.Palette, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".pal":
+		return decodeJASCPalette(data)
+	case ".gpl":
+		return decodeGIMPPalette(data)
+	case ".json":
+		return decodeJSONPalette(data)
+	default:
+		return nil, errors.New("unrecognized palette format")
+	}
+}
+
+func decodeJASCPalette(data []byte) (color.Palette, error) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	var lines []string
+	for s.Scan() {
+		if l := strings.TrimSpace(s.Text()); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) < 3 || lines[0] != "JASC-PAL" {
+		return nil, errors.New("missing JASC-PAL header")
+	}
+	n, err := strconv.Atoi(lines[2])
+	if err != nil {
+		return nil, fmt.Errorf("bad colors count: %w", err)
+	}
+	if len(lines)-3 < n {
+		return nil, fmt.Errorf("expected %d colors, found %d", n, len(lines)-3)
+	}
+	colors := make(color.Palette, 0, n)
+	for _, l := range lines[3 : 3+n] {
+		c, err := parseRGBFields(strings.Fields(l))
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, nil
+}
+
+func decodeGIMPPalette(data []byte) (color.Palette, error) {
+	s := bufio.NewScanner(bytes.NewReader(data))
+	if !s.Scan() || strings.TrimSpace(s.Text()) != "GIMP Palette" {
+		return nil, errors.New("missing GIMP Palette header")
+	}
+	var colors color.Palette
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		switch {
+		case l == "", strings.HasPrefix(l, "#"):
+			continue
+		case strings.HasPrefix(l, "Name:"), strings.HasPrefix(l, "Columns:"):
+			continue
+		}
+		fields := strings.Fields(l)
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("bad color line %q", l)
+		}
+		// The fields after RGB components are the color name.
+		c, err := parseRGBFields(fields[:3])
+		if err != nil {
+			return nil, err
+		}
+		colors = append(colors, c)
+	}
+	return colors, s.Err()
+}
+
+func decodeJSONPalette(data []byte) (color.Palette, error) {
+	var hexColors []string
+	if err := json.Unmarshal(data, &hexColors); err != nil {
+		return nil, err
+	}
+	colors := make(color.Palette, len(hexColors))
+	for i, s := range hexColors {
+		c, err := parseHexColor(s)
+		if err != nil {
+			return nil, err
+		}
+		colors[i] = c
+	}
+	return colors, nil
+}
+
+func parseRGBFields(fields []string) (color.RGBA, error) {
+	if len(fields) != 3 {
+		return color.RGBA{}, fmt.Errorf("expected 3 color components, found %d", len(fields))
+	}
+	var rgb [3]uint8
+	for i, f := range fields {
+		v, err := strconv.ParseUint(f, 10, 8)
+		if err != nil {
+			return color.RGBA{}, fmt.Errorf("bad color component %q", f)
+		}
+		rgb[i] = uint8(v)
+	}
+	return color.RGBA{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}, nil
+}
+
+// parseHexColor parses "#rrggbb" and "#rrggbbaa" colors.
+// The alpha component is not premultiplied.
+func parseHexColor(s string) (color.NRGBA, error) {
+	digits := strings.TrimPrefix(s, "#")
+	if len(digits) != 6 && len(digits) != 8 {
+		return color.NRGBA{}, fmt.Errorf("bad hex color %q", s)
+	}
+	v, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("bad hex color %q", s)
+	}
+	if len(digits) == 6 {
+		v = v<<8 | 0xff
+	}
+	return color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+}
+
+// swapPalette returns a copy of img that uses the specified palette colors.
+// The colors that are not covered by the palette are left as is.
+func swapPalette(img *image.Paletted, colors color.Palette) *image.Paletted {
+	newPalette := make(color.Palette, len(img.Palette))
+	copy(newPalette, img.Palette)
+	copy(newPalette, colors)
+	return &image.Paletted{
+		Pix:     img.Pix,
+		Stride:  img.Stride,
+		Rect:    img.Rect,
+		Palette: newPalette,
+	}
+}
@@ -0,0 +1,153 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ParticleEmitter describes a single particle emitter of the ParticleDef.
+type ParticleEmitter struct {
+	// Name is an optional emitter name, it's used in the error messages.
+	Name string `json:"name"`
+
+	// ImageName is a particle texture name (a ParticleDefInfo.Images key).
+	ImageName string `json:"image"`
+
+	// Image is a particle texture resolved from the ImageName.
+	Image ImageID `json:"-"`
+
+	// Rate is a number of particles emitted per second.
+	Rate float64 `json:"rate"`
+
+	// MaxParticles limits the number of the simultaneously alive particles.
+	// Zero means "no limit".
+	MaxParticles int `json:"max_particles"`
+
+	// Lifetime is a particle lifetime range (in seconds).
+	Lifetime ParticleRange `json:"lifetime"`
+
+	// Speed is a particle initial speed range (in pixels per second).
+	Speed ParticleRange `json:"speed"`
+
+	// Angle is a particle emission direction range (in radians).
+	Angle ParticleRange `json:"angle"`
+
+	// Scale and Alpha curves are sampled by the normalized
+	// particle age, see ParticleCurve.At.
+	Scale ParticleCurve `json:"scale"`
+	Alpha ParticleCurve `json:"alpha"`
+}
+
+// ParticleRange is a closed [Min, Max] range of values.
+// The emitter picks a random value from this range for every particle.
+type ParticleRange struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// ParticleCurve is a piecewise linear function of t in [0, 1].
+// The points are sorted by their T values.
+type ParticleCurve []ParticleCurvePoint
+
+type ParticleCurvePoint struct {
+	T     float64 `json:"t"`
+	Value float64 `json:"value"`
+}
+
+// At returns the curve value at t.
+// The t values outside of the curve points range are clamped.
+// An empty curve is a constant 1.
+func (c ParticleCurve) At(t float64) float64 {
+	if len(c) == 0 {
+		return 1
+	}
+	if t <= c[0].T {
+		return c[0].Value
+	}
+	for i := 1; i < len(c); i++ {
+		if t <= c[i].T {
+			a := c[i-1]
+			b := c[i]
+			if b.T == a.T {
+				return b.Value
+			}
+			return a.Value + (b.Value-a.Value)*((t-a.T)/(b.T-a.T))
+		}
+	}
+	return c[len(c)-1].Value
+}
+
+func decodeParticleDef(r io.Reader, info ParticleDefInfo, imageRegistry *registry[ImageID, ImageInfo]) (ParticleDef, error) {
+	var def ParticleDef
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return def, err
+	}
+	if len(def.Emitters) == 0 {
+		return def, errors.New("no emitters defined")
+	}
+	for i, e := range def.Emitters {
+		name := e.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+		imageID, ok := info.Images[e.ImageName]
+		if !ok {
+			return def, fmt.Errorf("emitter %s: unknown image %q", name, e.ImageName)
+		}
+		def.Emitters[i].Image = imageID
+		if err := validateParticleEmitter(def.Emitters[i], imageRegistry); err != nil {
+			return def, fmt.Errorf("emitter %s: %w", name, err)
+		}
+	}
+	return def, nil
+}
+
+func validateParticleEmitter(e ParticleEmitter, imageRegistry *registry[ImageID, ImageInfo]) error {
+	if _, ok := imageRegistry.mapping[imageRegistry.resolve(e.Image)]; !ok {
+		return fmt.Errorf("image %q: unregistered image with id=%d", e.ImageName, e.Image)
+	}
+	if e.Rate < 0 {
+		return fmt.Errorf("negative rate %v", e.Rate)
+	}
+	if e.MaxParticles < 0 {
+		return fmt.Errorf("negative max_particles %d", e.MaxParticles)
+	}
+	ranges := []struct {
+		name string
+		r    ParticleRange
+	}{
+		{"lifetime", e.Lifetime},
+		{"speed", e.Speed},
+		{"angle", e.Angle},
+	}
+	for _, x := range ranges {
+		if x.r.Min > x.r.Max {
+			return fmt.Errorf("%s: min (%v) is greater than max (%v)", x.name, x.r.Min, x.r.Max)
+		}
+	}
+	if e.Lifetime.Max <= 0 {
+		return errors.New("lifetime: max should be positive")
+	}
+	curves := []struct {
+		name string
+		c    ParticleCurve
+	}{
+		{"scale", e.Scale},
+		{"alpha", e.Alpha},
+	}
+	for _, x := range curves {
+		for i, p := range x.c {
+			if p.T < 0 || p.T > 1 {
+				return fmt.Errorf("%s: point %d t=%v is outside of [0, 1]", x.name, i, p.T)
+			}
+			if i > 0 && p.T < x.c[i-1].T {
+				return fmt.Errorf("%s: points are not sorted by t", x.name)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package resource
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeParticleDef(t *testing.T) {
+	l := NewLoader(nil)
+	l.ImageRegistry.Set(1, ImageInfo{Path: "spark.png"})
+	l.ImageRegistry.Set(2, ImageInfo{Path: "smoke.png"})
+	info := ParticleDefInfo{
+		Path:   "fx.json",
+		Images: map[string]ImageID{"spark": 1, "smoke": 2, "missing": 3},
+	}
+
+	def, err := decodeParticleDef(strings.NewReader(`{"emitters": [
+		{"name": "sparks", "image": "spark", "rate": 30, "lifetime": {"min": 0.5, "max": 1}},
+		{"image": "smoke", "lifetime": {"max": 2}, "alpha": [{"t": 0, "value": 1}, {"t": 1, "value": 0}]}
+	]}`), info, &l.ImageRegistry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(def.Emitters) != 2 {
+		t.Fatalf("have %d emitters, want 2", len(def.Emitters))
+	}
+	for i, want := range []ImageID{1, 2} {
+		if have := def.Emitters[i].Image; have != want {
+			t.Errorf("emitter %d: image mismatch: have %d, want %d", i, have, want)
+		}
+	}
+	if have := def.Emitters[1].Alpha.At(0.25); have != 0.75 {
+		t.Errorf("alpha at 0.25: have %v, want 0.75", have)
+	}
+
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"syntax", `{"emitters": [`, "unexpected EOF"},
+		{"unknown field", `{"emitters": [{"image": "spark", "colour": 1}]}`, `unknown field "colour"`},
+		{"no emitters", `{"emitters": []}`, "no emitters defined"},
+		{"numeric image", `{"emitters": [{"image": 1}]}`, "cannot unmarshal number"},
+		{"unknown image", `{"emitters": [{"name": "a", "image": "fire", "lifetime": {"max": 1}}]}`, `emitter a: unknown image "fire"`},
+		{"unregistered image", `{"emitters": [{"image": "missing", "lifetime": {"max": 1}}]}`, `emitter #0: image "missing": unregistered image with id=3`},
+		{"negative rate", `{"emitters": [{"image": "spark", "rate": -1, "lifetime": {"max": 1}}]}`, "negative rate -1"},
+		{"bad range", `{"emitters": [{"image": "spark", "lifetime": {"min": 2, "max": 1}}]}`, "lifetime: min (2) is greater than max (1)"},
+		{"no lifetime", `{"emitters": [{"image": "spark"}]}`, "lifetime: max should be positive"},
+		{"curve t", `{"emitters": [{"image": "spark", "lifetime": {"max": 1}, "scale": [{"t": 2}]}]}`, "scale: point 0 t=2 is outside of [0, 1]"},
+		{"curve order", `{"emitters": [{"image": "spark", "lifetime": {"max": 1}, "alpha": [{"t": 1}, {"t": 0}]}]}`, "alpha: points are not sorted by t"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeParticleDef(strings.NewReader(test.data), info, &l.ImageRegistry)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("error mismatch:\nhave: %v\nwant: %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package resource
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// errCorruptPatch is reported for the malformed patch files.
+var errCorruptPatch = errors.New("corrupt patch")
+
+// maxPatchedSize is a max size of the patched asset.
+// The size is read from the patch header, so it's bounded
+// to avoid huge allocations for the corrupted patches.
+const maxPatchedSize = 1 << 30
+
+type patchKey struct {
+	path  string
+	patch string
+}
+
+// openPatched opens the asset with the patch applied.
+// The patched data is cached, so the patch is applied only once.
+func (l *Loader) openPatched(ref Ref, path, patchPath string, open OpenAssetFunc) io.ReadCloser {
+	key := patchKey{path: path, patch: patchPath}
+	if data, ok := l.patchedAssets[key]; ok {
+		return io.NopCloser(bytes.NewReader(data))
+	}
+
+	r := open(l.assetPath(path))
+	if checksum, ok := l.Checksums[path]; ok {
+		r = l.verifyAsset(ref, path, checksum, r)
+	}
+	base := l.readAll(ref, path, r)
+	patch := l.readAll(ref, patchPath, open(l.assetPath(patchPath)))
+	data, err := applyBSDiff(base, patch)
+	if err != nil {
+		panic(newError("patch", ref, path, fmt.Errorf("apply %q: %w", patchPath, err)))
+	}
+
+	if l.patchedAssets == nil {
+		l.patchedAssets = make(map[patchKey][]byte)
+	}
+	l.patchedAssets[key] = data
+	return io.NopCloser(bytes.NewReader(data))
+}
+
+// evictPatchedAssets removes the cached patched data of the path.
+func (l *Loader) evictPatchedAssets(path string) {
+	for key := range l.patchedAssets {
+		if key.path == path {
+			delete(l.patchedAssets, key)
+		}
+	}
+}
+
+func (l *Loader) readAll(ref Ref, path string, r io.ReadCloser) []byte {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		panic(newError("read", ref, path, err))
+	}
+	if err := r.Close(); err != nil {
+		panic(newError("read", ref, path, err))
+	}
+	return data
+}
+
+// applyBSDiff applies the bsdiff (BSDIFF40 format) patch to the old data.
+func applyBSDiff(old, patch []byte) ([]byte, error) {
+	const headerSize = 32
+	if len(patch) < headerSize || string(patch[:8]) != "BSDIFF40" {
+		return nil, fmt.Errorf("%w: bad header", errCorruptPatch)
+	}
+	ctrlLen := offtin(patch[8:])
+	diffLen := offtin(patch[16:])
+	newSize := offtin(patch[24:])
+	// The lengths are checked one by one, so their sum can't overflow.
+	bodySize := int64(len(patch)) - headerSize
+	if ctrlLen < 0 || diffLen < 0 || newSize < 0 || ctrlLen > bodySize || diffLen > bodySize-ctrlLen {
+		return nil, fmt.Errorf("%w: bad header", errCorruptPatch)
+	}
+	if newSize > maxPatchedSize {
+		return nil, fmt.Errorf("%w: new size %d exceeds the %d limit", errCorruptPatch, newSize, maxPatchedSize)
+	}
+
+	ctrlBlock := patch[headerSize : headerSize+ctrlLen]
+	diffBlock := patch[headerSize+ctrlLen : headerSize+ctrlLen+diffLen]
+	extraBlock := patch[headerSize+ctrlLen+diffLen:]
+	ctrl := bzip2.NewReader(bytes.NewReader(ctrlBlock))
+	diff := bzip2.NewReader(bytes.NewReader(diffBlock))
+	extra := bzip2.NewReader(bytes.NewReader(extraBlock))
+
+	// The result grows as the data is actually read,
+	// so a bogus newSize can't cause a large allocation on its own.
+	result := make([]byte, 0, minInt64(newSize, int64(len(old))+int64(len(patch))))
+	var oldPos, newPos int64
+	var buf [24]byte
+	for newPos < newSize {
+		if _, err := io.ReadFull(ctrl, buf[:]); err != nil {
+			return nil, fmt.Errorf("%w: read control block: %v", errCorruptPatch, err)
+		}
+		diffSize := offtin(buf[0:])
+		extraSize := offtin(buf[8:])
+		seek := offtin(buf[16:])
+		if diffSize < 0 || extraSize < 0 || diffSize > newSize-newPos || extraSize > newSize-newPos-diffSize {
+			return nil, fmt.Errorf("%w: bad control data", errCorruptPatch)
+		}
+
+		// The diff bytes are added to the old data bytes.
+		var err error
+		result, err = appendFull(result, diff, diffSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read diff block: %v", errCorruptPatch, err)
+		}
+		chunk := result[newPos:]
+		for i := range chunk {
+			if j := oldPos + int64(i); j >= 0 && j < int64(len(old)) {
+				chunk[i] += old[j]
+			}
+		}
+		newPos += diffSize
+		oldPos += diffSize
+
+		// The extra bytes are copied as is.
+		result, err = appendFull(result, extra, extraSize)
+		if err != nil {
+			return nil, fmt.Errorf("%w: read extra block: %v", errCorruptPatch, err)
+		}
+		newPos += extraSize
+		oldPos += seek
+	}
+	return result, nil
+}
+
+// appendFull appends exactly n bytes read from r to dst.
+func appendFull(dst []byte, r io.Reader, n int64) ([]byte, error) {
+	buf := bytes.NewBuffer(dst)
+	copied, err := io.CopyN(buf, r, n)
+	if err == io.EOF && copied < n {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf.Bytes(), err
+}
+
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// offtin decodes the bsdiff sign-magnitude 64-bit integer.
+func offtin(b []byte) int64 {
+	v := binary.LittleEndian.Uint64(b)
+	x := int64(v &^ (1 << 63))
+	if v&(1<<63) != 0 {
+		x = -x
+	}
+	return x
+}
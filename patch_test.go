@@ -0,0 +1,139 @@
+package resource
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// The patches are generated by a bsdiff-compatible encoder
+// for the testBSDiffOld data.
+const testBSDiffOld = "The quick brown fox jumps over the lazy dog"
+
+func TestApplyBSDiff(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+	}{
+		{
+			name:  "diff and extra",
+			patch: "42534449464634302b000000000000002e000000000000002d00000000000000425a6839314159265359e64bed0f000005d00058080008200030cd00901a41566e2ee48a70a121cc97da1e425a6839314159265359bd85e4eb0000006000c0040400100620002128d36a0c025802be2ee48a70a1217b0bc9d6425a68393141592653599110c72f000000900020002000211846c2ee48a70a12122218e5e0",
+			want:  "The quick brown cat jumps over the lazy dog!!",
+		},
+		{
+			name:  "seeks",
+			patch: "4253444946463430360000000000000027000000000000002b00000000000000425a683931415926535943959359000002704078040c800008400020002128463210030497d16406c90df87c2ee48a70a120872b26b2425a6839314159265359df14f627000000500040000100200021008283177245385090df14f627425a6839314159265359644fc6c800000000800004200021184682ee48a70a120c89f8d900",
+			want:  "jumps over the lazy dog|The quick brown fox",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			patch, err := hex.DecodeString(test.patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			have, err := applyBSDiff([]byte(testBSDiffOld), patch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(have) != test.want {
+				t.Fatalf("result mismatch:\nhave: %q\nwant: %q", have, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyBSDiffErrors(t *testing.T) {
+	header := func(ctrlLen, diffLen, newSize uint64) []byte {
+		b := make([]byte, 32)
+		copy(b, "BSDIFF40")
+		binary.LittleEndian.PutUint64(b[8:], ctrlLen)
+		binary.LittleEndian.PutUint64(b[16:], diffLen)
+		binary.LittleEndian.PutUint64(b[24:], newSize)
+		return b
+	}
+	valid, err := hex.DecodeString("42534449464634302b000000000000002e000000000000002d00000000000000425a6839314159265359e64bed0f000005d00058080008200030cd00901a41566e2ee48a70a121cc97da1e425a6839314159265359bd85e4eb0000006000c0040400100620002128d36a0c025802be2ee48a70a1217b0bc9d6425a68393141592653599110c72f000000900020002000211846c2ee48a70a12122218e5e0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The header, the control and the diff blocks, without the extra block.
+	noExtra := append([]byte(nil), valid[:32+43+46]...)
+	hugeSize := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(hugeSize[24:], 1<<40)
+	lyingSize := append([]byte(nil), valid...)
+	binary.LittleEndian.PutUint64(lyingSize[24:], 1<<29)
+
+	tests := []struct {
+		name  string
+		patch []byte
+	}{
+		{"empty", nil},
+		{"bad magic", append([]byte("BSDIFF41"), make([]byte, 24)...)},
+		{"negative length", header(1<<63|1, 0, 0)},
+		{"blocks out of bounds", header(100, 100, 10)},
+		{"lengths overflow", header(1<<62, 1<<62, 10)},
+		{"huge new size", hugeSize},
+		{"lying new size", lyingSize},
+		{"missing extra", noExtra},
+		{"not bzip2", append(header(4, 4, 10), "junkjunkjunk"...)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := applyBSDiff([]byte(testBSDiffOld), test.patch)
+			if !errors.Is(err, errCorruptPatch) {
+				t.Fatalf("have %v error, want a corrupt patch error", err)
+			}
+		})
+	}
+}
+
+func TestPatchedAssetsEviction(t *testing.T) {
+	patch, err := hex.DecodeString("42534449464634302b000000000000002e000000000000002d00000000000000425a6839314159265359e64bed0f000005d00058080008200030cd00901a41566e2ee48a70a121cc97da1e425a6839314159265359bd85e4eb0000006000c0040400100620002128d36a0c025802be2ee48a70a1217b0bc9d6425a68393141592653599110c72f000000900020002000211846c2ee48a70a12122218e5e0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	newLoader := func() *Loader {
+		l := NewLoader(nil)
+		l.OpenAssetFunc = func(path string) io.ReadCloser {
+			if path == "a.patch" {
+				return io.NopCloser(strings.NewReader(string(patch)))
+			}
+			return io.NopCloser(strings.NewReader(testBSDiffOld))
+		}
+		l.Patches = map[string]string{"a.txt": "a.patch"}
+		l.RawRegistry.Set(1, RawInfo{Path: "a.txt"})
+		return l
+	}
+
+	tests := []struct {
+		name  string
+		evict func(l *Loader)
+	}{
+		{"unload", func(l *Loader) { l.Unload(RawID(1).Ref()) }},
+		{"close", func(l *Loader) { l.Close() }},
+		{"mount", func(l *Loader) {
+			l.MountContent("dlc", fstest.MapFS{"a.txt": {Data: []byte("dlc")}})
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newLoader()
+			if have := string(l.LoadRaw(1).Data); have != "The quick brown cat jumps over the lazy dog!!" {
+				t.Fatalf("unexpected patched data: %q", have)
+			}
+			if len(l.patchedAssets) != 1 {
+				t.Fatal("the patched data is not cached")
+			}
+			test.evict(l)
+			if len(l.patchedAssets) != 0 {
+				t.Fatal("the patched data is not evicted")
+			}
+		})
+	}
+}
@@ -0,0 +1,173 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// PathNormalization is a set of asset path normalization flags.
+// See Loader.PathNormalization.
+type PathNormalization uint8
+
+const (
+	// NormalizeSlashes replaces the backslashes with slashes,
+	// so "ui\\button.png" becomes "ui/button.png".
+	NormalizeSlashes PathNormalization = 1 << iota
+
+	// NormalizeCase converts the paths to the lower case,
+	// so "UI/Button.png" becomes "ui/button.png".
+	// The asset files are expected to have the lower case names.
+	NormalizeCase
+
+	// NormalizeNFC converts the paths to the Unicode normalization form C.
+	// It makes the paths that were authored on macOS (which uses NFD)
+	// match the same file names on the other systems.
+	NormalizeNFC
+
+	// NormalizeAll enables all of the normalizations.
+	NormalizeAll = NormalizeSlashes | NormalizeCase | NormalizeNFC
+)
+
+// NormalizePath returns a normalized version of the path.
+func NormalizePath(path string, mode PathNormalization) string {
+	if mode&NormalizeSlashes != 0 {
+		path = strings.ReplaceAll(path, `\`, "/")
+	}
+	if mode&NormalizeNFC != 0 {
+		path = norm.NFC.String(path)
+	}
+	if mode&NormalizeCase != 0 {
+		path = strings.ToLower(path)
+	}
+	return path
+}
+
+func (l *Loader) normalizePath(path string) string {
+	if l.PathNormalization == 0 {
+		return path
+	}
+	return NormalizePath(path, l.PathNormalization)
+}
+
+// ValidatePaths checks whether the registered resource paths stay unambiguous
+// after the normalization: the different paths like "UI/Button.png"
+// and "ui/button.png" would be read from the same file.
+//
+// The loader PathNormalization is used for the check.
+// If it's zero, all normalizations are used (see NormalizeAll):
+// the case collisions break the game on the case-insensitive file systems
+// even if the loader doesn't normalize the paths.
+//
+// It's a debug utility, like ValidateLoop.
+// Every collision is reported via WarningFunc (if it's set) as
+// an *Error with "path" operation; the returned error is a *BatchError.
+func (l *Loader) ValidatePaths() error {
+	mode := l.PathNormalization
+	if mode == 0 {
+		mode = NormalizeAll
+	}
+	type pathOwner struct {
+		ref  Ref
+		path string
+	}
+	owners := make(map[string]pathOwner)
+	var errs []*Error
+	for _, kind := range l.allKinds() {
+		r := l.kindRegistry(kind)
+		if r == nil {
+			continue
+		}
+		ids := r.ids()
+		sort.Ints(ids)
+		for _, id := range ids {
+			ref := Ref{Kind: kind, ID: id}
+			path := r.infoPath(id)
+			if path == "" {
+				continue
+			}
+			key := NormalizePath(path, mode)
+			owner, ok := owners[key]
+			if !ok {
+				owners[key] = pathOwner{ref: ref, path: path}
+				continue
+			}
+			if owner.path == path {
+				// The same file can be used by several resources.
+				continue
+			}
+			err := fmt.Errorf("ambiguous path: it matches %q of %s with id=%d", owner.path, owner.ref.Kind, owner.ref.ID)
+			e := newError("path", ref, path, err)
+			l.warn(e)
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) != 0 {
+		return &BatchError{Errors: errs}
+	}
+	return nil
+}
+
+// SetPathVar sets a path variable value.
+// The variables are used in the resource info paths as "{name}",
+// so the quality tiers, languages and platforms can be encoded
+// right in the paths: "music/{quality}/theme.ogg", "text/{lang}/dialogues.json".
+//
+// The "{platform}" variable is predefined, it's the Loader.Platform value
+// (unless it's overridden by this method).
+// A path that refers to an undefined variable can't be loaded.
+//
+// The variables are resolved when a resource is being loaded,
+// so changing a variable doesn't affect the loaded resources;
+// unload them to make the next Load use the new value.
+func (l *Loader) SetPathVar(name, value string) {
+	if l.pathVars == nil {
+		l.pathVars = make(map[string]string)
+	}
+	l.pathVars[name] = value
+}
+
+// PathVar returns the path variable value and whether it's defined.
+// See SetPathVar.
+func (l *Loader) PathVar(name string) (string, bool) {
+	if value, ok := l.pathVars[name]; ok {
+		return value, true
+	}
+	if name == "platform" {
+		return l.Platform, true
+	}
+	return "", false
+}
+
+// expandPath replaces the "{name}" path variables with their values.
+// An unclosed "{" is not treated as a variable.
+func (l *Loader) expandPath(ref Ref, path string) string {
+	if !strings.Contains(path, "{") {
+		return path
+	}
+	var sb strings.Builder
+	rest := path
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start == -1 {
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end == -1 {
+			break
+		}
+		end += start
+		name := rest[start+1 : end]
+		value, ok := l.PathVar(name)
+		if !ok {
+			panic(newError("open", ref, path, fmt.Errorf("undefined path variable %q", name)))
+		}
+		sb.WriteString(rest[:start])
+		sb.WriteString(value)
+		rest = rest[end+1:]
+	}
+	sb.WriteString(rest)
+	return sb.String()
+}
@@ -0,0 +1,199 @@
+package resource
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// KindPlugin implements a resource kind that is defined outside of this package,
+// like a tile map or a video clip.
+//
+// A plugin kind is allocated with NewKind and bound to a loader via RegisterKind.
+// After that, its resources participate in all kind-agnostic loader operations:
+// Load, Unload, IsLoaded, Prefetch, Snapshot, LoadBatch, dependency lists, etc.
+type KindPlugin interface {
+	// Decode creates a resource from its data.
+	// The returned value is cached until the resource is unloaded.
+	//
+	// The loader can be used to load the other resources this resource needs,
+	// but it's better to list them in PluginInfo.DependsOn.
+	Decode(l *Loader, ref Ref, info PluginInfo, r io.Reader) (any, error)
+
+	// Unload is called when the resource is removed from the cache.
+	// It can release the associated data, like textures.
+	Unload(value any)
+}
+
+// PluginInfo describes a resource of the plugin kind.
+type PluginInfo struct {
+	// A path that will be used to read the resource data.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Params are the plugin-specific resource parameters.
+	Params any
+}
+
+// kindPluginBase is the first Kind value that is used for the plugin kinds.
+// It leaves enough space for the builtin kinds.
+const kindPluginBase Kind = 1 << 16
+
+var pluginKinds struct {
+	sync.Mutex
+	names []string
+}
+
+// NewKind allocates a new resource kind for a plugin (see KindPlugin).
+// The name is used as the kind String value, like "tilemap".
+//
+// The kinds are global, so it's conventional to allocate them
+// in package-level variables:
+//
+//	var KindTileMap = resource.NewKind("tilemap")
+func NewKind(name string) Kind {
+	pluginKinds.Lock()
+	defer pluginKinds.Unlock()
+	pluginKinds.names = append(pluginKinds.names, name)
+	return kindPluginBase + Kind(len(pluginKinds.names)-1)
+}
+
+func pluginKindName(k Kind) (string, bool) {
+	pluginKinds.Lock()
+	defer pluginKinds.Unlock()
+	i := int(k - kindPluginBase)
+	if i < 0 || i >= len(pluginKinds.names) {
+		return "", false
+	}
+	return pluginKinds.names[i], true
+}
+
+type pluginKind struct {
+	plugin   KindPlugin
+	registry registry[int, PluginInfo]
+	cache    map[int]any
+}
+
+// RegisterKind binds the plugin implementation to the kind
+// that was allocated by NewKind.
+// Use PluginRegistry to bind the resources of this kind.
+func (l *Loader) RegisterKind(kind Kind, p KindPlugin) {
+	if _, ok := pluginKindName(kind); !ok {
+		panic(fmt.Sprintf("register %s kind: it was not allocated by NewKind", kind))
+	}
+	if _, ok := l.plugins[kind]; ok {
+		panic(fmt.Sprintf("register %s kind: it's already registered", kind))
+	}
+	l.plugins[kind] = &pluginKind{
+		plugin:   p,
+		registry: registry[int, PluginInfo]{mapping: make(map[int]PluginInfo)},
+		cache:    make(map[int]any),
+	}
+}
+
+// PluginRegistry returns the registry of the plugin kind resources.
+// It panics if the kind is not registered (see RegisterKind).
+func (l *Loader) PluginRegistry(kind Kind) *registry[int, PluginInfo] {
+	return &l.getPluginKind(kind).registry
+}
+
+// LoadPlugin returns a plugin kind resource associated with a given ref.
+// Only a first call for this ref will lead to resource decoding,
+// all next calls return the cached result.
+func (l *Loader) LoadPlugin(ref Ref) any {
+	k := l.getPluginKind(ref.Kind)
+	ref.ID = k.registry.resolve(ref.ID)
+	value, ok := k.cache[ref.ID]
+	if !ok {
+		info, ok := k.registry.mapping[ref.ID]
+		if !ok {
+			panic(l.notRegisteredError(ref))
+		}
+		info = applyVariant(l, ref, info)
+		info.Path = l.platformPath(info.Path, info.PathByPlatform)
+		r := l.openResource(ref, info.Path, info.Data)
+		defer func() {
+			if err := r.Close(); err != nil {
+				panic(newError("read", ref, info.Path, err))
+			}
+		}()
+		done := l.profileStart(ref, info.Path, stageDecode)
+		v, err := k.plugin.Decode(l, ref, info, r)
+		done()
+		if err != nil {
+			panic(l.decodeError("decode", ref, info.Path, info.Data, err))
+		}
+		value = v
+		l.loadDependencies(ref, info.DependsOn)
+		k.cache[ref.ID] = value
+		l.emit(EventLoad, ref)
+	}
+	return value
+}
+
+// GetPluginInfo extracts the plugin kind resource info associated with a given ref.
+func (l *Loader) GetPluginInfo(ref Ref) PluginInfo {
+	k := l.getPluginKind(ref.Kind)
+	return k.registry.mapping[k.registry.resolve(ref.ID)]
+}
+
+func (l *Loader) getPluginKind(kind Kind) *pluginKind {
+	k, ok := l.plugins[kind]
+	if !ok {
+		panic(fmt.Sprintf("%s kind is not registered", kind))
+	}
+	return k
+}
+
+// allKinds returns the builtin and the registered plugin kinds in ascending order.
+func (l *Loader) allKinds() []Kind {
+	kinds := make([]Kind, 0, int(numBuiltinKinds)+len(l.plugins))
+	for kind := Kind(0); kind < numBuiltinKinds; kind++ {
+		kinds = append(kinds, kind)
+	}
+	return append(kinds, l.pluginKindsOrdered()...)
+}
+
+// pluginKindsOrdered returns the registered plugin kinds in ascending order.
+func (l *Loader) pluginKindsOrdered() []Kind {
+	kinds := make([]Kind, 0, len(l.plugins))
+	for kind := range l.plugins {
+		kinds = append(kinds, kind)
+	}
+	sort.Slice(kinds, func(i, j int) bool {
+		return kinds[i] < kinds[j]
+	})
+	return kinds
+}
+
+func (l *Loader) unloadPluginResource(ref Ref) bool {
+	k, ok := l.plugins[ref.Kind]
+	if !ok {
+		return false
+	}
+	value, ok := k.cache[ref.ID]
+	if !ok {
+		return false
+	}
+	k.plugin.Unload(value)
+	delete(k.cache, ref.ID)
+	return true
+}
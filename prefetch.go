@@ -0,0 +1,285 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Priority is a queued load request priority.
+// See Loader.Enqueue.
+type Priority int
+
+const (
+	// PriorityIdle is used for the speculative loading (see Prefetch).
+	PriorityIdle Priority = iota
+
+	// PriorityNormal resources are loaded before the idle ones.
+	PriorityNormal
+
+	// PriorityCritical resources are loaded during the next
+	// PrefetchStep call, even if it exceeds the time budget.
+	PriorityCritical
+
+	numPriorities
+)
+
+// Prefetch queues the resources for a background loading.
+// It's a hint that these resources are likely to be needed soon
+// (next level, next dialog portrait, etc.)
+//
+// The queued resources are loaded by PrefetchStep calls,
+// a few at a time, so the loading is spread across the frames.
+// A foreground Load call for a queued resource loads it right away;
+// the queue skips the resources that are already loaded.
+//
+// Prefetch is identical to Enqueue with PriorityIdle.
+func (l *Loader) Prefetch(refs ...Ref) {
+	l.Enqueue(PriorityIdle, refs...)
+}
+
+// Enqueue adds the resources to the load queue with a given priority.
+// The higher priority resources are loaded first;
+// the resources with equal priorities are loaded in the queue order.
+//
+// See Prefetch and PrefetchStep.
+func (l *Loader) Enqueue(priority Priority, refs ...Ref) {
+	if priority < 0 || priority >= numPriorities {
+		panic(fmt.Sprintf("invalid load priority %d", priority))
+	}
+	l.loadQueue[priority] = append(l.loadQueue[priority], refs...)
+}
+
+// PreloadFromTrace loads the resources in the order they were
+// recorded in the trace (see UsageTracker.Trace).
+//
+// Recording a playthrough once and preloading its trace at startup
+// warms the caches in the order the game is going to need them.
+// Store the trace along with the game data (or in the user config dir)
+// to make the scene entry near-instant on subsequent runs.
+//
+// The trace could be recorded by a different game build,
+// so the resources are found by their paths (see LoadTrace)
+// and the resources that are not registered anymore are skipped.
+// The load errors are reported in the same way as LoadBatch does.
+func (l *Loader) PreloadFromTrace(trace LoadTrace) error {
+	return l.LoadBatch(l.traceRefs(trace))
+}
+
+// PreloadEager loads all resources that are marked as Eager
+// in their infos (see the package documentation).
+//
+// It's intended to be called once at startup, after all resources
+// are registered, so the "must be instant" assets are decoded
+// before the game starts while everything else stays lazy.
+// The resources are loaded in their kind and ID order.
+// The load errors are reported in the same way as LoadBatch does.
+func (l *Loader) PreloadEager() error {
+	return l.LoadBatch(l.filterRefs(anyRegistry.isEager))
+}
+
+// Tier is a progressive loading tier.
+// The lower tiers are loaded first.
+//
+// See Loader.PreloadTier and Loader.StreamTier.
+type Tier int
+
+const (
+	// TierEssential resources are required to start the game.
+	TierEssential Tier = iota + 1
+
+	// TierStandard resources are the regular game assets.
+	TierStandard
+
+	// TierHighRes resources are the optional high quality assets.
+	TierHighRes
+)
+
+// PreloadTier loads all resources of the given tier
+// (see the Tier field of the resource info types).
+//
+// It's mostly useful for the web builds where the assets are downloaded:
+// load the TierEssential resources before the game starts
+// and StreamTier the rest, so the game becomes playable sooner.
+// The load errors are reported in the same way as LoadBatch does.
+func (l *Loader) PreloadTier(tier Tier) error {
+	return l.LoadBatch(l.tierRefs(tier))
+}
+
+// StreamTier queues all resources of the given tier for a background loading.
+// They're loaded by the PrefetchStep calls.
+//
+// The tier resources are queued with PriorityIdle,
+// so the foreground Enqueue requests are served first.
+func (l *Loader) StreamTier(tier Tier) {
+	l.Prefetch(l.tierRefs(tier)...)
+}
+
+// TierProgress reports how many resources of the given tier are loaded.
+// It can be used to render a loading indicator.
+func (l *Loader) TierProgress(tier Tier) (loaded, total int) {
+	refs := l.tierRefs(tier)
+	for _, ref := range refs {
+		if l.IsLoaded(ref) {
+			loaded++
+		}
+	}
+	return loaded, len(refs)
+}
+
+// EstimateBytes returns the total data size of the given resources
+// that are not loaded yet, so the web builds can show the download
+// size before the loading starts:
+//
+//	refs := l.TierRefs(resource.TierStandard)
+//	fmt.Printf("Downloading %.1f MB...", float64(l.EstimateBytes(refs))/(1024*1024))
+//
+// The sizes are taken from the info FileSize fields
+// or from the Data lengths for the in-memory resources.
+// The resources without a known size are counted as zero bytes,
+// every resource is counted only once.
+func (l *Loader) EstimateBytes(refs []Ref) int64 {
+	var total int64
+	seen := make(map[Ref]struct{}, len(refs))
+	for _, ref := range refs {
+		ref = l.resolveRef(ref)
+		if _, ok := seen[ref]; ok {
+			continue
+		}
+		seen[ref] = struct{}{}
+		if l.IsLoaded(ref) {
+			continue
+		}
+		if r := l.kindRegistry(ref.Kind); r != nil {
+			total += r.infoSize(ref.ID)
+		}
+	}
+	return total
+}
+
+// TierRefs returns all resources of the given tier
+// in their kind and ID order.
+func (l *Loader) TierRefs(tier Tier) []Ref {
+	return l.tierRefs(tier)
+}
+
+func (l *Loader) tierRefs(tier Tier) []Ref {
+	return l.filterRefs(func(r anyRegistry, id int) bool {
+		return r.tier(id) == tier
+	})
+}
+
+// filterRefs returns the registered resources that satisfy the predicate
+// in their kind and ID order.
+func (l *Loader) filterRefs(pred func(r anyRegistry, id int) bool) []Ref {
+	var refs []Ref
+	for _, kind := range l.allKinds() {
+		r := l.kindRegistry(kind)
+		ids := r.ids()
+		sort.Ints(ids)
+		for _, id := range ids {
+			if pred(r, id) {
+				refs = append(refs, Ref{Kind: kind, ID: id})
+			}
+		}
+	}
+	return refs
+}
+
+// WhenLoaded returns a channel that is closed when the resource is loaded.
+// If the resource is already loaded, the returned channel is closed.
+//
+// It makes it possible to swap the placeholders for the real
+// resources reactively, without polling IsLoaded:
+//
+//	l.Prefetch(ImagePortrait.Ref())
+//	select {
+//	case <-l.WhenLoaded(ImagePortrait.Ref()):
+//		// Use the real portrait.
+//	default:
+//		// Use a placeholder.
+//	}
+//
+// The notifications are delivered during the PrefetchStep calls,
+// this includes the resources loaded by a foreground Load.
+func (l *Loader) WhenLoaded(ref Ref) <-chan struct{} {
+	ref = l.resolveRef(ref)
+	ch := make(chan struct{})
+	if l.IsLoaded(ref) {
+		close(ch)
+		return ch
+	}
+	if l.loadWaiters == nil {
+		l.loadWaiters = make(map[Ref][]chan struct{})
+	}
+	l.loadWaiters[ref] = append(l.loadWaiters[ref], ch)
+	return ch
+}
+
+func (l *Loader) notifyLoaded() {
+	for ref, waiters := range l.loadWaiters {
+		if !l.IsLoaded(ref) {
+			continue
+		}
+		for _, ch := range waiters {
+			close(ch)
+		}
+		delete(l.loadWaiters, ref)
+	}
+}
+
+// PrefetchStep loads the queued resources (see Prefetch) until the
+// time budget is exhausted. At least one resource is loaded per call
+// if the queue is not empty.
+// All PriorityCritical resources are loaded regardless of the budget.
+//
+// It's intended to be called once per frame, from the game Update method:
+//
+//	l.PrefetchStep(2 * time.Millisecond)
+//
+// It reports whether there are any resources left in the queue.
+func (l *Loader) PrefetchStep(budget time.Duration) bool {
+	defer l.notifyLoaded()
+	start := time.Now()
+	loaded := false
+	for {
+		ref, priority, ok := l.popQueued()
+		if !ok {
+			return false
+		}
+		if l.IsLoaded(ref) {
+			continue
+		}
+		if loaded && priority != PriorityCritical && time.Since(start) >= budget {
+			// Put it back, it will be loaded during the next step.
+			l.loadQueue[priority] = append([]Ref{ref}, l.loadQueue[priority]...)
+			break
+		}
+		l.Load(ref)
+		loaded = true
+	}
+	for _, q := range l.loadQueue {
+		if len(q) != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Loader) popQueued() (Ref, Priority, bool) {
+	for p := numPriorities - 1; p >= 0; p-- {
+		q := l.loadQueue[p]
+		if len(q) == 0 {
+			continue
+		}
+		ref := q[0]
+		if len(q) == 1 {
+			// Release the underlying array.
+			l.loadQueue[p] = nil
+		} else {
+			l.loadQueue[p] = q[1:]
+		}
+		return ref, p, true
+	}
+	return Ref{}, 0, false
+}
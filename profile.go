@@ -0,0 +1,140 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// Profiler records the loading durations per resource.
+// Assign it to the Loader.Profiler field to enable the profiling.
+//
+// The durations are split into the stages:
+//
+//   - Open: opening the asset (including the checksum verification read)
+//   - Decode: decoding the data (images, audio, fonts; shader compilation)
+//   - Upload: creating the GPU textures (images)
+//
+// The other resource kinds only have their Open stage recorded.
+type Profiler struct {
+	entries map[Ref]*ProfileEntry
+}
+
+// ProfileEntry is a single resource loading profile.
+// Multiple loads of the same resource (after an unload) are summed up.
+type ProfileEntry struct {
+	Kind Kind   `json:"kind"`
+	ID   int    `json:"id"`
+	Path string `json:"path"`
+
+	Open   time.Duration `json:"open"`
+	Decode time.Duration `json:"decode"`
+	Upload time.Duration `json:"upload"`
+}
+
+// Total returns the sum of all stage durations.
+func (e ProfileEntry) Total() time.Duration {
+	return e.Open + e.Decode + e.Upload
+}
+
+type profileStage int
+
+const (
+	stageOpen profileStage = iota
+	stageDecode
+	stageUpload
+)
+
+// NewProfiler creates an empty profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{entries: make(map[Ref]*ProfileEntry)}
+}
+
+// Reset removes all recorded entries.
+func (p *Profiler) Reset() {
+	p.entries = make(map[Ref]*ProfileEntry)
+}
+
+// Entries returns the recorded profiles sorted by their total
+// duration, the slowest resources go first.
+func (p *Profiler) Entries() []ProfileEntry {
+	entries := make([]ProfileEntry, 0, len(p.entries))
+	for _, e := range p.entries {
+		entries = append(entries, *e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		ti := entries[i].Total()
+		tj := entries[j].Total()
+		if ti != tj {
+			return ti > tj
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return entries[i].Kind < entries[j].Kind
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	return entries
+}
+
+// WriteReport writes a human-readable report table to w.
+func (p *Profiler) WriteReport(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "total\topen\tdecode\tupload\tkind\tid\tpath")
+	for _, e := range p.Entries() {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%s\t%d\t%s\n",
+			e.Total(), e.Open, e.Decode, e.Upload, e.Kind, e.ID, e.Path)
+	}
+	return tw.Flush()
+}
+
+// WriteJSON writes the report in the JSON format to w.
+// The durations are encoded as integer nanoseconds.
+func (p *Profiler) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p.Entries())
+}
+
+func (p *Profiler) record(ref Ref, path string, stage profileStage, d time.Duration) {
+	e := p.entries[ref]
+	if e == nil {
+		e = &ProfileEntry{Kind: ref.Kind, ID: ref.ID}
+		p.entries[ref] = e
+	}
+	if path != "" {
+		e.Path = path
+	}
+	switch stage {
+	case stageOpen:
+		e.Open += d
+	case stageDecode:
+		e.Decode += d
+	case stageUpload:
+		e.Upload += d
+	}
+}
+
+// profileStart returns a function that records the stage duration
+// when it's called. It also starts the stage tracing span (see Tracer).
+// It's a no-op if both profiling and tracing are disabled.
+//
+//	defer l.profileStart(ref, path, stageDecode)()
+func (l *Loader) profileStart(ref Ref, path string, stage profileStage) func() {
+	if l.Profiler == nil && l.Tracer == nil {
+		return func() {}
+	}
+	var endSpan func()
+	if l.Tracer != nil {
+		endSpan = l.Tracer.StartSpan(stage.spanName(), ref, path)
+	}
+	start := time.Now()
+	return func() {
+		if l.Profiler != nil {
+			l.Profiler.record(ref, path, stage, time.Since(start))
+		}
+		if endSpan != nil {
+			endSpan()
+		}
+	}
+}
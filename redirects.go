@@ -0,0 +1,37 @@
+package resource
+
+import "fmt"
+
+// maxRedirects is a max length of the PathRedirects chain.
+const maxRedirects = 16
+
+// redirectPath follows the PathRedirects for the path.
+// Every redirected path is reported as deprecated once,
+// via the WarningFunc and the EventDeprecated event.
+func (l *Loader) redirectPath(ref Ref, path string) string {
+	if len(l.PathRedirects) == 0 {
+		return path
+	}
+	original := path
+	for i := 0; ; i++ {
+		next, ok := l.PathRedirects[path]
+		if !ok {
+			break
+		}
+		if i == maxRedirects {
+			panic(fmt.Sprintf("too many path redirects for %q (a redirect cycle?)", original))
+		}
+		path = next
+	}
+	if path != original {
+		if l.warnedRedirects == nil {
+			l.warnedRedirects = make(map[string]struct{})
+		}
+		if _, ok := l.warnedRedirects[original]; !ok {
+			l.warnedRedirects[original] = struct{}{}
+			l.warn(fmt.Errorf("path %q is deprecated, use %q instead", original, path))
+			l.emitDeprecated(ref, original, path)
+		}
+	}
+	return path
+}
@@ -1,5 +1,11 @@
 package resource
 
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
 // registry is a resource metadata association index.
 //
 // Right now it's implemented as a map, but it could become a
@@ -8,9 +14,17 @@ package resource
 // to store them inside a slice storage.
 //
 // We use an opaque type here to make it an implementation detail.
-// The users have only Set and Assign operations.
+// The users have only Set, Assign and Alloc-like operations.
 type registry[IDType ~int, InfoType any] struct {
 	mapping map[IDType]InfoType
+
+	// aliases maps an alias ID to its target ID.
+	// An alias ID has no bound metadata of its own.
+	aliases map[IDType]IDType
+
+	// nextID is an ID that would be returned by the next Alloc call.
+	// It's always greater than any ID that was bound or allocated before.
+	nextID IDType
 }
 
 // Set binds the typed resource ID to its metadata.
@@ -19,13 +33,16 @@ type registry[IDType ~int, InfoType any] struct {
 //
 // If id was unbound before, it will be bound.
 // If id was bound before, its metadata will be replaced.
+// If id was an alias, it stops being an alias (the alias is overridden).
 //
-// The typed ID could be of type:
-// AudioID, FontID, ImageID, RawID, ShaderID.
-// The metadata should have a respective type too:
-// AudioInfo, FontInfo, ImageInfo, RawInfo, ShaderInfo.
+// The typed ID could be any of the *ID types (like ImageID or TableID),
+// the metadata should have the matching *Info type (like ImageInfo or TableInfo).
 func (r *registry[IDType, InfoType]) Set(id IDType, info InfoType) {
 	r.mapping[id] = info
+	delete(r.aliases, id)
+	if id >= r.nextID {
+		r.nextID = id + 1
+	}
 }
 
 // Assign is a convenience wrapper over Set to bind multiple key-value
@@ -35,3 +52,191 @@ func (r *registry[IDType, InfoType]) Assign(m map[IDType]InfoType) {
 		r.Set(k, v)
 	}
 }
+
+// Alias makes id resolve to the target resource.
+// Both the metadata and the loaded resource cache entry are shared
+// with the target, so loading the alias doesn't decode anything twice.
+//
+// The alias remains in effect until it's overridden by a Set call
+// for that id. This makes it possible to use a placeholder resource
+// until the final one is provided (or a skin overrides it).
+//
+// Aliases can be chained; a cyclic alias causes a panic.
+func (r *registry[IDType, InfoType]) Alias(id, target IDType) {
+	if r.resolve(target) == id {
+		panic(fmt.Sprintf("cyclic alias %d->%d", id, target))
+	}
+	if r.aliases == nil {
+		r.aliases = make(map[IDType]IDType)
+	}
+	delete(r.mapping, id)
+	r.aliases[id] = target
+	if id >= r.nextID {
+		r.nextID = id + 1
+	}
+}
+
+// Alloc returns a new ID that is not used by any bound resource.
+// It's useful for the resources that are discovered during the run time
+// (mods, procedural content, etc.) to avoid collisions with the
+// iota-style constants of the game.
+//
+// The returned ID is not bound to anything,
+// use Set to bind it to the resource metadata.
+//
+// Note that binding an ID that is greater than the allocated ones
+// via Set shifts the allocation space, so the IDs are never reused.
+func (r *registry[IDType, InfoType]) Alloc() IDType {
+	return r.AllocRange(1)
+}
+
+// AllocRange reserves n consecutive unused IDs and returns the first one.
+// The reserved range is [id, id+n).
+//
+// This is useful for plugins (and mods) that want to have their own
+// ID namespace: they can allocate a range once and then use
+// the offsets relative to the returned ID.
+func (r *registry[IDType, InfoType]) AllocRange(n int) IDType {
+	if n <= 0 {
+		panic(fmt.Sprintf("invalid alloc range size %d", n))
+	}
+	if r.nextID == 0 {
+		// Zero ID is conventionally used as a "none" resource ID.
+		r.nextID = 1
+	}
+	id := r.nextID
+	r.nextID += IDType(n)
+	return id
+}
+
+// resolve follows the alias chain (if any) and returns the
+// ID that holds the actual resource metadata.
+func (r *registry[IDType, InfoType]) resolve(id IDType) IDType {
+	for {
+		target, ok := r.aliases[id]
+		if !ok {
+			return id
+		}
+		id = target
+	}
+}
+
+// OrderedIDs returns all bound IDs in ascending order.
+// Aliases are not included.
+//
+// Use it instead of iterating over the registrations in an
+// arbitrary order, so the operations like "preload everything"
+// run in a stable and reproducible order:
+//
+//	for _, id := range l.ImageRegistry.OrderedIDs() {
+//		l.LoadImage(id)
+//	}
+func (r *registry[IDType, InfoType]) OrderedIDs() []IDType {
+	ids := make([]IDType, 0, len(r.mapping))
+	for id := range r.mapping {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// anyRegistry is a kind-agnostic registry interface.
+type anyRegistry interface {
+	ids() []int
+	has(id int) bool
+	infoPath(id int) string
+	isEager(id int) bool
+	tier(id int) Tier
+	infoSize(id int) int64
+}
+
+// ids returns all bound IDs (aliases are not included).
+func (r *registry[IDType, InfoType]) ids() []int {
+	ids := make([]int, 0, len(r.mapping))
+	for id := range r.mapping {
+		ids = append(ids, int(id))
+	}
+	return ids
+}
+
+// has reports whether id (or its alias target) has a bound info.
+func (r *registry[IDType, InfoType]) has(id int) bool {
+	_, ok := r.mapping[r.resolve(IDType(id))]
+	return ok
+}
+
+// infoPath returns the Path field value of the info bound to id.
+// It returns an empty string if there is no such info or field.
+func (r *registry[IDType, InfoType]) infoPath(id int) string {
+	path := r.infoField(id, "Path")
+	if !path.IsValid() || path.Kind() != reflect.String {
+		return ""
+	}
+	return path.String()
+}
+
+// isEager returns the Eager field value of the info bound to id.
+func (r *registry[IDType, InfoType]) isEager(id int) bool {
+	eager := r.infoField(id, "Eager")
+	return eager.IsValid() && eager.Kind() == reflect.Bool && eager.Bool()
+}
+
+// tier returns the Tier field value of the info bound to id.
+func (r *registry[IDType, InfoType]) tier(id int) Tier {
+	tier := r.infoField(id, "Tier")
+	if !tier.IsValid() || tier.Kind() != reflect.Int {
+		return 0
+	}
+	return Tier(tier.Int())
+}
+
+// infoSize returns the FileSize field value of the info bound to id.
+// If the size is not set, the Data field length is used.
+func (r *registry[IDType, InfoType]) infoSize(id int) int64 {
+	size := r.infoField(id, "FileSize")
+	if size.IsValid() && size.Kind() == reflect.Int64 && size.Int() != 0 {
+		return size.Int()
+	}
+	data := r.infoField(id, "Data")
+	if data.IsValid() && data.Kind() == reflect.Slice {
+		return int64(data.Len())
+	}
+	return 0
+}
+
+// infoField returns the named field of the info bound to id.
+// It returns an invalid value if there is no such info or field.
+func (r *registry[IDType, InfoType]) infoField(id int, name string) reflect.Value {
+	info, ok := r.mapping[r.resolve(IDType(id))]
+	if !ok {
+		return reflect.Value{}
+	}
+	v := reflect.ValueOf(info)
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+	return v.FieldByName(name)
+}
+
+// clone returns a deep copy of the registry index.
+// The infos themselves are copied shallowly.
+func (r *registry[IDType, InfoType]) clone() registry[IDType, InfoType] {
+	return registry[IDType, InfoType]{
+		mapping: cloneMap(r.mapping),
+		aliases: cloneMap(r.aliases),
+		nextID:  r.nextID,
+	}
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return nil
+	}
+	result := make(map[K]V, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
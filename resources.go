@@ -1,21 +1,159 @@
 package resource
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
 	"io"
+	"reflect"
+	"text/template"
+	"time"
+	"unicode"
 
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/audio"
 	"golang.org/x/image/font"
 )
 
+// Kind is a resource kind identifier.
+type Kind int
+
+const (
+	KindAudio Kind = iota
+	KindFont
+	KindImage
+	KindRaw
+	KindShader
+	KindPalette
+	KindMaterial
+	KindSDFFont
+	KindTable
+	KindConfig
+	KindTemplate
+	KindScript
+	KindParticleDef
+	KindHitboxes
+	KindTileSet
+	KindComposite
+	KindShaderImage
+	KindLUT
+	KindGradient
+	KindCurve
+	KindInputProfile
+	KindDefinition
+	KindDocument
+	KindIconSet
+	KindTheme
+	KindVariantSet
+
+	// numBuiltinKinds is the number of kinds defined in this package.
+	numBuiltinKinds
+)
+
+// String returns a kind name, like "image" or "audio".
+func (k Kind) String() string {
+	switch k {
+	case KindAudio:
+		return "audio"
+	case KindFont:
+		return "font"
+	case KindImage:
+		return "image"
+	case KindRaw:
+		return "raw"
+	case KindShader:
+		return "shader"
+	case KindPalette:
+		return "palette"
+	case KindMaterial:
+		return "material"
+	case KindSDFFont:
+		return "sdf font"
+	case KindTable:
+		return "table"
+	case KindConfig:
+		return "config"
+	case KindTemplate:
+		return "text template"
+	case KindScript:
+		return "script"
+	case KindParticleDef:
+		return "particle def"
+	case KindHitboxes:
+		return "hitboxes"
+	case KindTileSet:
+		return "tile set"
+	case KindComposite:
+		return "composite image"
+	case KindShaderImage:
+		return "shader image"
+	case KindLUT:
+		return "color lut"
+	case KindGradient:
+		return "gradient"
+	case KindCurve:
+		return "curve"
+	case KindInputProfile:
+		return "input profile"
+	case KindDefinition:
+		return "definition"
+	case KindDocument:
+		return "document"
+	case KindIconSet:
+		return "icon set"
+	case KindTheme:
+		return "theme"
+	case KindVariantSet:
+		return "variant set"
+	default:
+		if name, ok := pluginKindName(k); ok {
+			return name
+		}
+		return "unknown"
+	}
+}
+
+// Ref is a kind-tagged resource ID.
+// It's useful when resources of different kinds need to be
+// referenced uniformly, like in resource dependency lists.
+//
+// Use typed ID Ref method to create it, like ImageID.Ref().
+type Ref struct {
+	Kind Kind
+	ID   int
+}
+
 // AudioID is a typed key for Audio resources.
 // See also: AudioInfo.
 type AudioID int
 
+// Ref returns a kind-tagged reference to this resource.
+func (id AudioID) Ref() Ref { return Ref{Kind: KindAudio, ID: int(id)} }
+
 type AudioInfo struct {
 	// A path that will be used to read the resource data.
 	Path string
 
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
 	// Group is a sound group ID.
 	// Groups are used to apply group-wide operations like
 	// volume adjustments.
@@ -52,6 +190,11 @@ type AudioInfo struct {
 	// beneficial to add a NopDecorator decorator that would return the input stream as is.
 	// This will make WAV more expensive to play in terms of CPU clocks.
 	StreamDecorator func(stream io.ReadSeeker) io.ReadSeeker
+
+	// BeatmapPath is an optional path to the beatmap sidecar file.
+	// The beatmap is loaded along with the audio resource,
+	// see Beatmap for the file format.
+	BeatmapPath string
 }
 
 type Audio struct {
@@ -61,25 +204,203 @@ type Audio struct {
 	// An initialized audio player that can be used to play the audio.
 	// Note that you may need to rewind it before playing the sound.
 	// The player wraps an original stream, so you can't access it directly.
+	// Use Bytes or NewStream to get the decoded audio data.
 	Player *audio.Player
 
 	Group  uint
 	Volume float64
+
+	// Duration is the audio length.
+	// It's zero if the length is unknown: the underlying stream
+	// has no Length method (like some custom loader streams).
+	// See StreamDuration.
+	Duration time.Duration
+
+	// Beatmap is a timing data loaded from the AudioInfo.BeatmapPath sidecar.
+	// It's nil if the audio has no beatmap.
+	// Unloading the audio resource drops it, so the next Load re-reads the file.
+	Beatmap *Beatmap
+
+	// data is a decoded in-memory WAV data.
+	// It's nil for the streamed audio resources.
+	data []byte
+
+	loader *Loader
+}
+
+// Bytes returns the decoded audio data for the in-memory sounds.
+// The data is in the Ebitengine PCM format: 16-bit little endian, 2 channels.
+//
+// It returns nil for the streamed audio resources, use NewStream for them.
+// The returned slice is shared with the players, do not modify it.
+func (a Audio) Bytes() []byte { return a.data }
+
+// NewStream creates a new independent decoded stream for this audio resource.
+// The stream is in the same format as Bytes data.
+//
+// The stream decorator is not applied to the result,
+// so it can be used for things like waveform visualization
+// or beat analysis without affecting the playback.
+//
+// For the in-memory sounds, this stream reads the Bytes data.
+// Other resources are decoded again on every call.
+func (a Audio) NewStream() io.ReadSeeker {
+	if a.data != nil {
+		return bytes.NewReader(a.data)
+	}
+	if a.loader == nil {
+		panic(fmt.Sprintf("audio %d was not created by a loader", a.ID))
+	}
+	return a.loader.decodeAudioStream(a.ID, a.loader.getAudioInfo(a.ID))
+}
+
+// ConfigID is a typed key for Config resources.
+// See also: ConfigInfo.
+type ConfigID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ConfigID) Ref() Ref { return Ref{Kind: KindConfig, ID: int(id)} }
+
+// ConfigInfo describes a configuration file resource,
+// like a game settings or balance values file.
+// Its decoded values are accessed with DecodeConfig.
+type ConfigInfo struct {
+	// A path that will be used to read the resource data.
+	//
+	// The path extension selects the config format decoder,
+	// see Loader.ConfigDecoders.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Soft makes this resource soft-cached: it can be unloaded
+	// automatically when the Loader.SoftCacheLimit is exceeded
+	// or when Loader.DropSoftCache is called.
+	// The next Load reads it again transparently.
+	//
+	// This is useful for the data that is needed only briefly,
+	// like level generation inputs.
+	Soft bool
+}
+
+// Config is a loaded configuration file.
+// Use DecodeConfig function to get its decoded value.
+type Config struct {
+	// An ID that was associated with this resource.
+	ID ConfigID
+
+	// Data is an undecoded config file contents.
+	Data []byte
+
+	// path is the resolved config path, its extension selects the decoder.
+	path string
+
+	// values are the decoded config values, keyed by their type.
+	values map[reflect.Type]any
 }
 
 // FontID is a typed key for Font resources.
 // See also: FontInfo.
 type FontID int
 
+// Ref returns a kind-tagged reference to this resource.
+func (id FontID) Ref() Ref { return Ref{Kind: KindFont, ID: int(id)} }
+
+// FontInfo describes a TTF or OTF font resource.
+//
+// Variable fonts are rendered as their default instance: the
+// golang.org/x/image/font/opentype rasterizer doesn't apply the variation
+// axes (like weight or width). The requested Instance and Axes are
+// validated, but to get the other weights rendered, export the static
+// instances (like Regular and Bold) and register them as separate resources.
 type FontInfo struct {
 	// A path that will be used to read the resource data.
 	Path string
 
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
 	Size int
 
 	LineSpacing float64
+
+	// PreloadGlyphs is a set of runes that are rendered into the
+	// text glyph cache right after the font is loaded.
+	// This eliminates the first-use text rendering hitches
+	// (especially on the web platform).
+	//
+	// Use ASCIIGlyphs to pre-warm all printable ASCII characters.
+	PreloadGlyphs string
+
+	// PreloadGlyphsFrom works like PreloadGlyphs, but the runes
+	// are taken from the Raw resources (like translation strings files).
+	PreloadGlyphsFrom []RawID
+
+	// GlyphFilter hides the glyphs outside of the specified unicode ranges.
+	// The filtered glyphs are reported as missing by the face,
+	// so they're never rasterized and cached.
+	// This keeps the glyph caches small for huge (e.g. CJK) fonts
+	// in games that only need a known set of strings.
+	//
+	// This is a glyph filtering, not a font subsetting:
+	// the font file data is still parsed and kept in memory as a whole.
+	//
+	// An empty slice means "no filtering".
+	GlyphFilter []*unicode.RangeTable
+
+	// Instance selects a named instance of the variable font,
+	// like "Bold" or "Condensed Black" (see the OpenType fvar table).
+	Instance string
+
+	// Axes are the variable font axis values by their tags,
+	// like {"wght": 700, "wdth": 75}.
+	// They override the Instance coordinates.
+	//
+	// The Instance and Axes are validated against the font fvar table:
+	// a non-variable font, an unknown instance or axis
+	// and an out-of-range value are reported as the load errors.
+	//
+	// Note that the glyph outlines are not interpolated yet:
+	// a variable font is always rendered with its default instance
+	// and the ignored variation is reported via Loader.WarningFunc.
+	Axes map[string]float64
 }
 
+// ASCIIGlyphs contains all printable ASCII characters.
+// It can be used as a FontInfo.PreloadGlyphs value.
+const ASCIIGlyphs = " !\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~"
+
 type Font struct {
 	// An ID that was associated with this resource.
 	ID FontID
@@ -87,36 +408,395 @@ type Font struct {
 	Face font.Face
 }
 
+// SDFFontID is a typed key for SDFFont resources.
+// See also: SDFFontInfo.
+type SDFFontID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id SDFFontID) Ref() Ref { return Ref{Kind: KindSDFFont, ID: int(id)} }
+
+type SDFFontInfo struct {
+	// A path that will be used to read the resource data (a TTF or OTF font).
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Size is a font size that is used to rasterize the glyphs.
+	// It's interpreted the same way as the FontInfo.Size.
+	// SDF glyphs can be scaled well, so it's usually something like 32 or 48.
+	Size int
+
+	// Spread is a max distance (in pixels) encoded by the distance field.
+	// Larger spread values allow thicker outlines and glows.
+	// A zero value means "Size/8".
+	Spread int
+
+	// Glyphs is a set of runes that are put into the atlas.
+	// An empty string means ASCIIGlyphs.
+	Glyphs string
+}
+
+// SDFFont is a signed distance field font atlas.
+//
+// Every atlas pixel alpha encodes a distance to the glyph edge:
+// 0.5 is the edge itself, greater values are inside the glyph.
+// It's intended to be rendered with a shader that can scale
+// and outline the text.
+type SDFFont struct {
+	// An ID that was associated with this resource.
+	ID SDFFontID
+
+	// Atlas is an image that contains all glyphs.
+	Atlas *ebiten.Image
+
+	// Glyphs maps a rune to its atlas location and metrics.
+	Glyphs map[rune]SDFGlyph
+
+	// Spread is a distance (in pixels) that is encoded by the distance field.
+	Spread int
+
+	// Ascent, Descent and LineHeight are the font metrics (in pixels)
+	// for the SDFFontInfo.Size.
+	Ascent     float64
+	Descent    float64
+	LineHeight float64
+}
+
+// SDFGlyph describes a single SDFFont glyph.
+type SDFGlyph struct {
+	// Rect is a glyph location inside the atlas.
+	// It includes the spread padding.
+	Rect image.Rectangle
+
+	// Offset is a glyph image top-left corner position relative to the dot
+	// (the baseline origin).
+	Offset image.Point
+
+	// Advance is a distance (in pixels) to the next glyph dot.
+	Advance float64
+}
+
 // ImageID is a typed key for Image resources.
 // See also: ImageInfo.
 type ImageID int
 
+// Ref returns a kind-tagged reference to this resource.
+func (id ImageID) Ref() Ref { return Ref{Kind: KindImage, ID: int(id)} }
+
 type ImageInfo struct {
 	// A path that will be used to read the resource data.
 	Path string
 
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
 	FrameWidth  int
 	FrameHeight int
+
+	// SplitFrames makes LoadImage split the sprite sheet into independent
+	// frame images right away, see Loader.LoadImageFrame.
+	// The frame grid is defined by FrameWidth and FrameHeight.
+	SplitFrames bool
+
+	// SourceRect selects a region of the decoded image that is used
+	// as this resource; an empty rectangle means "the entire image".
+	// The rectangle coordinates are relative to the image origin.
+	//
+	// This makes it possible to register several IDs that are slices of
+	// one huge image (like a scanned background) without uploading
+	// the entire image as a texture.
+	// Note that every such resource decodes the file separately,
+	// unless a Loader.SharedCache is used.
+	SourceRect image.Rectangle
+
+	// Alpha describes how the stored color values relate to the alpha channel.
+	// The default AlphaAuto trusts the image decoder.
+	//
+	// Some tools export the premultiplied colors into the formats
+	// that are expected to have the straight alpha (like PNG).
+	// Such images would have the dark fringes around the semi-transparent
+	// pixels, use AlphaPremultiplied for them.
+	Alpha AlphaMode
+
+	// ColorKey is a color that is converted to transparent during
+	// the image decoding (classic magenta #ff00ff, for instance).
+	// Only the RGB components are compared.
+	//
+	// This is useful for legacy sprite packs without alpha channels.
+	// A nil value disables the color keying.
+	ColorKey color.Color
+
+	// Unmanaged makes the created ebiten image unmanaged.
+	// Unmanaged images are never put on an internal automatic texture atlas.
+	// This is useful for huge background images and render-to-texture
+	// sources to avoid the atlas churn.
+	//
+	// See ebiten.NewImageFromImageOptions for more details.
+	Unmanaged bool
+
+	// Mipmaps is a number of pre-downscaled image copies to generate.
+	// Every next copy is two times smaller than the previous one.
+	// The downscaling is done during the decoding using a high quality filter.
+	//
+	// This is useful for zoomed-out views (like world maps) that would
+	// shimmer otherwise and would require a runtime GPU downscaling.
+	// See Image.Mipmap method.
+	Mipmaps int
+
+	// Tiled permits splitting the image into several textures
+	// when it exceeds the Loader.MaxTextureSize.
+	// The tiled image has a nil Data, use its Tiled.Draw instead.
+	//
+	// This is useful for the huge level backgrounds.
+	// The tiled images have no mipmaps and can't be tinted.
+	Tiled bool
+
+	// Generate creates the image instead of reading it from the Path.
+	// It's called during the first LoadImage for this resource
+	// (and after every unload), so the generated image is cached
+	// like any other image resource.
+	//
+	// This is useful for the run-time rendered images, like level thumbnails.
+	// An *ebiten.Image result is used as is (unless it requires a color
+	// keying or mipmaps), so it's fine to render the image with Ebitengine.
+	Generate func() image.Image
+
+	// PackChannels creates the image by packing the grayscale images
+	// into its R, G, B and A channels (indexed by Channel values)
+	// instead of reading it from the Path.
+	// A zero ID leaves the channel empty (or opaque for the alpha).
+	//
+	// The source images are decoded directly (their textures are not created),
+	// they should have the same size.
+	// This is useful to combine the lighting maps (like occlusion,
+	// roughness and height) into a single texture.
+	PackChannels [4]ImageID
 }
 
+// AlphaMode is an image color values convention.
+// See ImageInfo.Alpha.
+type AlphaMode int
+
+const (
+	// AlphaAuto uses the image decoder convention.
+	AlphaAuto AlphaMode = iota
+
+	// AlphaPremultiplied means that the stored colors are
+	// already multiplied by their alpha values.
+	AlphaPremultiplied
+
+	// AlphaStraight means that the stored colors are not
+	// multiplied by their alpha values, even if the decoder
+	// reports them as premultiplied.
+	AlphaStraight
+)
+
 type Image struct {
 	// An ID that was associated with this resource.
 	ID ImageID
 
 	// An ebiten Image object initialized from the resource bytes.
+	// It's nil for the tiled images, see Tiled.
 	Data *ebiten.Image
 
 	DefaultFrameWidth  int
 	DefaultFrameHeight int
+
+	// Mipmaps are the pre-downscaled image copies.
+	// Mipmaps[0] is two times smaller than Data, Mipmaps[1] is
+	// four times smaller than Data, and so on.
+	// See ImageInfo.Mipmaps.
+	Mipmaps []*ebiten.Image
+
+	// Tiled is a split image that exceeds the max texture size.
+	// It's nil unless the image is too big (see ImageInfo.Tiled).
+	Tiled *TiledImage
+}
+
+// Mipmap returns the best image to draw it with the given scale.
+// If there are no suitable mipmaps, Data is returned.
+// A scale of 0.5 would select the first mipmap level, if it's available.
+//
+// Note that the returned image is smaller than the original one,
+// so its draw scale needs to be adjusted accordingly.
+func (img Image) Mipmap(scale float64) *ebiten.Image {
+	result := img.Data
+	levelScale := 1.0
+	for _, m := range img.Mipmaps {
+		levelScale /= 2
+		if levelScale < scale {
+			break
+		}
+		result = m
+	}
+	return result
+}
+
+// MaterialID is a typed key for Material resources.
+// See also: MaterialInfo.
+type MaterialID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id MaterialID) Ref() Ref { return Ref{Kind: KindMaterial, ID: int(id)} }
+
+// MaterialInfo binds an albedo image with its companion maps.
+//
+// For the optional fields, a zero ID means "not present".
+type MaterialInfo struct {
+	// Albedo is the main (diffuse) material image.
+	Albedo ImageID
+
+	// Normal is an optional normal map image.
+	Normal ImageID
+
+	// Emissive is an optional emission map image.
+	Emissive ImageID
+
+	// Shader is an optional shader that is used to render this material.
+	Shader ShaderID
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// Material is a set of images (and an optional shader) that
+// are used to render a sprite in lighting-based 2D games.
+//
+// All resources referenced by the material are loaded along with it
+// and they can't be unloaded while the material is loaded.
+type Material struct {
+	// An ID that was associated with this resource.
+	ID MaterialID
+
+	Albedo Image
+
+	// Normal.Data is nil if MaterialInfo.Normal was not specified.
+	Normal Image
+
+	// Emissive.Data is nil if MaterialInfo.Emissive was not specified.
+	Emissive Image
+
+	// Shader.Data is nil if MaterialInfo.Shader was not specified.
+	Shader Shader
+}
+
+// PaletteID is a typed key for Palette resources.
+// See also: PaletteInfo.
+type PaletteID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id PaletteID) Ref() Ref { return Ref{Kind: KindPalette, ID: int(id)} }
+
+type PaletteInfo struct {
+	// A path that will be used to read the resource data.
+	//
+	// The palette format is selected by the path extension:
+	//	".pal"  - JASC palette (Paint Shop Pro)
+	//	".gpl"  - GIMP palette
+	//	".json" - an array of "#rrggbb" (or "#rrggbbaa") strings
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+type Palette struct {
+	// An ID that was associated with this resource.
+	ID PaletteID
+
+	// Colors is a decoded list of palette colors.
+	Colors color.Palette
 }
 
 // RawID is a typed key for Raw resources.
 // See also: RawInfo.
 type RawID int
 
+// Ref returns a kind-tagged reference to this resource.
+func (id RawID) Ref() Ref { return Ref{Kind: KindRaw, ID: int(id)} }
+
 type RawInfo struct {
 	// A path that will be used to read the resource data.
 	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Soft makes this resource soft-cached: it can be unloaded
+	// automatically when the Loader.SoftCacheLimit is exceeded
+	// or when Loader.DropSoftCache is called.
+	// The next Load reads it again transparently.
+	//
+	// This is useful for the data that is needed only briefly,
+	// like level generation inputs.
+	Soft bool
 }
 
 type Raw struct {
@@ -127,19 +807,1127 @@ type Raw struct {
 	Data []byte
 }
 
-// ShaderID is a typed key for Shader resources.
-// See also: ShaderInfo.
-type ShaderID int
+// TemplateID is a typed key for Template resources.
+// See also: TemplateInfo.
+type TemplateID int
 
-type ShaderInfo struct {
+// Ref returns a kind-tagged reference to this resource.
+func (id TemplateID) Ref() Ref { return Ref{Kind: KindTemplate, ID: int(id)} }
+
+type TemplateInfo struct {
 	// A path that will be used to read the resource data.
 	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Funcs are added to the template function map before parsing.
+	Funcs template.FuncMap
 }
 
-type Shader struct {
+// Template is a parsed text/template file.
+type Template struct {
 	// An ID that was associated with this resource.
-	ID ShaderID
+	ID TemplateID
 
-	// A compiled shader.
-	Data *ebiten.Shader
+	// Template is a parsed template.
+	// Its name is the template resource path.
+	Template *template.Template
+}
+
+// ScriptID is a typed key for Script resources.
+// See also: ScriptInfo.
+type ScriptID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ScriptID) Ref() Ref { return Ref{Kind: KindScript, ID: int(id)} }
+
+type ScriptInfo struct {
+	// A path that will be used to read the resource data.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+type Script struct {
+	// An ID that was associated with this resource.
+	ID ScriptID
+
+	// Source is a script source code.
+	Source []byte
+
+	// Compiled is a value returned by the Loader.CompileScriptFunc.
+	// It's nil if there is no compile function set.
+	Compiled any
+}
+
+// ParticleDefID is a typed key for ParticleDef resources.
+// See also: ParticleDefInfo.
+type ParticleDefID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ParticleDefID) Ref() Ref { return Ref{Kind: KindParticleDef, ID: int(id)} }
+
+type ParticleDefInfo struct {
+	// A path that will be used to read the resource data.
+	// The resource is expected to be a JSON document.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the extra dependencies (the emitter images are added implicitly).
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Images maps the image names used in the particle def JSON
+	// to the resource IDs.
+	Images map[string]ImageID
+}
+
+// ParticleDef is a validated particle system definition.
+//
+// The JSON document layout follows the ParticleEmitter field tags:
+//
+//	{
+//	  "emitters": [
+//	    {
+//	      "name": "sparks",
+//	      "image": "spark",
+//	      "rate": 30,
+//	      "lifetime": {"min": 0.5, "max": 1},
+//	      "speed": {"min": 40, "max": 80},
+//	      "angle": {"min": 0, "max": 6.28},
+//	      "alpha": [{"t": 0, "value": 1}, {"t": 1, "value": 0}]
+//	    }
+//	  ]
+//	}
+//
+// The image values are the ParticleDefInfo.Images keys,
+// so the document doesn't depend on the image IDs numbering.
+type ParticleDef struct {
+	// An ID that was associated with this resource.
+	ID ParticleDefID `json:"-"`
+
+	Emitters []ParticleEmitter `json:"emitters"`
+}
+
+// HitboxesID is a typed key for Hitboxes resources.
+// See also: HitboxesInfo.
+type HitboxesID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id HitboxesID) Ref() Ref { return Ref{Kind: KindHitboxes, ID: int(id)} }
+
+type HitboxesInfo struct {
+	// A path that will be used to read the resource data.
+	//
+	// The resource is either a simple JSON sidecar file
+	// with a list of boxes for every frame:
+	//
+	//	{"frames": [[{"name": "hurt", "x": 2, "y": 0, "w": 12, "h": 16}], []]}
+	//
+	// Or an Aseprite JSON export with slices
+	// (every slice becomes a hitbox named after the slice).
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Image is an animation image these hitboxes describe.
+	// The image is not loaded automatically (hitboxes could be
+	// used without any graphics, like on a game server).
+	Image ImageID
+}
+
+// Hitboxes is a per-frame collision boxes data.
+type Hitboxes struct {
+	// An ID that was associated with this resource.
+	ID HitboxesID
+
+	// Image is an associated animation image.
+	// See HitboxesInfo.Image.
+	Image ImageID
+
+	frames [][]Hitbox
+}
+
+// NumFrames reports the number of frames described by this resource.
+func (h Hitboxes) NumFrames() int { return len(h.frames) }
+
+// Hitboxes returns the boxes of the given animation frame.
+// It returns nil for the frames without boxes or out of range frames.
+//
+// The returned slice should not be modified.
+func (h Hitboxes) Hitboxes(frame int) []Hitbox {
+	if frame < 0 || frame >= len(h.frames) {
+		return nil
+	}
+	return h.frames[frame]
+}
+
+// TileSetID is a typed key for TileSet resources.
+// See also: TileSetInfo.
+type TileSetID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id TileSetID) Ref() Ref { return Ref{Kind: KindTileSet, ID: int(id)} }
+
+type TileSetInfo struct {
+	// A path that will be used to read the resource data.
+	//
+	// Paths with ".tsx" extension are decoded as Tiled tile sets.
+	// Otherwise the resource is expected to be a JSON document:
+	//
+	//	{
+	//	  "tile_width": 16,
+	//	  "tile_height": 16,
+	//	  "tile_count": 64,
+	//	  "columns": 8,
+	//	  "spacing": 1,
+	//	  "margin": 1,
+	//	  "tiles": [
+	//	    {"index": 3, "collision": true, "terrain": ["wall"]},
+	//	    {"index": 5, "animation": [{"tile_index": 5, "duration": 100}, {"tile_index": 6, "duration": 100}]}
+	//	  ]
+	//	}
+	//
+	// The animation frame durations are specified in milliseconds.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the extra dependencies (the tile set Image is added implicitly).
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Image is a tile set texture.
+	// The image path stored inside the TSX file is ignored.
+	Image ImageID
+}
+
+// TileSet is a tile set image along with its per-tile properties.
+type TileSet struct {
+	// An ID that was associated with this resource.
+	ID TileSetID
+
+	// Image is a tile set texture.
+	Image ImageID
+
+	TileWidth  int
+	TileHeight int
+
+	// Columns is a number of tiles per texture row.
+	Columns int
+
+	// Spacing is a number of pixels between the adjacent tiles.
+	Spacing int
+
+	// Margin is a number of pixels around the tiles (the texture padding).
+	Margin int
+
+	// Tiles are indexed by the tile index.
+	Tiles []Tile
+}
+
+// TileRect returns the texture rectangle of the tile with a given index.
+func (ts TileSet) TileRect(index int) image.Rectangle {
+	x := ts.Margin + (index%ts.Columns)*(ts.TileWidth+ts.Spacing)
+	y := ts.Margin + (index/ts.Columns)*(ts.TileHeight+ts.Spacing)
+	return image.Rect(x, y, x+ts.TileWidth, y+ts.TileHeight)
+}
+
+// CompositeID is a typed key for Composite resources.
+// See also: CompositeInfo.
+type CompositeID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id CompositeID) Ref() Ref { return Ref{Kind: KindComposite, ID: int(id)} }
+
+// CompositeInfo describes an image that is composed of several image layers,
+// like a paper-doll character (base + outfit + weapon).
+type CompositeInfo struct {
+	// Layers are drawn in order: the first layer is at the bottom.
+	Layers []CompositeLayer
+
+	// Width and Height are the composite image size.
+	// If they're zero, the size is selected to fit all layers.
+	Width  int
+	Height int
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// CompositeLayer is a single image layer of the composite image.
+type CompositeLayer struct {
+	Image ImageID
+
+	// Offset is a layer position inside the composite image.
+	Offset image.Point
+
+	// CompositeMode is a layer blending mode.
+	// The default value is ebiten.CompositeModeSourceOver.
+	CompositeMode ebiten.CompositeMode
+}
+
+// Composite is an image that is flattened from its layers once during the loading.
+// The layer images are loaded in the process, but they're not dependencies:
+// they can be unloaded without affecting the composite image.
+type Composite struct {
+	// An ID that was associated with this resource.
+	ID CompositeID
+
+	Data *ebiten.Image
+}
+
+// ShaderImageID is a typed key for ShaderImage resources.
+// See also: ShaderImageInfo.
+type ShaderImageID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ShaderImageID) Ref() Ref { return Ref{Kind: KindShaderImage, ID: int(id)} }
+
+// ShaderImageInfo describes a procedural image that is rendered
+// by a shader, like a noise, gradient or vignette texture.
+type ShaderImageInfo struct {
+	// Shader renders the image.
+	Shader ShaderID
+
+	// Width and Height are the rendered image size.
+	Width  int
+	Height int
+
+	// Uniforms are the shader uniform values.
+	// They're merged with the shader default uniforms
+	// (see ShaderInfo.DefaultUniforms), overriding them.
+	Uniforms map[string]any
+
+	// Images are the optional shader source images.
+	// A zero ID means "no image".
+	// All source images should have the Width*Height size.
+	Images [4]ImageID
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// ShaderImage is an image that is rendered by a shader once during the loading.
+// The shader and source images are loaded in the process, but they're not
+// dependencies: they can be unloaded without affecting the rendered image.
+type ShaderImage struct {
+	// An ID that was associated with this resource.
+	ID ShaderImageID
+
+	Data *ebiten.Image
+}
+
+// LUTID is a typed key for LUT resources.
+// See also: LUTInfo.
+type LUTID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id LUTID) Ref() Ref { return Ref{Kind: KindLUT, ID: int(id)} }
+
+type LUTInfo struct {
+	// A path that will be used to read the resource data.
+	//
+	// The ".cube" files are decoded as the Adobe/Resolve cube LUTs.
+	// Other files are decoded as the strip images: N*N wide and N high,
+	// where N is the LUT size (like 256x16).
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// LUT is a color grading lookup table.
+//
+// The table is stored as a strip image, regardless of the source format:
+// Size blue slices of Size*Size pixels are laid out horizontally.
+// Inside every slice, red grows along the X axis and green grows along the Y axis.
+// It can be bound as a shader image right away:
+//
+//	options.Images[1] = lut.Data
+//	options.Uniforms["LUTSize"] = float32(lut.Size)
+type LUT struct {
+	// An ID that was associated with this resource.
+	ID LUTID
+
+	// Size is a number of entries per color channel.
+	Size int
+
+	Data *ebiten.Image
+}
+
+// GradientID is a typed key for Gradient resources.
+// See also: GradientInfo.
+type GradientID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id GradientID) Ref() Ref { return Ref{Kind: KindGradient, ID: int(id)} }
+
+type GradientInfo struct {
+	// A path that will be used to read the resource data.
+	// See Gradient for the JSON format description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// Gradient is a color gradient loaded from JSON:
+//
+//	{
+//	  "stops": [
+//	    {"t": 0, "color": "#ff0000"},
+//	    {"t": 1, "color": "#0000ff80", "ease": "in_out_sine"}
+//	  ]
+//	}
+//
+// The stops should be sorted by t.
+// The colors are "#rrggbb" or "#rrggbbaa" hex values.
+// The ease is applied to the segment that starts at the stop,
+// see Easing for the supported names.
+type Gradient struct {
+	// An ID that was associated with this resource.
+	ID GradientID
+
+	// Stops are sorted by their T values.
+	Stops []GradientStop
+}
+
+// CurveID is a typed key for Curve resources.
+// See also: CurveInfo.
+type CurveID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id CurveID) Ref() Ref { return Ref{Kind: KindCurve, ID: int(id)} }
+
+type CurveInfo struct {
+	// A path that will be used to read the resource data.
+	// See Curve for the JSON format description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// Curve is an animation curve loaded from JSON:
+//
+//	{
+//	  "loop": false,
+//	  "keys": [
+//	    {"t": 0, "value": 0, "ease": "out_quad"},
+//	    {"t": 0.5, "value": 10},
+//	    {"t": 1, "value": 0}
+//	  ]
+//	}
+//
+// The keys should be sorted by t.
+// The ease is applied to the segment that starts at the key,
+// see Easing for the supported names.
+type Curve struct {
+	// An ID that was associated with this resource.
+	ID CurveID
+
+	// Keys are sorted by their T values.
+	Keys []CurveKey
+
+	// Loop makes the curve repeat itself outside of its keys range.
+	Loop bool
+}
+
+// InputProfileID is a typed key for InputProfile resources.
+// See also: InputProfileInfo.
+type InputProfileID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id InputProfileID) Ref() Ref { return Ref{Kind: KindInputProfile, ID: int(id)} }
+
+type InputProfileInfo struct {
+	// A path that will be used to read the resource data.
+	// See InputProfile for the JSON format description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Actions is a list of the game actions.
+	// If it's not empty, the profile should bind all of these actions
+	// and it can't contain any other actions.
+	Actions []string
+}
+
+// InputProfile is an input binding profile loaded from JSON:
+//
+//	{
+//	  "actions": {
+//	    "jump": ["Space", "gamepad:RightBottom"],
+//	    "fire": ["KeyX", "mouse:Left"]
+//	  }
+//	}
+//
+// The keys are ebiten.Key names.
+// The gamepad buttons are ebiten.StandardGamepadButton names
+// without a "StandardGamepadButton" prefix.
+// The mouse buttons are "Left", "Right" and "Middle".
+type InputProfile struct {
+	// An ID that was associated with this resource.
+	ID InputProfileID
+
+	// Actions maps the action names to their bindings.
+	Actions map[string][]InputBinding
+}
+
+// IsPressed reports whether any of the action bindings is pressed.
+// The gamepad argument is only used for the gamepad bindings.
+func (p InputProfile) IsPressed(action string, gamepad ebiten.GamepadID) bool {
+	for _, b := range p.Actions[action] {
+		if b.IsPressed(gamepad) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefinitionID is a typed key for Definition resources.
+// See also: DefinitionInfo.
+type DefinitionID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id DefinitionID) Ref() Ref { return Ref{Kind: KindDefinition, ID: int(id)} }
+
+type DefinitionInfo struct {
+	// A path that will be used to read the resource data.
+	// The data is expected to be a JSON document.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Schema is a Raw resource with a JSON Schema for this definition.
+	// The definition is validated against it when loaded,
+	// the violations are reported as *SchemaError.
+	// A zero value means that only the JSON syntax is checked.
+	//
+	// Only a subset of JSON Schema is supported: type, enum, const,
+	// properties, required, additionalProperties, items,
+	// minimum, maximum, minLength, maxLength, pattern, minItems,
+	// maxItems and the local $ref to $defs (or definitions).
+	// The other keywords are ignored.
+	Schema RawID
+}
+
+// Definition is a validated JSON document, like a quest
+// or an achievement description.
+// See DefinitionInfo.Schema.
+type Definition struct {
+	// An ID that was associated with this resource.
+	ID DefinitionID
+
+	// Data is the definition JSON document.
+	Data []byte
+}
+
+// Decode unmarshals the definition into v using the encoding/json rules.
+func (d Definition) Decode(v any) error {
+	return json.Unmarshal(d.Data, v)
+}
+
+// DocumentID is a typed key for Document resources.
+// See also: DocumentInfo.
+type DocumentID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id DocumentID) Ref() Ref { return Ref{Kind: KindDocument, ID: int(id)} }
+
+type DocumentInfo struct {
+	// A path that will be used to read the resource data.
+	// See Document for the markup description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Font is the default text font.
+	Font FontID
+
+	// Fonts maps the [font=name] tag names to the font resources.
+	Fonts map[string]FontID
+
+	// Color is the default text color.
+	// A zero value means opaque white.
+	Color color.NRGBA
+}
+
+// Document is a rich text document, like a credits or a help screen text.
+//
+// The document is written in a BBCode-like markup:
+//
+//	[font=title]Credits[/font]
+//
+//	Programming: [color=#ffcc00]Jane Doe[/color]
+//
+// The [font=name] tag selects a font from the DocumentInfo.Fonts,
+// the [color=#rrggbb] tag changes the text color (#rrggbbaa is also accepted).
+// The tags can be nested. Use "[[" to get a literal "[".
+//
+// All document fonts are loaded along with the document.
+type Document struct {
+	// An ID that was associated with this resource.
+	ID DocumentID
+
+	// Lines are the document text lines, split into styled spans.
+	// An empty line has no spans.
+	Lines [][]TextSpan
+}
+
+// IconSetID is a typed key for IconSet resources.
+// See also: IconSetInfo.
+type IconSetID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id IconSetID) Ref() Ref { return Ref{Kind: KindIconSet, ID: int(id)} }
+
+type IconSetInfo struct {
+	// A path that will be used to read the resource data.
+	// See IconSet for the JSON format description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Font is an icon font for the glyph icons.
+	Font FontID
+
+	// Atlas is an icon atlas image for the region icons.
+	Atlas ImageID
+}
+
+// IconSet maps the symbolic icon names to the icon font glyphs
+// and icon atlas regions, so the UI code can request icons by name.
+//
+// The icon set is loaded from a JSON object:
+//
+//	{
+//	  "icon.sword": "U+E001",
+//	  "icon.shield": [16, 0, 16, 16]
+//	}
+//
+// A string value is a glyph of the IconSetInfo.Font (a "U+XXXX" code point
+// or a single rune), an array is a [x, y, width, height] region
+// of the IconSetInfo.Atlas.
+//
+// The icon font and atlas are loaded along with the icon set.
+// To apply the edited mapping (hot reload), Unload the icon set and load it again.
+type IconSet struct {
+	// An ID that was associated with this resource.
+	ID IconSetID
+
+	// Face is the icon font face; it's nil if IconSetInfo.Font is not set.
+	Face font.Face
+
+	// Atlas is the icon atlas image; it's nil if IconSetInfo.Atlas is not set.
+	Atlas *ebiten.Image
+
+	Icons map[string]Icon
+}
+
+// Rune returns the named icon font glyph.
+// It panics if there is no such font icon.
+func (s IconSet) Rune(name string) rune {
+	icon, ok := s.Icons[name]
+	if !ok || icon.Rune == 0 {
+		panic(fmt.Sprintf("icon set with id=%d has no font icon %q", s.ID, name))
+	}
+	return icon.Rune
+}
+
+// Image returns the named icon atlas region.
+// It panics if there is no such atlas icon.
+func (s IconSet) Image(name string) *ebiten.Image {
+	icon, ok := s.Icons[name]
+	if !ok || icon.Rect.Empty() {
+		panic(fmt.Sprintf("icon set with id=%d has no atlas icon %q", s.ID, name))
+	}
+	return s.Atlas.SubImage(icon.Rect).(*ebiten.Image)
+}
+
+// ThemeID is a typed key for Theme resources.
+// See also: ThemeInfo.
+type ThemeID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ThemeID) Ref() Ref { return Ref{Kind: KindTheme, ID: int(id)} }
+
+type ThemeInfo struct {
+	// A path that will be used to read the resource data.
+	// See Theme for the JSON format description.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Fonts and Images map the names used in the theme JSON
+	// to the resource IDs.
+	Fonts  map[string]FontID
+	Images map[string]ImageID
+}
+
+// Theme is a UI skin that bundles the fonts, nine-slice images and colors.
+//
+// The theme is loaded from a JSON object:
+//
+//	{
+//	  "fonts": {"label": "main", "title": "main_large"},
+//	  "images": {"button": {"image": "button_png", "slice": [4, 4, 4, 4]}},
+//	  "colors": {"text": "#f0f0f0", "text_disabled": "#f0f0f080"}
+//	}
+//
+// The font and image values are the ThemeInfo.Fonts and ThemeInfo.Images keys.
+// The slice is the [left, top, right, bottom] nine-slice border sizes.
+//
+// All references are resolved and validated at load time;
+// the referenced resources are loaded along with the theme.
+// Since the UI code only refers to the theme keys, a whole skin
+// can be swapped by loading another Theme.
+type Theme struct {
+	// An ID that was associated with this resource.
+	ID ThemeID
+
+	Fonts  map[string]Font
+	Images map[string]NineSlice
+	Colors map[string]color.NRGBA
+}
+
+// Font returns the named theme font.
+// It panics if there is no such font.
+func (t Theme) Font(name string) Font {
+	f, ok := t.Fonts[name]
+	if !ok {
+		panic(fmt.Sprintf("theme with id=%d has no font %q", t.ID, name))
+	}
+	return f
+}
+
+// Image returns the named theme image.
+// It panics if there is no such image.
+func (t Theme) Image(name string) NineSlice {
+	s, ok := t.Images[name]
+	if !ok {
+		panic(fmt.Sprintf("theme with id=%d has no image %q", t.ID, name))
+	}
+	return s
+}
+
+// Color returns the named theme color.
+// It panics if there is no such color.
+func (t Theme) Color(name string) color.NRGBA {
+	c, ok := t.Colors[name]
+	if !ok {
+		panic(fmt.Sprintf("theme with id=%d has no color %q", t.ID, name))
+	}
+	return c
+}
+
+// VariantSetID is a typed key for VariantSet resources.
+// See also: VariantSetInfo.
+type VariantSetID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id VariantSetID) Ref() Ref { return Ref{Kind: KindVariantSet, ID: int(id)} }
+
+type VariantSetInfo struct {
+	// Items are the interchangeable resources, like several grass tiles.
+	// They're usually of the same kind.
+	Items []Ref
+
+	// Weights are the optional relative item probabilities.
+	// If it's empty, all items are equally likely to be picked.
+	Weights []float64
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+}
+
+// VariantSet is a set of the interchangeable cosmetic resources.
+// The items are selected deterministically by a seed,
+// so the procedural maps get varied, but reproducible art:
+//
+//	grass := l.LoadVariantSet(VariantSetGrass)
+//	img := l.LoadImage(ImageID(grass.Pick(resource.TileSeed(mapSeed, x, y)).ID))
+//
+// The items are not loaded along with the set.
+type VariantSet struct {
+	// An ID that was associated with this resource.
+	ID VariantSetID
+
+	Items []Ref
+
+	// weights are the cumulative item weights.
+	weights []float64
+}
+
+// TableID is a typed key for Table resources.
+// See also: TableInfo.
+type TableID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id TableID) Ref() Ref { return Ref{Kind: KindTable, ID: int(id)} }
+
+type TableInfo struct {
+	// A path that will be used to read the resource data.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// Comma is a field delimiter.
+	// A zero value selects the delimiter based on the path extension:
+	// a tab for ".tsv" files and a comma for everything else.
+	//
+	// The ".tsv" files with a zero Comma are split on tabs without
+	// any quoting, so the quote characters are kept in the values as is.
+	// An explicit Comma always selects the CSV parsing rules.
+	Comma rune
+}
+
+// Table is a parsed CSV (or TSV) table.
+// The first record of the file is a header: it contains the column names.
+//
+// Use DecodeTable to map the table rows to a struct slice.
+type Table struct {
+	// An ID that was associated with this resource.
+	ID TableID
+
+	// Columns are the table column names (the header record).
+	Columns []string
+
+	// Rows are the table records, not including the header.
+	Rows [][]string
+
+	columnIndex map[string]int
+}
+
+// ColumnIndex returns the index of the named column.
+// It returns -1 if there is no such column.
+func (t Table) ColumnIndex(name string) int {
+	if i, ok := t.columnIndex[name]; ok {
+		return i
+	}
+	return -1
+}
+
+// Get returns the named column value of the specified row.
+// It returns an empty string if there is no such column.
+func (t Table) Get(row int, column string) string {
+	i := t.ColumnIndex(column)
+	if i == -1 {
+		return ""
+	}
+	return t.Rows[row][i]
+}
+
+// Column returns all values of the named column.
+// It returns nil if there is no such column.
+func (t Table) Column(name string) []string {
+	i := t.ColumnIndex(name)
+	if i == -1 {
+		return nil
+	}
+	values := make([]string, len(t.Rows))
+	for j, row := range t.Rows {
+		values[j] = row[i]
+	}
+	return values
+}
+
+// ShaderID is a typed key for Shader resources.
+// See also: ShaderInfo.
+type ShaderID int
+
+// Ref returns a kind-tagged reference to this resource.
+func (id ShaderID) Ref() Ref { return Ref{Kind: KindShader, ID: int(id)} }
+
+type ShaderInfo struct {
+	// A path that will be used to read the resource data.
+	Path string
+
+	// PathByPlatform overrides the Path per platform (see Loader.Platform).
+	PathByPlatform map[string]string
+
+	// Data is used instead of reading the Path if it's not nil.
+	Data []byte
+
+	// DependsOn lists the resources that are loaded along with this one.
+	DependsOn []Ref
+
+	// Eager marks a resource that is loaded by Loader.PreloadEager.
+	Eager bool
+
+	// Tier is a progressive loading tier (see Loader.PreloadTier).
+	Tier Tier
+
+	// FileSize is an optional data size for Loader.EstimateBytes.
+	FileSize int64
+
+	// DefaultUniforms are the default shader uniform values.
+	// They're validated against the shader source during the loading:
+	// every key should match a uniform variable declared in the shader.
+	//
+	// See Shader.Uniforms method.
+	DefaultUniforms map[string]any
+
+	// LibraryPaths are the shader source files that are concatenated
+	// (in order) before the Path file contents prior to the compilation.
+	// This is a simple way to share a common code between the shaders.
+	//
+	// Since the files are concatenated, the package clause should
+	// appear only once: in the first library file.
+	LibraryPaths []string
+}
+
+type Shader struct {
+	// An ID that was associated with this resource.
+	ID ShaderID
+
+	// A compiled shader.
+	Data *ebiten.Shader
+
+	// DefaultUniforms is a ShaderInfo.DefaultUniforms value.
+	// It should not be modified, use Uniforms method to get a mutable copy.
+	DefaultUniforms map[string]any
+}
+
+// Uniforms returns a copy of the default uniforms map.
+// The result can be used to seed the DrawRectShaderOptions.Uniforms.
+func (s Shader) Uniforms() map[string]any {
+	m := make(map[string]any, len(s.DefaultUniforms))
+	for k, v := range s.DefaultUniforms {
+		m[k] = v
+	}
+	return m
 }
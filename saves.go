@@ -0,0 +1,97 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SaveStore reads and writes the savegame slots.
+//
+// Every slot is an opaque blob identified by its name, like "slot1" or "autosave".
+// The binary data can be stored via Write/Read methods while the
+// WriteJSON/ReadJSON methods handle the serialization.
+//
+// On most platforms the slots are stored as files inside the
+// Loader.SaveRoot directory. On wasm, the browser localStorage
+// is used instead (with SaveRoot used as a key prefix).
+//
+// Reading a missing slot results in an error that satisfies
+// errors.Is(err, fs.ErrNotExist).
+type SaveStore struct {
+	backend saveBackend
+}
+
+type saveBackend interface {
+	read(slot string) ([]byte, error)
+	write(slot string, data []byte) error
+	remove(slot string) error
+}
+
+// Saves returns the loader savegame store.
+//
+// The store is created during the first Saves call,
+// so SaveRoot should be set before that.
+func (l *Loader) Saves() *SaveStore {
+	if l.saves == nil {
+		if l.SaveRoot == "" {
+			panic("can't use saves: SaveRoot is not set")
+		}
+		l.saves = &SaveStore{backend: newSaveBackend(l.SaveRoot)}
+	}
+	return l.saves
+}
+
+// Read returns the slot contents.
+func (s *SaveStore) Read(slot string) ([]byte, error) {
+	if err := validateSaveSlot(slot); err != nil {
+		return nil, err
+	}
+	return s.backend.read(slot)
+}
+
+// Write replaces the slot contents with data.
+// A slot is created if it doesn't exist.
+func (s *SaveStore) Write(slot string, data []byte) error {
+	if err := validateSaveSlot(slot); err != nil {
+		return err
+	}
+	return s.backend.write(slot, data)
+}
+
+// Delete removes the slot.
+// Deleting a missing slot is not an error.
+func (s *SaveStore) Delete(slot string) error {
+	if err := validateSaveSlot(slot); err != nil {
+		return err
+	}
+	return s.backend.remove(slot)
+}
+
+// ReadJSON decodes the slot contents into v.
+func (s *SaveStore) ReadJSON(slot string, v any) error {
+	data, err := s.Read(slot)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %q save slot: %w", slot, err)
+	}
+	return nil
+}
+
+// WriteJSON encodes v and writes it into the slot.
+func (s *SaveStore) WriteJSON(slot string, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode %q save slot: %w", slot, err)
+	}
+	return s.Write(slot, data)
+}
+
+func validateSaveSlot(slot string) error {
+	if slot == "" || slot == "." || slot == ".." || strings.ContainsAny(slot, `/\:`) {
+		return fmt.Errorf("invalid save slot name %q", slot)
+	}
+	return nil
+}
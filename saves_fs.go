@@ -0,0 +1,56 @@
+//go:build !js
+
+package resource
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+type fileSaveBackend struct {
+	root string
+}
+
+func newSaveBackend(root string) saveBackend {
+	return &fileSaveBackend{root: root}
+}
+
+func (b *fileSaveBackend) slotPath(slot string) string {
+	return filepath.Join(b.root, slot+".sav")
+}
+
+func (b *fileSaveBackend) read(slot string) ([]byte, error) {
+	return os.ReadFile(b.slotPath(slot))
+}
+
+func (b *fileSaveBackend) write(slot string, data []byte) error {
+	if err := os.MkdirAll(b.root, 0o755); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so a crash during the
+	// saving doesn't corrupt the previous slot contents.
+	f, err := os.CreateTemp(b.root, slot+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, writeErr := f.Write(data)
+	closeErr := f.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(f.Name())
+		return writeErr
+	}
+	return os.Rename(f.Name(), b.slotPath(slot))
+}
+
+func (b *fileSaveBackend) remove(slot string) error {
+	err := os.Remove(b.slotPath(slot))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+//go:build js
+
+package resource
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"syscall/js"
+)
+
+type localStorageSaveBackend struct {
+	prefix string
+}
+
+func newSaveBackend(root string) saveBackend {
+	return &localStorageSaveBackend{prefix: root + "/"}
+}
+
+func (b *localStorageSaveBackend) storage() (js.Value, error) {
+	storage := js.Global().Get("localStorage")
+	if storage.IsUndefined() || storage.IsNull() {
+		return js.Value{}, errors.New("localStorage is not available")
+	}
+	return storage, nil
+}
+
+func (b *localStorageSaveBackend) read(slot string) ([]byte, error) {
+	storage, err := b.storage()
+	if err != nil {
+		return nil, err
+	}
+	v := storage.Call("getItem", b.prefix+slot)
+	if v.IsNull() {
+		return nil, fmt.Errorf("read %q save slot: %w", slot, fs.ErrNotExist)
+	}
+	// localStorage can only store strings, so the data is base64-encoded.
+	return base64.StdEncoding.DecodeString(v.String())
+}
+
+func (b *localStorageSaveBackend) write(slot string, data []byte) (err error) {
+	storage, err := b.storage()
+	if err != nil {
+		return err
+	}
+	// setItem throws if the storage quota is exceeded.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("write %q save slot: %v", slot, r)
+		}
+	}()
+	storage.Call("setItem", b.prefix+slot, base64.StdEncoding.EncodeToString(data))
+	return nil
+}
+
+func (b *localStorageSaveBackend) remove(slot string) error {
+	storage, err := b.storage()
+	if err != nil {
+		return err
+	}
+	storage.Call("removeItem", b.prefix+slot)
+	return nil
+}
@@ -0,0 +1,476 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SchemaError describes a definition that doesn't match its JSON Schema.
+// See DefinitionInfo.Schema.
+//
+// The loader panics with an *Error that wraps this value,
+// use errors.As to get the location details.
+type SchemaError struct {
+	// Pointer is a JSON Pointer (RFC 6901) of the invalid value, like "/rewards/0/gold".
+	// It's empty for the document root.
+	Pointer string
+
+	// Offset is the invalid value data offset.
+	Offset int64
+
+	// Line and Column are 1-based position of the invalid value.
+	Line   int
+	Column int
+
+	Message string
+}
+
+func (e *SchemaError) Error() string {
+	pointer := e.Pointer
+	if pointer == "" {
+		pointer = "/"
+	}
+	return fmt.Sprintf("%s (line %d, column %d): %s", pointer, e.Line, e.Column, e.Message)
+}
+
+// jsonSchema is a subset of JSON Schema that is enough to validate
+// the game definitions.
+//
+// The supported keywords are: type, enum, const, properties, required,
+// additionalProperties, items, minimum, maximum, minLength, maxLength,
+// pattern, minItems, maxItems, $defs, definitions and the local $ref
+// ("#", "#/$defs/name" and "#/definitions/name").
+// All other keywords are ignored.
+type jsonSchema struct {
+	Type                 json.RawMessage        `json:"type"`
+	Enum                 []any                  `json:"enum"`
+	Const                *any                   `json:"const"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	Ref                  string                 `json:"$ref"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+
+	types            []string
+	pattern          *regexp.Regexp
+	noAdditional     bool
+	additionalSchema *jsonSchema
+}
+
+func parseJSONSchema(data []byte) (*jsonSchema, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	if err := s.compile(&s, "#"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *jsonSchema) compile(root *jsonSchema, path string) error {
+	if len(s.Type) != 0 {
+		var typ string
+		if err := json.Unmarshal(s.Type, &typ); err == nil {
+			s.types = []string{typ}
+		} else if err := json.Unmarshal(s.Type, &s.types); err != nil {
+			return fmt.Errorf("%s: type should be a string or an array of strings", path)
+		}
+		for _, typ := range s.types {
+			switch typ {
+			case "null", "boolean", "object", "array", "number", "integer", "string":
+			default:
+				return fmt.Errorf("%s: unknown type %q", path, typ)
+			}
+		}
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: pattern: %w", path, err)
+		}
+		s.pattern = re
+	}
+	if len(s.AdditionalProperties) != 0 {
+		var allowed bool
+		if err := json.Unmarshal(s.AdditionalProperties, &allowed); err == nil {
+			s.noAdditional = !allowed
+		} else {
+			var additional jsonSchema
+			if err := json.Unmarshal(s.AdditionalProperties, &additional); err != nil {
+				return fmt.Errorf("%s: additionalProperties should be a boolean or a schema", path)
+			}
+			s.additionalSchema = &additional
+		}
+	}
+	if s.Ref != "" {
+		if _, err := root.resolveRef(s.Ref); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	children := make(map[string]*jsonSchema)
+	for name, x := range s.Properties {
+		children["properties/"+name] = x
+	}
+	for name, x := range s.Defs {
+		children["$defs/"+name] = x
+	}
+	for name, x := range s.Definitions {
+		children["definitions/"+name] = x
+	}
+	if s.Items != nil {
+		children["items"] = s.Items
+	}
+	if s.additionalSchema != nil {
+		children["additionalProperties"] = s.additionalSchema
+	}
+	for key, x := range children {
+		if x == nil {
+			return fmt.Errorf("%s/%s: schema is null", path, key)
+		}
+		if err := x.compile(root, path+"/"+key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *jsonSchema) resolveRef(ref string) (*jsonSchema, error) {
+	if ref == "#" {
+		return s, nil
+	}
+	var defs map[string]*jsonSchema
+	var name string
+	switch {
+	case strings.HasPrefix(ref, "#/$defs/"):
+		defs, name = s.Defs, strings.TrimPrefix(ref, "#/$defs/")
+	case strings.HasPrefix(ref, "#/definitions/"):
+		defs, name = s.Definitions, strings.TrimPrefix(ref, "#/definitions/")
+	default:
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	target, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return target, nil
+}
+
+// jsonNode is a parsed JSON value that remembers its data offset.
+type jsonNode struct {
+	offset int64
+
+	// value is nil, bool, json.Number, string,
+	// []*jsonNode or []jsonMember.
+	value any
+}
+
+type jsonMember struct {
+	key   string
+	value *jsonNode
+}
+
+type jsonTreeParser struct {
+	dec  *json.Decoder
+	data []byte
+}
+
+func parseJSONTree(data []byte) (*jsonNode, error) {
+	// The streaming decoder doesn't report the trailing garbage,
+	// so the syntax is checked separately.
+	var raw json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	p := jsonTreeParser{dec: dec, data: data}
+	return p.parseValue()
+}
+
+// valueStart skips the separators preceding the next token.
+func (p *jsonTreeParser) valueStart() int64 {
+	offset := p.dec.InputOffset()
+	for offset < int64(len(p.data)) {
+		switch p.data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func (p *jsonTreeParser) parseValue() (*jsonNode, error) {
+	n := &jsonNode{offset: p.valueStart()}
+	tok, err := p.dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '{':
+			members := []jsonMember{}
+			for p.dec.More() {
+				key, err := p.dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				members = append(members, jsonMember{key: key.(string), value: value})
+			}
+			n.value = members
+		case '[':
+			elems := []*jsonNode{}
+			for p.dec.More() {
+				elem, err := p.parseValue()
+				if err != nil {
+					return nil, err
+				}
+				elems = append(elems, elem)
+			}
+			n.value = elems
+		}
+		// Consume the closing delimiter.
+		if _, err := p.dec.Token(); err != nil {
+			return nil, err
+		}
+	default:
+		n.value = tok
+	}
+	return n, nil
+}
+
+// toInterface converts the node to the encoding/json default representation.
+func (n *jsonNode) toInterface() any {
+	switch v := n.value.(type) {
+	case json.Number:
+		f, _ := v.Float64()
+		return f
+	case []*jsonNode:
+		result := make([]any, len(v))
+		for i, elem := range v {
+			result[i] = elem.toInterface()
+		}
+		return result
+	case []jsonMember:
+		result := make(map[string]any, len(v))
+		for _, m := range v {
+			result[m.key] = m.value.toInterface()
+		}
+		return result
+	default:
+		return v
+	}
+}
+
+func (n *jsonNode) typeName() string {
+	switch v := n.value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if f, err := v.Float64(); err == nil && f == math.Trunc(f) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []*jsonNode:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+type schemaValidator struct {
+	root *jsonSchema
+	data []byte
+}
+
+func (v *schemaValidator) errorf(n *jsonNode, pointer string, format string, args ...any) error {
+	line, column := 1, 1
+	for _, c := range v.data[:n.offset] {
+		if c == '\n' {
+			line++
+			column = 1
+		} else {
+			column++
+		}
+	}
+	return &SchemaError{
+		Pointer: pointer,
+		Offset:  n.offset,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func (v *schemaValidator) validate(s *jsonSchema, n *jsonNode, pointer string) error {
+	if s.Ref != "" {
+		// The refs were checked during the schema compilation.
+		target, _ := v.root.resolveRef(s.Ref)
+		if err := v.validate(target, n, pointer); err != nil {
+			return err
+		}
+	}
+
+	typ := n.typeName()
+	if len(s.types) != 0 {
+		matched := false
+		for _, want := range s.types {
+			if want == typ || want == "number" && typ == "integer" {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return v.errorf(n, pointer, "expected %s, found %s", strings.Join(s.types, " or "), typ)
+		}
+	}
+	if s.Const != nil && !reflect.DeepEqual(*s.Const, n.toInterface()) {
+		return v.errorf(n, pointer, "value should be %s", jsonString(*s.Const))
+	}
+	if s.Enum != nil {
+		value := n.toInterface()
+		found := false
+		for _, x := range s.Enum {
+			if reflect.DeepEqual(x, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			options := make([]string, len(s.Enum))
+			for i, x := range s.Enum {
+				options[i] = jsonString(x)
+			}
+			return v.errorf(n, pointer, "value should be one of %s", strings.Join(options, ", "))
+		}
+	}
+
+	switch value := n.value.(type) {
+	case json.Number:
+		f, _ := value.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			return v.errorf(n, pointer, "%s is less than minimum %v", value, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			return v.errorf(n, pointer, "%s is greater than maximum %v", value, *s.Maximum)
+		}
+	case string:
+		length := len([]rune(value))
+		if s.MinLength != nil && length < *s.MinLength {
+			return v.errorf(n, pointer, "string is shorter than %d", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return v.errorf(n, pointer, "string is longer than %d", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			return v.errorf(n, pointer, "%q doesn't match %q", value, s.Pattern)
+		}
+	case []*jsonNode:
+		if s.MinItems != nil && len(value) < *s.MinItems {
+			return v.errorf(n, pointer, "array has less than %d items", *s.MinItems)
+		}
+		if s.MaxItems != nil && len(value) > *s.MaxItems {
+			return v.errorf(n, pointer, "array has more than %d items", *s.MaxItems)
+		}
+		if s.Items != nil {
+			for i, elem := range value {
+				if err := v.validate(s.Items, elem, pointer+"/"+strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+		}
+	case []jsonMember:
+		for _, name := range s.Required {
+			if !hasJSONMember(value, name) {
+				return v.errorf(n, pointer, "missing required property %q", name)
+			}
+		}
+		for _, m := range value {
+			memberPointer := pointer + "/" + escapeJSONPointer(m.key)
+			if prop, ok := s.Properties[m.key]; ok {
+				if err := v.validate(prop, m.value, memberPointer); err != nil {
+					return err
+				}
+				continue
+			}
+			if s.noAdditional {
+				return v.errorf(m.value, memberPointer, "unknown property %q", m.key)
+			}
+			if s.additionalSchema != nil {
+				if err := v.validate(s.additionalSchema, m.value, memberPointer); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func hasJSONMember(members []jsonMember, key string) bool {
+	for _, m := range members {
+		if m.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeJSONPointer(s string) string {
+	s = strings.ReplaceAll(s, "~", "~0")
+	return strings.ReplaceAll(s, "/", "~1")
+}
+
+func jsonString(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+// validateDefinition checks the definition data against the schema.
+// It returns a *json.SyntaxError for malformed data
+// and a *SchemaError for the schema violations.
+func validateDefinition(schema *jsonSchema, data []byte) error {
+	root, err := parseJSONTree(data)
+	if err != nil {
+		return err
+	}
+	if schema == nil {
+		return nil
+	}
+	v := schemaValidator{root: schema, data: data}
+	return v.validate(schema, root, "")
+}
@@ -0,0 +1,126 @@
+package resource
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testQuestSchema = `{
+	"type": "object",
+	"required": ["id", "rewards"],
+	"additionalProperties": false,
+	"properties": {
+		"id": {"type": "string", "pattern": "^[a-z_]+$", "minLength": 2, "maxLength": 16},
+		"kind": {"enum": ["main", "side"]},
+		"version": {"const": 1},
+		"level": {"type": "integer", "minimum": 1, "maximum": 50},
+		"weight": {"type": ["number", "null"]},
+		"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 2},
+		"rewards": {"type": "array", "items": {"$ref": "#/$defs/reward"}},
+		"extra": {"type": "object", "additionalProperties": {"type": "boolean"}},
+		"next": {"$ref": "#"}
+	},
+	"$defs": {
+		"reward": {
+			"type": "object",
+			"required": ["gold"],
+			"properties": {"gold": {"type": "integer", "minimum": 0}}
+		}
+	}
+}`
+
+func TestValidateDefinition(t *testing.T) {
+	schema, err := parseJSONSchema([]byte(testQuestSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid := []string{
+		`{"id": "intro", "rewards": []}`,
+		`{"id": "intro", "rewards": [{"gold": 10}, {"gold": 0, "note": "any"}]}`,
+		`{"id": "intro", "rewards": [], "kind": "side", "version": 1, "level": 50}`,
+		`{"id": "intro", "rewards": [], "weight": 0.5}`,
+		`{"id": "intro", "rewards": [], "weight": null}`,
+		`{"id": "intro", "rewards": [], "tags": ["a", "b"]}`,
+		`{"id": "intro", "rewards": [], "extra": {"hidden": true}}`,
+		`{"id": "intro", "rewards": [], "next": {"id": "outro", "rewards": []}}`,
+	}
+	for _, data := range valid {
+		if err := validateDefinition(schema, []byte(data)); err != nil {
+			t.Errorf("validate %s: unexpected error: %v", data, err)
+		}
+	}
+
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`[]`, "/ (line 1, column 1): expected object, found array"},
+		{`{"rewards": []}`, `/ (line 1, column 1): missing required property "id"`},
+		{`{"id": "intro", "rewards": [], "x": 1}`, `/x (line 1, column 37): unknown property "x"`},
+		{`{"id": 1, "rewards": []}`, "/id (line 1, column 8): expected string, found integer"},
+		{`{"id": "Intro", "rewards": []}`, `/id (line 1, column 8): "Intro" doesn't match "^[a-z_]+$"`},
+		{`{"id": "a", "rewards": []}`, "/id (line 1, column 8): string is shorter than 2"},
+		{`{"id": "abcdefghijklmnopq", "rewards": []}`, "/id (line 1, column 8): string is longer than 16"},
+		{`{"id": "intro", "rewards": [], "kind": "daily"}`, `/kind (line 1, column 40): value should be one of "main", "side"`},
+		{`{"id": "intro", "rewards": [], "version": 2}`, "/version (line 1, column 43): value should be 1"},
+		{`{"id": "intro", "rewards": [], "level": 1.5}`, "/level (line 1, column 41): expected integer, found number"},
+		{`{"id": "intro", "rewards": [], "level": 0}`, "/level (line 1, column 41): 0 is less than minimum 1"},
+		{`{"id": "intro", "rewards": [], "level": 51}`, "/level (line 1, column 41): 51 is greater than maximum 50"},
+		{`{"id": "intro", "rewards": [], "weight": "x"}`, "/weight (line 1, column 42): expected number or null, found string"},
+		{`{"id": "intro", "rewards": [], "tags": []}`, "/tags (line 1, column 40): array has less than 1 items"},
+		{`{"id": "intro", "rewards": [], "tags": ["a", "b", "c"]}`, "/tags (line 1, column 40): array has more than 2 items"},
+		{`{"id": "intro", "rewards": [], "tags": ["a", 1]}`, "/tags/1 (line 1, column 46): expected string, found integer"},
+		{`{"id": "intro", "rewards": [], "extra": {"a/b": 1}}`, "/extra/a~1b (line 1, column 49): expected boolean, found integer"},
+		{"{\n  \"id\": \"intro\",\n  \"rewards\": [\n    {\"gold\": -1}\n  ]\n}", "/rewards/0/gold (line 4, column 14): -1 is less than minimum 0"},
+		{`{"id": "intro", "rewards": [{}]}`, `/rewards/0 (line 1, column 29): missing required property "gold"`},
+		{`{"id": "intro", "rewards": [], "next": {"id": "x"}}`, `/next (line 1, column 40): missing required property "rewards"`},
+	}
+	for _, test := range tests {
+		err := validateDefinition(schema, []byte(test.data))
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Errorf("validate %s: expected a schema error, got %v", test.data, err)
+			continue
+		}
+		if have := schemaErr.Error(); have != test.want {
+			t.Errorf("validate %s: error mismatch:\nhave: %q\nwant: %q", test.data, have, test.want)
+		}
+	}
+}
+
+func TestValidateDefinitionSyntax(t *testing.T) {
+	for _, data := range []string{`{`, `{"a": 1} x`, `{"a" 1}`, ``} {
+		if err := validateDefinition(nil, []byte(data)); err == nil {
+			t.Errorf("validate %q: expected a syntax error", data)
+		}
+	}
+	if err := validateDefinition(nil, []byte(`{"any": ["thing"]}`)); err != nil {
+		t.Errorf("validate without a schema: unexpected error: %v", err)
+	}
+}
+
+func TestParseJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`{"type": "float"}`, `#: unknown type "float"`},
+		{`{"type": 1}`, "#: type should be a string or an array of strings"},
+		{`{"pattern": "("}`, "#: pattern: error parsing regexp"},
+		{`{"additionalProperties": 1}`, "#: additionalProperties should be a boolean or a schema"},
+		{`{"$ref": "other.json#/a"}`, `#: unsupported $ref "other.json#/a"`},
+		{`{"$ref": "#/$defs/missing"}`, `#: unresolved $ref "#/$defs/missing"`},
+		{`{"properties": {"a": null}}`, "#/properties/a: schema is null"},
+		{`{"properties": {"a": {"items": {"type": "int"}}}}`, `#/properties/a/items: unknown type "int"`},
+		{`{"definitions": {"a": {"$ref": "#/definitions/b"}}}`, `#/definitions/a: unresolved $ref "#/definitions/b"`},
+		{`[]`, "cannot unmarshal array"},
+	}
+	for _, test := range tests {
+		_, err := parseJSONSchema([]byte(test.schema))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("parse %s: error mismatch:\nhave: %v\nwant: %q", test.schema, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package resource
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// sdfMaxAtlasSize is a max width and height of the generated SDF atlas.
+// Larger textures are not supported by some GPUs.
+const sdfMaxAtlasSize = 4096
+
+// generateSDFFont rasterizes the SDF font glyphs.
+// The result Atlas is not set: the atlas is returned as an alpha mask,
+// so it can be stored in the disk cache (see newSDFAtlas).
+func generateSDFFont(tt *opentype.Font, info SDFFontInfo) (SDFFont, *image.Alpha, error) {
+	face, err := opentype.NewFace(tt, &opentype.FaceOptions{
+		Size:    float64(info.Size),
+		DPI:     fontDPI,
+		Hinting: font.HintingNone,
+	})
+	if err != nil {
+		return SDFFont{}, nil, err
+	}
+	defer face.Close()
+
+	spread := info.Spread
+	if spread == 0 {
+		spread = info.Size / 8
+		if spread == 0 {
+			spread = 1
+		}
+	}
+	glyphs := info.Glyphs
+	if glyphs == "" {
+		glyphs = ASCIIGlyphs
+	}
+
+	type glyphField struct {
+		r       rune
+		offset  image.Point
+		advance fixed.Int26_6
+		field   *image.Alpha
+	}
+	var fields []glyphField
+	var sizes []image.Point
+	seen := make(map[rune]struct{})
+	for _, r := range glyphs {
+		if _, ok := seen[r]; ok {
+			continue
+		}
+		seen[r] = struct{}{}
+		dr, mask, maskp, advance, ok := face.Glyph(fixed.Point26_6{}, r)
+		if !ok {
+			continue
+		}
+		field := distanceField(dr, mask, maskp, spread)
+		fields = append(fields, glyphField{
+			r:       r,
+			offset:  dr.Min.Sub(image.Pt(spread, spread)),
+			advance: advance,
+			field:   field,
+		})
+		sizes = append(sizes, field.Rect.Size())
+	}
+	rects, atlasSize, err := packSDFGlyphs(sizes, sdfMaxAtlasSize)
+	if err != nil {
+		return SDFFont{}, nil, err
+	}
+
+	result := SDFFont{
+		Glyphs:     make(map[rune]SDFGlyph, len(fields)),
+		Spread:     spread,
+		Ascent:     fixedToFloat(face.Metrics().Ascent),
+		Descent:    fixedToFloat(face.Metrics().Descent),
+		LineHeight: fixedToFloat(face.Metrics().Height),
+	}
+	atlas := image.NewAlpha(image.Rectangle{Max: atlasSize})
+	for i, f := range fields {
+		result.Glyphs[f.r] = SDFGlyph{
+			Rect:    rects[i],
+			Offset:  f.offset,
+			Advance: fixedToFloat(f.advance),
+		}
+		draw.Draw(atlas, rects[i], f.field, f.field.Rect.Min, draw.Src)
+	}
+	return result, atlas, nil
+}
+
+// packSDFGlyphs lays out the glyph fields using a simple shelf packing.
+//
+// The atlas width is a power of two that makes the atlas roughly square,
+// so it grows along with the glyph count.
+// It's an error if the atlas doesn't fit into maxSize*maxSize.
+func packSDFGlyphs(sizes []image.Point, maxSize int) ([]image.Rectangle, image.Point, error) {
+	area, maxWidth := 0, 0
+	for _, size := range sizes {
+		area += size.X * size.Y
+		if size.X > maxWidth {
+			maxWidth = size.X
+		}
+	}
+	width := 1
+	for width*width < area || width < maxWidth {
+		width *= 2
+	}
+
+	rects := make([]image.Rectangle, len(sizes))
+	x, y, rowHeight := 0, 0, 0
+	for i, size := range sizes {
+		if x+size.X > width {
+			x = 0
+			y += rowHeight
+			rowHeight = 0
+		}
+		rects[i] = image.Rectangle{Min: image.Pt(x, y), Max: image.Pt(x+size.X, y+size.Y)}
+		x += size.X
+		if size.Y > rowHeight {
+			rowHeight = size.Y
+		}
+	}
+	height := y + rowHeight
+	if width > maxSize || height > maxSize {
+		return nil, image.Point{}, fmt.Errorf("%d glyphs don't fit into the %dx%d atlas", len(sizes), maxSize, maxSize)
+	}
+	if height == 0 {
+		// No glyphs were rendered; use a minimal valid image.
+		height = 1
+	}
+	return rects, image.Pt(width, height), nil
+}
+
+// newSDFAtlas creates the SDF font atlas texture from its alpha mask.
+// The glyphs are white, so they can be tinted with ColorScale.
+func newSDFAtlas(mask *image.Alpha) *ebiten.Image {
+	atlas := image.NewNRGBA(mask.Rect)
+	draw.DrawMask(atlas, atlas.Rect, image.NewUniform(color.White), image.Point{}, mask, mask.Rect.Min, draw.Src)
+	return ebiten.NewImageFromImage(atlas)
+}
+
+// distanceField computes a signed distance field for the glyph mask.
+// The result is padded by spread pixels on every side.
+//
+// It's a brute force implementation, but it's good enough
+// for the glyph sizes that are used for the SDF fonts.
+func distanceField(dr image.Rectangle, mask image.Image, maskp image.Point, spread int) *image.Alpha {
+	w := dr.Dx() + spread*2
+	h := dr.Dy() + spread*2
+	inside := make([]bool, w*h)
+	for y := 0; y < dr.Dy(); y++ {
+		for x := 0; x < dr.Dx(); x++ {
+			_, _, _, a := mask.At(maskp.X+x, maskp.Y+y).RGBA()
+			inside[(y+spread)*w+(x+spread)] = a >= 0x8000
+		}
+	}
+
+	field := image.NewAlpha(image.Rect(0, 0, w, h))
+	maxDist := float64(spread)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			in := inside[y*w+x]
+			dist := maxDist
+			for dy := -spread; dy <= spread; dy++ {
+				yy := y + dy
+				if yy < 0 || yy >= h {
+					// Out of bounds pixels are always "outside".
+					if in {
+						dist = math.Min(dist, math.Abs(float64(dy)))
+					}
+					continue
+				}
+				for dx := -spread; dx <= spread; dx++ {
+					xx := x + dx
+					other := false
+					if xx >= 0 && xx < w {
+						other = inside[yy*w+xx]
+					}
+					if other != in {
+						dist = math.Min(dist, math.Hypot(float64(dx), float64(dy)))
+					}
+				}
+			}
+			v := 0.5 - dist/(2*maxDist)
+			if in {
+				v = 0.5 + dist/(2*maxDist)
+			}
+			field.Pix[y*field.Stride+x] = uint8(math.Round(v * 0xff))
+		}
+	}
+	return field
+}
+
+func fixedToFloat(x fixed.Int26_6) float64 {
+	return float64(x) / 64
+}
@@ -0,0 +1,105 @@
+package resource
+
+import (
+	"image"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+func TestPackSDFGlyphs(t *testing.T) {
+	repeat := func(n int, size image.Point) []image.Point {
+		sizes := make([]image.Point, n)
+		for i := range sizes {
+			sizes[i] = size
+		}
+		return sizes
+	}
+
+	tests := []struct {
+		name  string
+		sizes []image.Point
+		want  image.Point
+	}{
+		{"empty", nil, image.Pt(1, 1)},
+		{"single", []image.Point{{10, 12}}, image.Pt(16, 12)},
+		{"wide glyph", []image.Point{{4, 4}, {100, 2}}, image.Pt(128, 4)},
+		{"few glyphs", repeat(4, image.Pt(10, 10)), image.Pt(32, 20)},
+		{"many glyphs", repeat(1000, image.Pt(10, 10)), image.Pt(512, 200)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rects, size, err := packSDFGlyphs(test.sizes, sdfMaxAtlasSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if size != test.want {
+				t.Fatalf("atlas size mismatch: have %v, want %v", size, test.want)
+			}
+			bounds := image.Rectangle{Max: size}
+			for i, r := range rects {
+				if r.Size() != test.sizes[i] {
+					t.Fatalf("glyph %d: size mismatch: have %v, want %v", i, r.Size(), test.sizes[i])
+				}
+				if !r.In(bounds) {
+					t.Fatalf("glyph %d: %v is outside of the atlas", i, r)
+				}
+				for j := 0; j < i; j++ {
+					if r.Overlaps(rects[j]) {
+						t.Fatalf("glyphs %d and %d overlap", j, i)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPackSDFGlyphsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		sizes []image.Point
+	}{
+		{"too wide", []image.Point{{65, 1}}},
+		{"too many", make([]image.Point, 65)},
+	}
+	for i := range tests[1].sizes {
+		tests[1].sizes[i] = image.Pt(8, 64)
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, err := packSDFGlyphs(test.sizes, 64)
+			if err == nil || !strings.Contains(err.Error(), "don't fit into the 64x64 atlas") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestSDFFontMetrics(t *testing.T) {
+	tt, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	face, err := opentype.NewFace(tt, &opentype.FaceOptions{Size: 16, DPI: fontDPI})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer face.Close()
+
+	f, _, err := generateSDFFont(tt, SDFFontInfo{Size: 16, Glyphs: "A"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The SDF font should match the Font metrics of the same size.
+	if have, want := f.LineHeight, fixedToFloat(face.Metrics().Height); have != want {
+		t.Fatalf("line height mismatch: have %v, want %v", have, want)
+	}
+	advance, _ := face.GlyphAdvance('A')
+	if have, want := f.Glyphs['A'].Advance, fixedToFloat(advance); have != want {
+		t.Fatalf("advance mismatch: have %v, want %v", have, want)
+	}
+}
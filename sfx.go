@@ -0,0 +1,177 @@
+package resource
+
+import (
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// SoundPlayer plays audio resources while limiting the number of
+// simultaneously playing instances of the same sound.
+//
+// When a sound reaches its instances limit, the oldest playing instance
+// is stopped and reused (the "steal oldest" policy).
+// This prevents the audio clipping when a lot of identical sounds are
+// triggered during a single frame (like 30 coins being collected at once).
+//
+// SoundPlayer creates the extra players via Loader.NewAudioPlayer,
+// so it works best with the in-memory WAV resources.
+// These players are closed when their Audio resource is unloaded.
+type SoundPlayer struct {
+	loader      *Loader
+	unsubscribe func()
+
+	maxInstances   int
+	instanceLimits map[AudioID]int
+
+	pools map[AudioID]*soundPool
+
+	// playSeq is used to determine the oldest playing instance.
+	playSeq uint64
+}
+
+type soundPool struct {
+	players []*audio.Player
+	started []uint64
+}
+
+// NewSoundPlayer creates a sound player that allows up to
+// maxInstances simultaneously playing instances of every sound.
+//
+// The sound player subscribes to the loader events (see Loader.Subscribe),
+// call Close when it's not needed anymore.
+func NewSoundPlayer(l *Loader, maxInstances int) *SoundPlayer {
+	if maxInstances <= 0 {
+		panic("maxInstances should be positive")
+	}
+	p := &SoundPlayer{
+		loader:         l,
+		maxInstances:   maxInstances,
+		instanceLimits: make(map[AudioID]int),
+		pools:          make(map[AudioID]*soundPool),
+	}
+	p.unsubscribe = l.Subscribe(func(e Event) {
+		if isAudioUnload(e) {
+			p.dropPool(AudioID(e.Ref.ID))
+		}
+	})
+	return p
+}
+
+// Close closes all players created by this sound player
+// and unsubscribes it from the loader events.
+// The cached Audio.Player objects are owned by the loader,
+// they're not closed by this method.
+func (p *SoundPlayer) Close() {
+	p.unsubscribe()
+	for id := range p.pools {
+		p.dropPool(id)
+	}
+}
+
+// SetMaxInstances overrides the instances limit for the given sound.
+func (p *SoundPlayer) SetMaxInstances(id AudioID, n int) {
+	if n <= 0 {
+		panic("max instances should be positive")
+	}
+	p.instanceLimits[p.loader.AudioRegistry.resolve(id)] = n
+}
+
+// Play starts playing the sound from the beginning.
+// The player volume is set to the Audio.Volume value.
+//
+// It returns the player that is used to play this sound instance.
+func (p *SoundPlayer) Play(id AudioID) *audio.Player {
+	a := p.loader.LoadAudio(id)
+	player := p.acquire(a)
+	p.playSeq++
+	player.SetVolume(a.Volume)
+	if err := player.Rewind(); err != nil {
+		panic(err.Error())
+	}
+	player.Play()
+	return player
+}
+
+// Stop stops all playing instances of the given sound.
+func (p *SoundPlayer) Stop(id AudioID) {
+	pool := p.pools[p.loader.AudioRegistry.resolve(id)]
+	if pool == nil {
+		return
+	}
+	for _, player := range pool.players {
+		player.Pause()
+	}
+}
+
+func (p *SoundPlayer) acquire(a Audio) *audio.Player {
+	pool := p.pools[a.ID]
+	if pool != nil && pool.players[0] != a.Player {
+		// The cached player was released and created again
+		// (see Loader.ReleaseAudioPlayer).
+		p.dropPool(a.ID)
+		pool = nil
+	}
+	if pool == nil {
+		// The cached loader player is used as the first instance.
+		pool = &soundPool{
+			players: []*audio.Player{a.Player},
+			started: []uint64{0},
+		}
+		p.pools[a.ID] = pool
+	}
+	limit := p.maxInstances
+	if n, ok := p.instanceLimits[a.ID]; ok {
+		limit = n
+	}
+	return pool.acquire(p.playSeq, limit, func() *audio.Player {
+		return p.loader.NewAudioPlayer(a.ID)
+	})
+}
+
+// dropPool closes the extra players of the sound.
+// The first pool player is the cached Audio.Player, it's closed by the loader.
+func (p *SoundPlayer) dropPool(id AudioID) {
+	pool := p.pools[id]
+	if pool == nil {
+		return
+	}
+	closePlayers(pool.players[1:])
+	delete(p.pools, id)
+}
+
+// isAudioUnload reports whether e is an Audio resource unload event.
+func isAudioUnload(e Event) bool {
+	return e.Ref.Kind == KindAudio && (e.Type == EventUnload || e.Type == EventEvict)
+}
+
+func closePlayers(players []*audio.Player) {
+	for _, player := range players {
+		player.Close()
+	}
+}
+
+// acquire returns a player that is not playing right now.
+// If there is no such player, a new player is created unless the
+// pool size reached the limit; in that case, the oldest playing
+// instance is returned.
+func (pool *soundPool) acquire(seq uint64, limit int, newPlayer func() *audio.Player) *audio.Player {
+	oldest := 0
+	for i, player := range pool.players {
+		if !player.IsPlaying() {
+			pool.started[i] = seq
+			return player
+		}
+		if pool.started[i] < pool.started[oldest] {
+			oldest = i
+		}
+	}
+
+	if len(pool.players) < limit {
+		player := newPlayer()
+		pool.players = append(pool.players, player)
+		pool.started = append(pool.started, seq)
+		return player
+	}
+
+	pool.started[oldest] = seq
+	return pool.players[oldest]
+}
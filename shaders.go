@@ -0,0 +1,47 @@
+package resource
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+)
+
+// shaderUniforms returns the names of the uniform variables
+// declared in the Kage shader source.
+//
+// Kage uses a Go syntax, so we can use the Go parser to collect
+// the top-level var declarations.
+// The source is not type-checked here, ebiten.NewShader does that.
+func shaderUniforms(src []byte) (map[string]struct{}, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+	if err != nil {
+		return nil, err
+	}
+	uniforms := make(map[string]struct{})
+	for _, decl := range f.Decls {
+		decl, ok := decl.(*ast.GenDecl)
+		if !ok || decl.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range decl.Specs {
+			for _, name := range spec.(*ast.ValueSpec).Names {
+				uniforms[name.Name] = struct{}{}
+			}
+		}
+	}
+	return uniforms, nil
+}
+
+func validateShaderUniforms(src []byte, values map[string]any) error {
+	uniforms, err := shaderUniforms(src)
+	if err != nil {
+		return err
+	}
+	for name := range values {
+		if _, ok := uniforms[name]; !ok {
+			return fmt.Errorf("shader has no %s uniform", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package resource
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type testShaderReader struct {
+	io.Reader
+	closed *int
+}
+
+func (r testShaderReader) Close() error {
+	*r.closed++
+	return nil
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("read failure") }
+
+func TestCompileAllShadersReadError(t *testing.T) {
+	l := NewLoader(nil)
+	opened := 0
+	closed := 0
+	l.OpenAssetFunc = func(path string) io.ReadCloser {
+		opened++
+		if path == "broken.go" {
+			return testShaderReader{Reader: failingReader{}, closed: &closed}
+		}
+		return testShaderReader{Reader: strings.NewReader("package main"), closed: &closed}
+	}
+	l.ShaderRegistry.Set(1, ShaderInfo{Path: "broken.go"})
+	l.ShaderRegistry.Set(2, ShaderInfo{Path: "lib.go", LibraryPaths: []string{"util.go"}})
+
+	var err error
+	func() {
+		defer func() {
+			err, _ = recover().(error)
+		}()
+		l.CompileAllShaders(true)
+	}()
+	if !errors.Is(err, ErrOpen) {
+		t.Fatalf("expected a read error, got %v", err)
+	}
+	if opened != 3 || closed != opened {
+		t.Fatalf("opened %d readers, closed %d", opened, closed)
+	}
+	if l.IsLoaded(ShaderID(1).Ref()) || l.IsLoaded(ShaderID(2).Ref()) {
+		t.Fatal("a shader is loaded after a failed read")
+	}
+}
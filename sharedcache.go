@@ -0,0 +1,86 @@
+package resource
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"io"
+	"sync"
+)
+
+// SharedCache is a decoded data cache that can be shared between several loaders.
+// It's useful for the tools that run several loaders at once (like an editor
+// preview and the game runtime): the same image is decoded only once.
+//
+// The entries are keyed by the resource content hash,
+// so the loaders don't need to agree on the resource IDs or paths.
+//
+// Only the CPU-side data is shared: every loader still creates
+// its own textures (so unloading the resource in one loader
+// doesn't affect the others).
+// The cached images must be treated as immutable.
+//
+// SharedCache is safe for a concurrent use.
+// The zero value is ready to use.
+type SharedCache struct {
+	mu     sync.Mutex
+	images map[[sha256.Size]byte]*sharedImage
+}
+
+type sharedImage struct {
+	once sync.Once
+	img  image.Image
+	err  error
+}
+
+// Len returns the number of cached entries.
+func (c *SharedCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.images)
+}
+
+// Reset removes all cached entries.
+func (c *SharedCache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.images = nil
+}
+
+// decodeImage returns a decoded image for the given content.
+// If several goroutines request the same content at once,
+// only one of them decodes it while the others wait for the result.
+func (c *SharedCache) decodeImage(data []byte) (image.Image, error) {
+	key := sha256.Sum256(data)
+	c.mu.Lock()
+	if c.images == nil {
+		c.images = make(map[[sha256.Size]byte]*sharedImage)
+	}
+	e, ok := c.images[key]
+	if !ok {
+		e = &sharedImage{}
+		c.images[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.img, _, e.err = image.Decode(bytes.NewReader(data))
+	})
+	if e.err != nil {
+		// Don't cache the failures: the next attempt may read different data.
+		c.mu.Lock()
+		if c.images[key] == e {
+			delete(c.images, key)
+		}
+		c.mu.Unlock()
+	}
+	return e.img, e.err
+}
+
+func (l *Loader) decodeSharedImage(r io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return l.SharedCache.decodeImage(data)
+}
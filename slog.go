@@ -0,0 +1,52 @@
+//go:build go1.21
+
+package resource
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SetLogger makes the loader log its events (see Subscribe) with the logger,
+// so the asset loading shows up in the same logs as the rest of the game.
+//
+// The load, unload, reload and evict events are logged with the debug level,
+// the deprecated path usages (see PathRedirects) are logged with the warn level
+// and the errors are logged with the error level.
+// Every record has the kind, id and path attributes;
+// the duration and bytes attributes are added when they're known.
+//
+// A nil logger disables the logging.
+// This method requires Go 1.21 (the log/slog package).
+func (l *Loader) SetLogger(logger *slog.Logger) {
+	if l.unsubscribeLogger != nil {
+		l.unsubscribeLogger()
+		l.unsubscribeLogger = nil
+	}
+	if logger == nil {
+		return
+	}
+	l.unsubscribeLogger = l.Subscribe(func(e Event) {
+		attrs := []slog.Attr{
+			slog.String("kind", e.Ref.Kind.String()),
+			slog.Int("id", e.Ref.ID),
+			slog.String("path", e.Path),
+		}
+		if e.Duration != 0 {
+			attrs = append(attrs, slog.Duration("duration", e.Duration))
+		}
+		if e.Bytes != 0 {
+			attrs = append(attrs, slog.Int64("bytes", e.Bytes))
+		}
+		level := slog.LevelDebug
+		if e.NewPath != "" {
+			level = slog.LevelWarn
+			attrs = append(attrs, slog.String("new_path", e.NewPath))
+		}
+		if e.Err != nil {
+			level = slog.LevelError
+			attrs = append(attrs, slog.String("error", e.Err.Error()))
+		}
+		logger.LogAttrs(context.Background(), level, "resource "+e.Type.String(), attrs...)
+	})
+}
@@ -0,0 +1,52 @@
+package resource
+
+// softEntry is a soft-cached resource.
+type softEntry struct {
+	ref  Ref
+	size int64
+}
+
+// DropSoftCache unloads all soft-cached resources
+// (see RawInfo.Soft and ConfigInfo.Soft).
+// They will be read again on the next Load.
+//
+// It can be called when the game is running low on memory
+// or after a level generation step that needed the data briefly.
+func (l *Loader) DropSoftCache() {
+	l.trimSoftCache(0, false)
+}
+
+func (l *Loader) addSoft(ref Ref, size int64) {
+	for i, e := range l.softEntries {
+		if e.ref == ref {
+			// The resource was unloaded and then loaded again.
+			l.softSize -= e.size
+			l.softEntries = append(l.softEntries[:i], l.softEntries[i+1:]...)
+			break
+		}
+	}
+	l.softEntries = append(l.softEntries, softEntry{ref: ref, size: size})
+	l.softSize += size
+	l.trimSoftCache(l.SoftCacheLimit, true)
+}
+
+// trimSoftCache unloads the oldest soft-cached resources
+// until their total size fits the limit.
+// If keepLast is true, the most recently loaded resource is never unloaded.
+func (l *Loader) trimSoftCache(limit int64, keepLast bool) {
+	kept := l.softEntries[:0]
+	for i, e := range l.softEntries {
+		isLast := i == len(l.softEntries)-1
+		if l.softSize <= limit || (keepLast && isLast) {
+			kept = append(kept, e)
+			continue
+		}
+		if !l.IsLoaded(e.ref) || l.unload(e.ref, EventEvict) {
+			l.softSize -= e.size
+			continue
+		}
+		// The resource is required by other loaded resources.
+		kept = append(kept, e)
+	}
+	l.softEntries = kept
+}
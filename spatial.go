@@ -0,0 +1,160 @@
+package resource
+
+import (
+	"math"
+	"sync/atomic"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+)
+
+// Spatializer is a tiny positional audio helper.
+//
+// Every sound is played at some world position; its volume and stereo pan
+// are computed relative to the listener position.
+// The sounds that are closer to the listener are louder,
+// the sounds to the left of the listener are panned to the left, etc.
+//
+// Like SoundPlayer, it limits the number of simultaneously
+// playing instances of the same sound (with a "steal oldest" policy).
+type Spatializer struct {
+	// MaxDistance is a distance at which the sound becomes silent.
+	// The volume attenuation is linear.
+	MaxDistance float64
+
+	// PanDistance is a horizontal distance at which the sound
+	// is panned to the left (or right) channel completely.
+	PanDistance float64
+
+	loader      *Loader
+	unsubscribe func()
+
+	listenerX float64
+	listenerY float64
+
+	maxInstances int
+
+	pools map[AudioID]*soundPool
+	pans  map[*audio.Player]*panControl
+
+	playSeq uint64
+}
+
+// NewSpatializer creates a positional audio player that allows up to
+// maxInstances simultaneously playing instances of every sound.
+//
+// The default MaxDistance is 1000 and the default PanDistance is 500.
+//
+// Like SoundPlayer, the spatializer closes the players of the unloaded
+// sounds; call Close when it's not needed anymore.
+func NewSpatializer(l *Loader, maxInstances int) *Spatializer {
+	if maxInstances <= 0 {
+		panic("maxInstances should be positive")
+	}
+	s := &Spatializer{
+		MaxDistance:  1000,
+		PanDistance:  500,
+		loader:       l,
+		maxInstances: maxInstances,
+		pools:        make(map[AudioID]*soundPool),
+		pans:         make(map[*audio.Player]*panControl),
+	}
+	s.unsubscribe = l.Subscribe(func(e Event) {
+		if isAudioUnload(e) {
+			s.dropPool(AudioID(e.Ref.ID))
+		}
+	})
+	return s
+}
+
+// Close closes all players created by this spatializer
+// and unsubscribes it from the loader events.
+func (s *Spatializer) Close() {
+	s.unsubscribe()
+	for id := range s.pools {
+		s.dropPool(id)
+	}
+}
+
+func (s *Spatializer) dropPool(id AudioID) {
+	pool := s.pools[id]
+	if pool == nil {
+		return
+	}
+	for _, player := range pool.players {
+		delete(s.pans, player)
+	}
+	closePlayers(pool.players)
+	delete(s.pools, id)
+}
+
+// SetListener sets the listener position (usually, the camera center).
+// It only affects the sounds that are played after this call.
+func (s *Spatializer) SetListener(x, y float64) {
+	s.listenerX = x
+	s.listenerY = y
+}
+
+// PlayAt starts playing the sound at the given world position.
+// The resulting volume is the Audio.Volume multiplied by the distance attenuation.
+//
+// It returns the player that is used to play this sound instance.
+func (s *Spatializer) PlayAt(id AudioID, x, y float64) *audio.Player {
+	a := s.loader.LoadAudio(id)
+	pool := s.pools[a.ID]
+	if pool == nil {
+		pool = &soundPool{}
+		s.pools[a.ID] = pool
+	}
+	player := pool.acquire(s.playSeq, s.maxInstances, func() *audio.Player {
+		pan := &panControl{}
+		stream := newEffectStream(s.loader.newAudioStream(a), pan.apply, nil)
+		player, err := s.loader.audioContext.NewPlayer(stream)
+		if err != nil {
+			panic(err.Error())
+		}
+		s.pans[player] = pan
+		return player
+	})
+	s.playSeq++
+
+	dx := x - s.listenerX
+	dy := y - s.listenerY
+	attenuation := 1 - math.Hypot(dx, dy)/s.MaxDistance
+	if attenuation < 0 {
+		attenuation = 0
+	}
+	pan := dx / s.PanDistance
+	pan = math.Max(-1, math.Min(1, pan))
+
+	s.pans[player].set(pan)
+	player.SetVolume(a.Volume * attenuation)
+	if err := player.Rewind(); err != nil {
+		panic(err.Error())
+	}
+	player.Play()
+	return player
+}
+
+// panControl is a stereo pan value that can be changed
+// while the audio stream is being played.
+type panControl struct {
+	// bits is a float64 pan value in [-1, 1] range.
+	// It's accessed atomically as the audio streams are read
+	// from a separate goroutine.
+	bits uint64
+}
+
+func (p *panControl) set(pan float64) {
+	atomic.StoreUint64(&p.bits, math.Float64bits(pan))
+}
+
+func (p *panControl) apply(channel int, x float64) float64 {
+	pan := math.Float64frombits(atomic.LoadUint64(&p.bits))
+	// Constant power panning: the total loudness stays
+	// the same regardless of the pan value.
+	angle := (pan + 1) * math.Pi / 4
+	if channel == 0 {
+		return x * math.Cos(angle) * math.Sqrt2
+	}
+	return x * math.Sin(angle) * math.Sqrt2
+}
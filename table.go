@@ -0,0 +1,151 @@
+package resource
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+func parseTable(r io.Reader, info TableInfo) (Table, error) {
+	var records [][]string
+	var err error
+	if info.Comma == 0 && strings.HasSuffix(info.Path, ".tsv") {
+		records, err = readTSV(r)
+	} else {
+		cr := csv.NewReader(r)
+		cr.Comma = info.Comma
+		if cr.Comma == 0 {
+			cr.Comma = ','
+		}
+		records, err = cr.ReadAll()
+	}
+	if err != nil {
+		return Table{}, err
+	}
+	if len(records) == 0 {
+		return Table{}, errors.New("missing header record")
+	}
+	t := Table{
+		Columns:     records[0],
+		Rows:        records[1:],
+		columnIndex: make(map[string]int, len(records[0])),
+	}
+	for i, name := range t.Columns {
+		t.columnIndex[name] = i
+	}
+	return t, nil
+}
+
+// readTSV splits the TSV records on tabs.
+// Unlike CSV, TSV has no quoting: the quotes are a part of the field value.
+// The empty lines are skipped and every record must have
+// the same number of fields as the first one (like csv.Reader does).
+func readTSV(r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var records [][]string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if line == "" {
+			continue
+		}
+		record := strings.Split(line, "\t")
+		if len(records) != 0 && len(record) != len(records[0]) {
+			return nil, fmt.Errorf("record on line %d: wrong number of fields", i+1)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// DecodeTable maps the table rows to a slice of T structs.
+//
+// Every exported struct field is matched with a table column by its name.
+// A `table:"name"` field tag can be used to specify the column name explicitly;
+// a `table:"-"` tag makes the decoder ignore the field.
+// Fields without a matching column are left untouched.
+//
+// The supported field types are: string, bool, ints, uints and floats.
+// An empty cell value is decoded as a zero value.
+func DecodeTable[T any](t Table) ([]T, error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can't decode table into %s: struct type expected", typ)
+	}
+
+	type fieldMapping struct {
+		field  int
+		column int
+	}
+	var mappings []fieldMapping
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("table"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		if column := t.ColumnIndex(name); column != -1 {
+			mappings = append(mappings, fieldMapping{field: i, column: column})
+		}
+	}
+
+	result := make([]T, len(t.Rows))
+	for i, row := range t.Rows {
+		v := reflect.ValueOf(&result[i]).Elem()
+		for _, m := range mappings {
+			if err := setTableField(v.Field(m.field), row[m.column]); err != nil {
+				return nil, fmt.Errorf("row %d: column %q: %w", i+1, t.Columns[m.column], err)
+			}
+		}
+	}
+	return result, nil
+}
+
+func setTableField(field reflect.Value, s string) error {
+	if s == "" {
+		return nil
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		field.SetBool(v)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v, err := strconv.ParseInt(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(v)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v, err := strconv.ParseUint(s, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(v)
+	case reflect.Float32, reflect.Float64:
+		v, err := strconv.ParseFloat(s, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(v)
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
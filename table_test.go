@@ -0,0 +1,44 @@
+package resource
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseTable(t *testing.T) {
+	tests := []struct {
+		path  string
+		comma rune
+		data  string
+		want  [][]string
+		err   string
+	}{
+		{path: "a.csv", data: "name,hp\n\"orc, big\",10\n", want: [][]string{{"name", "hp"}, {"orc, big", "10"}}},
+		{path: "a.tsv", data: "name\thp\n\"orc\" big\t10\n", want: [][]string{{"name", "hp"}, {`"orc" big`, "10"}}},
+		{path: "a.tsv", data: "name\thp\r\n\"a\tb\"\r\n\r\n", want: [][]string{{"name", "hp"}, {`"a`, `b"`}}},
+		{path: "a.tsv", data: "name\tdesc\nx\t\"quoted\"\n", want: [][]string{{"name", "desc"}, {"x", `"quoted"`}}},
+		{path: "a.tsv", data: "name\thp\nx\n", err: "record on line 2: wrong number of fields"},
+		{path: "a.tsv", comma: ';', data: "name;hp\n\"a;b\";1\n", want: [][]string{{"name", "hp"}, {"a;b", "1"}}},
+		{path: "a.tsv", data: "", err: "missing header record"},
+	}
+
+	for _, test := range tests {
+		info := TableInfo{Path: test.path, Comma: test.comma}
+		tab, err := parseTable(strings.NewReader(test.data), info)
+		if test.err != "" {
+			if err == nil || err.Error() != test.err {
+				t.Errorf("%q: error mismatch:\nhave: %v\nwant: %s", test.data, err, test.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", test.data, err)
+			continue
+		}
+		have := append([][]string{tab.Columns}, tab.Rows...)
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%q: records mismatch:\nhave: %q\nwant: %q", test.data, have, test.want)
+		}
+	}
+}
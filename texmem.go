@@ -0,0 +1,109 @@
+package resource
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TextureMemory returns the approximate amount of the GPU memory
+// (in bytes) used by the loaded textures.
+//
+// Every texture is counted as width*height*4 bytes.
+// This includes the images, their mipmaps and derived variants
+// (like tinted images), the SDF font atlases, the composite
+// and shader images and the LUTs.
+//
+// The total is updated on every load and unload,
+// so this method is cheap to call.
+func (l *Loader) TextureMemory() int64 {
+	return l.textureMemory
+}
+
+// addTextureMemory updates the TextureMemory running total.
+func (l *Loader) addTextureMemory(delta int64) {
+	l.textureMemory += delta
+}
+
+// imageVariant returns the cached derived image (see imageVariantKey).
+// A cache hit marks the original image as recently used (see LRUCacheLimit).
+func (l *Loader) imageVariant(key imageVariantKey) (Image, bool) {
+	img, ok := l.imageVariants[key]
+	if ok {
+		l.touch(key.id.Ref())
+	}
+	return img, ok
+}
+
+// storeImageVariant caches the derived image (see imageVariantKey).
+func (l *Loader) storeImageVariant(key imageVariantKey, img Image) {
+	if old, ok := l.imageVariants[key]; ok {
+		l.addTextureMemory(-old.textureBytes())
+	}
+	l.imageVariants[key] = img
+	l.addTextureMemory(img.textureBytes())
+	l.checkTextureBudget()
+}
+
+// deleteImageVariant removes the derived image.
+// The texture is disposed only if dispose is true.
+func (l *Loader) deleteImageVariant(key imageVariantKey, dispose bool) {
+	img := l.imageVariants[key]
+	l.addTextureMemory(-img.textureBytes())
+	if dispose {
+		img.Data.Dispose()
+	}
+	delete(l.imageVariants, key)
+}
+
+// textureBytes returns the approximate image GPU memory usage.
+func (img Image) textureBytes() int64 {
+	total := textureBytes(img.Data)
+	if img.Tiled != nil {
+		total += img.Tiled.textureBytes()
+	}
+	for _, m := range img.Mipmaps {
+		total += textureBytes(m)
+	}
+	return total
+}
+
+// textures returns all GPU textures owned by the image.
+func (img Image) textures() []*ebiten.Image {
+	var textures []*ebiten.Image
+	if img.Data != nil {
+		textures = append(textures, img.Data)
+	}
+	if img.Tiled != nil {
+		textures = append(textures, img.Tiled.Tiles...)
+	}
+	return append(textures, img.Mipmaps...)
+}
+
+func textureBytes(img *ebiten.Image) int64 {
+	if img == nil {
+		return 0
+	}
+	w, h := img.Size()
+	return int64(w) * int64(h) * 4
+}
+
+// checkTextureBudget calls the TextureBudgetFunc if
+// the texture memory usage exceeds the TextureBudget.
+// The callback is called once per budget overflow:
+// the usage has to go below the budget to trigger it again.
+func (l *Loader) checkTextureBudget() {
+	if l.TextureBudget <= 0 {
+		return
+	}
+	used := l.textureMemory
+	if used <= l.TextureBudget {
+		l.textureBudgetExceeded = false
+		return
+	}
+	if l.textureBudgetExceeded {
+		return
+	}
+	l.textureBudgetExceeded = true
+	if l.TextureBudgetFunc != nil {
+		l.TextureBudgetFunc(used, l.TextureBudget)
+	}
+}
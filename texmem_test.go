@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"testing"
+)
+
+func TestTextureMemoryTotal(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, 8, 4))); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLoader(nil)
+	l.ImageRegistry.Assign(map[ImageID]ImageInfo{
+		1: {Path: "a.png", Data: buf.Bytes()},
+		2: {Path: "b.png", Data: buf.Bytes(), FrameWidth: 4, FrameHeight: 4, SplitFrames: true},
+	})
+
+	l.LoadImage(1)
+	if have, want := l.TextureMemory(), int64(8*4*4); have != want {
+		t.Fatalf("after loading image 1: have %d, want %d", have, want)
+	}
+	l.LoadImage(2)
+	// The image itself and its two 4x4 frames.
+	if have, want := l.TextureMemory(), int64(8*4*4*2+4*4*4*2); have != want {
+		t.Fatalf("after loading image 2: have %d, want %d", have, want)
+	}
+	l.Unload(ImageID(2).Ref())
+	if have, want := l.TextureMemory(), int64(8*4*4); have != want {
+		t.Fatalf("after unloading image 2: have %d, want %d", have, want)
+	}
+	l.Close()
+	if have := l.TextureMemory(); have != 0 {
+		t.Fatalf("after Close: have %d, want 0", have)
+	}
+}
+
+func TestReloadImageResize(t *testing.T) {
+	encode := func(w, h int) []byte {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	data := encode(8, 4)
+	l := NewLoader(nil)
+	l.OpenAssetFunc = func(path string) io.ReadCloser {
+		return io.NopCloser(bytes.NewReader(data))
+	}
+	l.ImageRegistry.Set(1, ImageInfo{Path: "a.png"})
+
+	l.LoadImage(1)
+	l.LoadImageTinted(1, color.RGBA{R: 0xff, A: 0xff})
+	data = encode(4, 4)
+	img := l.ReloadImage(1)
+	if w, h := img.Data.Size(); w != 4 || h != 4 {
+		t.Fatalf("reloaded image size is %dx%d", w, h)
+	}
+	if len(l.imageVariants) != 0 {
+		t.Fatalf("the image variants are not unloaded: %v", l.imageVariants)
+	}
+	if have, want := l.TextureMemory(), int64(4*4*4); have != want {
+		t.Fatalf("have %d texture bytes, want %d", have, want)
+	}
+}
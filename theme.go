@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// NineSlice is an image that is drawn with its corners intact,
+// while the edges and the center are stretched.
+// It's commonly used for the UI panels and buttons.
+type NineSlice struct {
+	Image *ebiten.Image
+
+	// Left, Top, Right and Bottom are the non-stretched border sizes.
+	Left   int
+	Top    int
+	Right  int
+	Bottom int
+}
+
+// Draw draws the slices stretched to the width*height rect onto dst.
+// The options are applied to the result as a whole.
+// A nil options value is permitted.
+func (s NineSlice) Draw(dst *ebiten.Image, width, height int, options *ebiten.DrawImageOptions) {
+	var base ebiten.DrawImageOptions
+	if options != nil {
+		base = *options
+	}
+	b := s.Image.Bounds()
+	srcX := [4]int{b.Min.X, b.Min.X + s.Left, b.Max.X - s.Right, b.Max.X}
+	srcY := [4]int{b.Min.Y, b.Min.Y + s.Top, b.Max.Y - s.Bottom, b.Max.Y}
+	dstX := [4]int{0, s.Left, width - s.Right, width}
+	dstY := [4]int{0, s.Top, height - s.Bottom, height}
+	for row := 0; row < 3; row++ {
+		for col := 0; col < 3; col++ {
+			src := image.Rect(srcX[col], srcY[row], srcX[col+1], srcY[row+1])
+			dstWidth := dstX[col+1] - dstX[col]
+			dstHeight := dstY[row+1] - dstY[row]
+			if src.Empty() || dstWidth <= 0 || dstHeight <= 0 {
+				continue
+			}
+			op := base
+			op.GeoM.Reset()
+			op.GeoM.Scale(float64(dstWidth)/float64(src.Dx()), float64(dstHeight)/float64(src.Dy()))
+			op.GeoM.Translate(float64(dstX[col]), float64(dstY[row]))
+			op.GeoM.Concat(base.GeoM)
+			dst.DrawImage(s.Image.SubImage(src).(*ebiten.Image), &op)
+		}
+	}
+}
+
+type themeImageData struct {
+	Image string `json:"image"`
+
+	// Slice is the [left, top, right, bottom] border sizes.
+	Slice [4]int `json:"slice"`
+}
+
+type themeData struct {
+	Fonts  map[string]string         `json:"fonts"`
+	Images map[string]themeImageData `json:"images"`
+	Colors map[string]string         `json:"colors"`
+}
+
+func decodeTheme(r io.Reader) (*themeData, error) {
+	var data themeData
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// resolveTheme loads the theme resources.
+// The deps are the resolved resource references.
+func (l *Loader) resolveTheme(id ThemeID, info ThemeInfo, data *themeData) (theme Theme, deps []Ref, err error) {
+	theme = Theme{
+		ID:     id,
+		Fonts:  make(map[string]Font, len(data.Fonts)),
+		Images: make(map[string]NineSlice, len(data.Images)),
+		Colors: make(map[string]color.NRGBA, len(data.Colors)),
+	}
+
+	// The keys are sorted to report the errors deterministically.
+	for _, key := range sortedKeys(data.Fonts) {
+		fontID, ok := info.Fonts[data.Fonts[key]]
+		if !ok {
+			return theme, nil, fmt.Errorf("fonts.%s: unknown font %q", key, data.Fonts[key])
+		}
+		theme.Fonts[key] = l.LoadFont(fontID)
+		deps = append(deps, fontID.Ref())
+	}
+	for _, key := range sortedKeys(data.Images) {
+		imageData := data.Images[key]
+		imageID, ok := info.Images[imageData.Image]
+		if !ok {
+			return theme, nil, fmt.Errorf("images.%s: unknown image %q", key, imageData.Image)
+		}
+		img := l.LoadImage(imageID)
+		if img.Tiled != nil {
+			return theme, nil, fmt.Errorf("images.%s: image %q is tiled", key, imageData.Image)
+		}
+		s := NineSlice{
+			Image:  img.Data,
+			Left:   imageData.Slice[0],
+			Top:    imageData.Slice[1],
+			Right:  imageData.Slice[2],
+			Bottom: imageData.Slice[3],
+		}
+		w, h := img.Data.Size()
+		if s.Left < 0 || s.Top < 0 || s.Right < 0 || s.Bottom < 0 || s.Left+s.Right > w || s.Top+s.Bottom > h {
+			return theme, nil, fmt.Errorf("images.%s: slice %v doesn't fit the %dx%d image", key, imageData.Slice, w, h)
+		}
+		theme.Images[key] = s
+		deps = append(deps, imageID.Ref())
+	}
+	for _, key := range sortedKeys(data.Colors) {
+		c, err := parseHexColor(data.Colors[key])
+		if err != nil {
+			return theme, nil, fmt.Errorf("colors.%s: %w", key, err)
+		}
+		theme.Colors[key] = c
+	}
+
+	return theme, deps, nil
+}
@@ -0,0 +1,143 @@
+package resource
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// TiledImage is an image that is split into several textures,
+// so it can exceed the max texture size supported by the GPU.
+// See ImageInfo.Tiled and Loader.MaxTextureSize.
+type TiledImage struct {
+	// Width and Height are the entire image dimensions.
+	Width  int
+	Height int
+
+	// TileSize is a max tile width and height.
+	// The last column and row tiles can be smaller.
+	TileSize int
+
+	// Columns is a number of tiles per row.
+	Columns int
+
+	// Tiles are the image parts in the row-major order.
+	Tiles []*ebiten.Image
+}
+
+// Draw draws the entire image onto dst, like dst.DrawImage would do that.
+// The options are applied to the image as a whole.
+// A nil options value is permitted.
+//
+// Note that the linear filtering could make the tile seams
+// visible when the image is scaled or rotated.
+func (t *TiledImage) Draw(dst *ebiten.Image, options *ebiten.DrawImageOptions) {
+	var base ebiten.DrawImageOptions
+	if options != nil {
+		base = *options
+	}
+	for i, tile := range t.Tiles {
+		op := base
+		op.GeoM.Reset()
+		op.GeoM.Translate(float64((i%t.Columns)*t.TileSize), float64((i/t.Columns)*t.TileSize))
+		op.GeoM.Concat(base.GeoM)
+		dst.DrawImage(tile, &op)
+	}
+}
+
+func (t *TiledImage) textureBytes() int64 {
+	var total int64
+	for _, tile := range t.Tiles {
+		total += textureBytes(tile)
+	}
+	return total
+}
+
+func (t *TiledImage) dispose() {
+	for _, tile := range t.Tiles {
+		tile.Dispose()
+	}
+}
+
+// tileRects returns the tile rectangles for the image bounds.
+func tileRects(b image.Rectangle, tileSize int) (rects []image.Rectangle, columns int) {
+	for y := b.Min.Y; y < b.Max.Y; y += tileSize {
+		for x := b.Min.X; x < b.Max.X; x += tileSize {
+			rects = append(rects, image.Rect(x, y, x+tileSize, y+tileSize).Intersect(b))
+		}
+	}
+	columns = (b.Dx() + tileSize - 1) / tileSize
+	return rects, columns
+}
+
+func newTiledImage(img image.Image, tileSize int, info ImageInfo) *TiledImage {
+	b := img.Bounds()
+	rects, columns := tileRects(b, tileSize)
+	t := &TiledImage{
+		Width:    b.Dx(),
+		Height:   b.Dy(),
+		TileSize: tileSize,
+		Columns:  columns,
+		Tiles:    make([]*ebiten.Image, len(rects)),
+	}
+	for i, r := range rects {
+		t.Tiles[i] = newEbitenImage(subImage(img, r), info)
+	}
+	return t
+}
+
+// writeTiledImage uploads the img pixels into the existing tiles.
+// It reports false if the layouts don't match.
+func writeTiledImage(t *TiledImage, img image.Image) bool {
+	b := img.Bounds()
+	if b.Dx() != t.Width || b.Dy() != t.Height {
+		return false
+	}
+	rects, _ := tileRects(b, t.TileSize)
+	for i, r := range rects {
+		if !writeEbitenImage(t.Tiles[i], subImage(img, r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// exceedsMaxTextureSize reports whether the image can't be uploaded
+// as a single texture (see Loader.MaxTextureSize).
+func (l *Loader) exceedsMaxTextureSize(img image.Image) bool {
+	if l.MaxTextureSize <= 0 {
+		return false
+	}
+	b := img.Bounds()
+	return b.Dx() > l.MaxTextureSize || b.Dy() > l.MaxTextureSize
+}
+
+// uploadImage creates the image textures.
+func (l *Loader) uploadImage(id ImageID, imageInfo ImageInfo, rawImage image.Image) Image {
+	img := Image{
+		ID:                 id,
+		DefaultFrameWidth:  imageInfo.FrameWidth,
+		DefaultFrameHeight: imageInfo.FrameHeight,
+	}
+	if l.exceedsMaxTextureSize(rawImage) {
+		if !imageInfo.Tiled {
+			b := rawImage.Bounds()
+			err := fmt.Errorf("image size %dx%d exceeds the max texture size %d (see ImageInfo.Tiled)",
+				b.Dx(), b.Dy(), l.MaxTextureSize)
+			panic(newError("upload", id.Ref(), imageInfo.Path, err))
+		}
+		img.Tiled = newTiledImage(rawImage, l.MaxTextureSize, imageInfo)
+		return img
+	}
+	if generated, ok := rawImage.(*ebiten.Image); ok && imageInfo.Mipmaps == 0 {
+		// Avoid a redundant copy of the generated texture.
+		img.Data = generated
+	} else {
+		img.Data = newEbitenImage(rawImage, imageInfo)
+	}
+	if imageInfo.Mipmaps > 0 {
+		img.Mipmaps = generateMipmaps(rawImage, imageInfo)
+	}
+	return img
+}
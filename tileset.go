@@ -0,0 +1,196 @@
+package resource
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tile describes the properties of a single tile in a TileSet.
+type Tile struct {
+	// Collision reports whether this tile is solid.
+	//
+	// For the Tiled TSX files, it's true if the tile has a "collision"
+	// bool property set to true or if it has any collision shapes.
+	Collision bool
+
+	// Terrain is a list of terrain tags (like "water" or "grass").
+	//
+	// For the Tiled TSX files, it's a comma-separated "terrain" property value.
+	Terrain []string
+
+	// Animation frames of this tile; nil for the static tiles.
+	Animation []TileFrame
+
+	// Properties contains all tile custom properties as is.
+	Properties map[string]string
+}
+
+// HasTerrain reports whether the tile has a given terrain tag.
+func (t *Tile) HasTerrain(tag string) bool {
+	for _, x := range t.Terrain {
+		if x == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// TileFrame is a single tile animation frame.
+type TileFrame struct {
+	// TileIndex is a tile set index of the tile that is shown during this frame.
+	TileIndex int
+
+	Duration time.Duration
+}
+
+func decodeTileSet(r io.Reader, path string) (TileSet, error) {
+	if strings.HasSuffix(path, ".tsx") {
+		return decodeTileSetTSX(r)
+	}
+	return decodeTileSetJSON(r)
+}
+
+type tileSetJSON struct {
+	TileWidth  int `json:"tile_width"`
+	TileHeight int `json:"tile_height"`
+	TileCount  int `json:"tile_count"`
+	Columns    int `json:"columns"`
+	Spacing    int `json:"spacing"`
+	Margin     int `json:"margin"`
+	Tiles      []struct {
+		Index     int      `json:"index"`
+		Collision bool     `json:"collision"`
+		Terrain   []string `json:"terrain"`
+		Animation []struct {
+			TileIndex int `json:"tile_index"`
+			Duration  int `json:"duration"`
+		} `json:"animation"`
+		Properties map[string]string `json:"properties"`
+	} `json:"tiles"`
+}
+
+func decodeTileSetJSON(r io.Reader) (TileSet, error) {
+	var doc tileSetJSON
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return TileSet{}, err
+	}
+	ts := TileSet{
+		TileWidth:  doc.TileWidth,
+		TileHeight: doc.TileHeight,
+		Columns:    doc.Columns,
+		Spacing:    doc.Spacing,
+		Margin:     doc.Margin,
+		Tiles:      make([]Tile, doc.TileCount),
+	}
+	for _, t := range doc.Tiles {
+		if t.Index < 0 || t.Index >= len(ts.Tiles) {
+			return TileSet{}, fmt.Errorf("tile index %d is out of range", t.Index)
+		}
+		tile := &ts.Tiles[t.Index]
+		tile.Collision = t.Collision
+		tile.Terrain = t.Terrain
+		tile.Properties = t.Properties
+		for _, f := range t.Animation {
+			tile.Animation = append(tile.Animation, TileFrame{
+				TileIndex: f.TileIndex,
+				Duration:  time.Duration(f.Duration) * time.Millisecond,
+			})
+		}
+	}
+	return ts, ts.validate()
+}
+
+type tileSetTSX struct {
+	TileWidth  int `xml:"tilewidth,attr"`
+	TileHeight int `xml:"tileheight,attr"`
+	TileCount  int `xml:"tilecount,attr"`
+	Columns    int `xml:"columns,attr"`
+	Spacing    int `xml:"spacing,attr"`
+	Margin     int `xml:"margin,attr"`
+	Tiles      []struct {
+		ID         int `xml:"id,attr"`
+		Properties []struct {
+			Name  string `xml:"name,attr"`
+			Value string `xml:"value,attr"`
+		} `xml:"properties>property"`
+		Frames []struct {
+			TileID   int `xml:"tileid,attr"`
+			Duration int `xml:"duration,attr"`
+		} `xml:"animation>frame"`
+		Shapes []struct{} `xml:"objectgroup>object"`
+	} `xml:"tile"`
+}
+
+func decodeTileSetTSX(r io.Reader) (TileSet, error) {
+	var doc tileSetTSX
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return TileSet{}, err
+	}
+	ts := TileSet{
+		TileWidth:  doc.TileWidth,
+		TileHeight: doc.TileHeight,
+		Columns:    doc.Columns,
+		Spacing:    doc.Spacing,
+		Margin:     doc.Margin,
+		Tiles:      make([]Tile, doc.TileCount),
+	}
+	for _, t := range doc.Tiles {
+		if t.ID < 0 || t.ID >= len(ts.Tiles) {
+			return TileSet{}, fmt.Errorf("tile id %d is out of range", t.ID)
+		}
+		tile := &ts.Tiles[t.ID]
+		tile.Collision = len(t.Shapes) != 0
+		if len(t.Properties) != 0 {
+			tile.Properties = make(map[string]string, len(t.Properties))
+		}
+		for _, p := range t.Properties {
+			tile.Properties[p.Name] = p.Value
+			switch p.Name {
+			case "collision":
+				v, err := strconv.ParseBool(p.Value)
+				if err != nil {
+					return TileSet{}, fmt.Errorf("tile id %d: collision property: %w", t.ID, err)
+				}
+				tile.Collision = tile.Collision || v
+			case "terrain":
+				for _, tag := range strings.Split(p.Value, ",") {
+					if tag = strings.TrimSpace(tag); tag != "" {
+						tile.Terrain = append(tile.Terrain, tag)
+					}
+				}
+			}
+		}
+		for _, f := range t.Frames {
+			tile.Animation = append(tile.Animation, TileFrame{
+				TileIndex: f.TileID,
+				Duration:  time.Duration(f.Duration) * time.Millisecond,
+			})
+		}
+	}
+	return ts, ts.validate()
+}
+
+func (ts *TileSet) validate() error {
+	if ts.TileWidth <= 0 || ts.TileHeight <= 0 {
+		return fmt.Errorf("invalid tile size %dx%d", ts.TileWidth, ts.TileHeight)
+	}
+	if ts.Columns <= 0 {
+		return fmt.Errorf("invalid columns number %d", ts.Columns)
+	}
+	if ts.Spacing < 0 || ts.Margin < 0 {
+		return fmt.Errorf("invalid spacing %d or margin %d", ts.Spacing, ts.Margin)
+	}
+	for i, t := range ts.Tiles {
+		for _, f := range t.Animation {
+			if f.TileIndex < 0 || f.TileIndex >= len(ts.Tiles) {
+				return fmt.Errorf("tile %d: animation frame tile index %d is out of range", i, f.TileIndex)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package resource
+
+import (
+	"image"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeTileSet(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+	}{
+		{
+			name: "json",
+			path: "tiles.json",
+			data: `{
+				"tile_width": 16, "tile_height": 8, "tile_count": 6, "columns": 3,
+				"spacing": 2, "margin": 1,
+				"tiles": [
+					{"index": 1, "collision": true, "terrain": ["wall"]},
+					{"index": 4, "animation": [{"tile_index": 4, "duration": 100}, {"tile_index": 5, "duration": 50}]}
+				]
+			}`,
+		},
+		{
+			name: "tsx",
+			path: "tiles.tsx",
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+				<tileset name="t" tilewidth="16" tileheight="8" spacing="2" margin="1" tilecount="6" columns="3">
+					<image source="tiles.png" width="55" height="21"/>
+					<tile id="1">
+						<properties>
+							<property name="collision" type="bool" value="true"/>
+							<property name="terrain" value="wall"/>
+						</properties>
+					</tile>
+					<tile id="4">
+						<animation>
+							<frame tileid="4" duration="100"/>
+							<frame tileid="5" duration="50"/>
+						</animation>
+					</tile>
+				</tileset>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ts, err := decodeTileSet(strings.NewReader(test.data), test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(ts.Tiles) != 6 {
+				t.Fatalf("have %d tiles, want 6", len(ts.Tiles))
+			}
+			if !ts.Tiles[1].Collision || !ts.Tiles[1].HasTerrain("wall") {
+				t.Fatalf("tile 1 properties are not decoded: %+v", ts.Tiles[1])
+			}
+			if ts.Tiles[0].Collision {
+				t.Fatal("tile 0 should not have a collision")
+			}
+			wantFrames := []TileFrame{
+				{TileIndex: 4, Duration: 100 * time.Millisecond},
+				{TileIndex: 5, Duration: 50 * time.Millisecond},
+			}
+			if len(ts.Tiles[4].Animation) != len(wantFrames) {
+				t.Fatalf("tile 4 animation: have %v, want %v", ts.Tiles[4].Animation, wantFrames)
+			}
+			for i, f := range wantFrames {
+				if ts.Tiles[4].Animation[i] != f {
+					t.Fatalf("tile 4 animation: have %v, want %v", ts.Tiles[4].Animation, wantFrames)
+				}
+			}
+
+			rects := []image.Rectangle{
+				image.Rect(1, 1, 17, 9),
+				image.Rect(19, 1, 35, 9),
+				image.Rect(37, 1, 53, 9),
+				image.Rect(1, 11, 17, 19),
+				image.Rect(19, 11, 35, 19),
+				image.Rect(37, 11, 53, 19),
+			}
+			for i, want := range rects {
+				if have := ts.TileRect(i); have != want {
+					t.Errorf("tile %d rect: have %v, want %v", i, have, want)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeTileSetErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"zero size", `{"tile_width": 0, "tile_height": 8, "columns": 1}`, "invalid tile size 0x8"},
+		{"zero columns", `{"tile_width": 8, "tile_height": 8}`, "invalid columns number 0"},
+		{"negative spacing", `{"tile_width": 8, "tile_height": 8, "columns": 1, "spacing": -1}`, "invalid spacing -1 or margin 0"},
+		{"bad index", `{"tile_width": 8, "tile_height": 8, "columns": 1, "tile_count": 1, "tiles": [{"index": 1}]}`, "tile index 1 is out of range"},
+		{"bad frame", `{"tile_width": 8, "tile_height": 8, "columns": 1, "tile_count": 1, "tiles": [{"index": 0, "animation": [{"tile_index": 3}]}]}`, "tile 0: animation frame tile index 3 is out of range"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := decodeTileSet(strings.NewReader(test.data), "tiles.json")
+			if err == nil || err.Error() != test.want {
+				t.Fatalf("error mismatch:\nhave: %v\nwant: %s", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package resource
+
+// Tracer wraps the resource loading phases into the tracing spans.
+// Assign it to the Loader.Tracer field to enable the tracing.
+//
+// The span names are "resource.open", "resource.decode" and "resource.upload"
+// (see Profiler for the phases description).
+//
+// The package doesn't depend on any tracing library,
+// an OpenTelemetry adapter could look like this:
+//
+//	type otelTracer struct {
+//		ctx    context.Context
+//		tracer trace.Tracer
+//	}
+//
+//	func (t *otelTracer) StartSpan(name string, ref resource.Ref, path string) func() {
+//		_, span := t.tracer.Start(t.ctx, name, trace.WithAttributes(
+//			attribute.String("resource.kind", ref.Kind.String()),
+//			attribute.Int("resource.id", ref.ID),
+//			attribute.String("resource.path", path),
+//		))
+//		return func() { span.End() }
+//	}
+type Tracer interface {
+	// StartSpan starts a new span for the resource loading phase.
+	// The returned function ends the span.
+	// The path can be empty for the phases that don't read the data.
+	StartSpan(name string, ref Ref, path string) (end func())
+}
+
+func (stage profileStage) spanName() string {
+	switch stage {
+	case stageOpen:
+		return "resource.open"
+	case stageDecode:
+		return "resource.decode"
+	default:
+		return "resource.upload"
+	}
+}
@@ -0,0 +1,164 @@
+package resource
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// UsageTracker records which resources were loaded during a session.
+// Assign it to the Loader.UsageTracker field to enable the tracking.
+//
+// Play through the game (or run the automated playthrough) and then
+// use Unused to find the registered resources that were never loaded.
+// These are the dead assets candidates that can be pruned from the
+// shipped bundle.
+//
+// The tracker also records the order of the first loads,
+// see Trace and Loader.PreloadFromTrace.
+type UsageTracker struct {
+	used  map[Ref]struct{}
+	order []UsageEntry
+}
+
+// UsageEntry describes a tracked resource.
+type UsageEntry struct {
+	Kind Kind   `json:"kind"`
+	ID   int    `json:"id"`
+	Path string `json:"path,omitempty"`
+}
+
+// NewUsageTracker creates an empty usage tracker.
+func NewUsageTracker() *UsageTracker {
+	return &UsageTracker{used: make(map[Ref]struct{})}
+}
+
+// Reset forgets all recorded resources.
+func (t *UsageTracker) Reset() {
+	t.used = make(map[Ref]struct{})
+	t.order = nil
+}
+
+// IsUsed reports whether the resource was loaded at least once.
+func (t *UsageTracker) IsUsed(ref Ref) bool {
+	_, ok := t.used[ref]
+	return ok
+}
+
+// Trace returns the resources in the order they were loaded for the first time.
+func (t *UsageTracker) Trace() LoadTrace {
+	return LoadTrace{Entries: append([]UsageEntry(nil), t.order...)}
+}
+
+// Unused returns the resources that are registered in l,
+// but were never loaded, sorted by their kind and ID.
+// Aliases are not reported: only the resources they point to are tracked.
+func (t *UsageTracker) Unused(l *Loader) []UsageEntry {
+	var entries []UsageEntry
+	for _, kind := range l.allKinds() {
+		r := l.kindRegistry(kind)
+		ids := r.ids()
+		sort.Ints(ids)
+		for _, id := range ids {
+			if t.IsUsed(Ref{Kind: kind, ID: id}) {
+				continue
+			}
+			entries = append(entries, UsageEntry{Kind: kind, ID: id, Path: r.infoPath(id)})
+		}
+	}
+	return entries
+}
+
+// WriteUnusedJSON writes the Unused result in the JSON format to w.
+func (t *UsageTracker) WriteUnusedJSON(w io.Writer, l *Loader) error {
+	entries := t.Unused(l)
+	if entries == nil {
+		entries = []UsageEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// trackUsage marks the resource as used.
+// It's a no-op if the tracking is disabled.
+func (l *Loader) trackUsage(ref Ref) {
+	t := l.UsageTracker
+	if t == nil {
+		return
+	}
+	if _, ok := t.used[ref]; !ok {
+		t.used[ref] = struct{}{}
+		e := UsageEntry{Kind: ref.Kind, ID: ref.ID}
+		if r := l.kindRegistry(ref.Kind); r != nil {
+			e.Path = r.infoPath(ref.ID)
+		}
+		t.order = append(t.order, e)
+	}
+}
+
+// LoadTrace is a recorded resource loading order.
+// See UsageTracker.Trace and Loader.PreloadFromTrace.
+//
+// The resources are recorded along with their paths:
+// the IDs are not stable between the game builds (they're usually
+// generated iota constants), so the paths are used to find the resources.
+type LoadTrace struct {
+	Entries []UsageEntry
+}
+
+// WriteJSON writes the trace in the JSON format to w.
+// Use ReadLoadTrace to decode it.
+func (t LoadTrace) WriteJSON(w io.Writer) error {
+	entries := t.Entries
+	if entries == nil {
+		entries = []UsageEntry{}
+	}
+	return json.NewEncoder(w).Encode(entries)
+}
+
+// ReadLoadTrace decodes the trace that was written by LoadTrace.WriteJSON.
+func ReadLoadTrace(r io.Reader) (LoadTrace, error) {
+	var t LoadTrace
+	err := json.NewDecoder(r).Decode(&t.Entries)
+	return t, err
+}
+
+// traceRefs maps the trace entries to the currently registered resources.
+// The entries with a path are matched by their kind and path;
+// the entries without a path are matched by their ID,
+// but only if the registered resource doesn't have a path either.
+// The entries that don't match anything are skipped.
+func (l *Loader) traceRefs(trace LoadTrace) []Ref {
+	byPath := make(map[Kind]map[string]int)
+	refs := make([]Ref, 0, len(trace.Entries))
+	for _, e := range trace.Entries {
+		r := l.kindRegistry(e.Kind)
+		if r == nil {
+			continue
+		}
+		if e.Path == "" {
+			if r.has(e.ID) && r.infoPath(e.ID) == "" {
+				refs = append(refs, Ref{Kind: e.Kind, ID: e.ID})
+			}
+			continue
+		}
+		paths, ok := byPath[e.Kind]
+		if !ok {
+			paths = make(map[string]int)
+			ids := r.ids()
+			sort.Ints(ids)
+			for _, id := range ids {
+				path := r.infoPath(id)
+				if _, ok := paths[path]; !ok && path != "" {
+					paths[path] = id
+				}
+			}
+			byPath[e.Kind] = paths
+		}
+		if id, ok := paths[e.Path]; ok {
+			refs = append(refs, Ref{Kind: e.Kind, ID: id})
+		}
+	}
+	return refs
+}
@@ -0,0 +1,69 @@
+package resource
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLoadTraceRenumbered(t *testing.T) {
+	open := func(path string) io.ReadCloser {
+		return io.NopCloser(strings.NewReader(path))
+	}
+
+	recorder := NewLoader(nil)
+	recorder.OpenAssetFunc = open
+	recorder.UsageTracker = NewUsageTracker()
+	recorder.RawRegistry.Assign(map[RawID]RawInfo{
+		1: {Path: "a.txt"},
+		2: {Path: "b.txt"},
+		3: {Path: "removed.txt"},
+		4: {Data: []byte("generated")},
+	})
+	recorder.LoadRaw(2)
+	recorder.LoadRaw(3)
+	recorder.LoadRaw(1)
+	recorder.LoadRaw(4)
+
+	var buf bytes.Buffer
+	if err := recorder.UsageTracker.Trace().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	trace, err := ReadLoadTrace(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new build: the IDs are different, one of the files is removed.
+	l := NewLoader(nil)
+	l.OpenAssetFunc = open
+	l.UsageTracker = NewUsageTracker()
+	l.RawRegistry.Assign(map[RawID]RawInfo{
+		1: {Path: "b.txt"},
+		2: {Path: "c.txt"},
+		3: {Path: "a.txt"},
+		4: {Data: []byte("generated")},
+	})
+	if err := l.PreloadFromTrace(trace); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []UsageEntry{
+		{Kind: KindRaw, ID: 1, Path: "b.txt"},
+		{Kind: KindRaw, ID: 3, Path: "a.txt"},
+		{Kind: KindRaw, ID: 4},
+	}
+	have := l.UsageTracker.Trace().Entries
+	if len(have) != len(want) {
+		t.Fatalf("preloaded entries mismatch:\nhave: %v\nwant: %v", have, want)
+	}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Fatalf("preloaded entries mismatch:\nhave: %v\nwant: %v", have, want)
+		}
+	}
+	if l.IsLoaded(RawID(2).Ref()) {
+		t.Fatal("a resource that is not in the trace is loaded")
+	}
+}
@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"errors"
+	"fmt"
+)
+
+// PickIndex returns the index of the item selected by the seed.
+// The same (set, seed) pair always selects the same item.
+func (s VariantSet) PickIndex(seed uint64) int {
+	// The set ID is mixed in, so the sets that share
+	// the seed (like the tile coordinates) vary independently.
+	h := splitmix64(seed ^ splitmix64(uint64(s.ID)))
+	if len(s.weights) == 0 {
+		return int(h % uint64(len(s.Items)))
+	}
+	x := float64(h>>11) / (1 << 53) * s.weights[len(s.weights)-1]
+	for i, w := range s.weights {
+		if x < w {
+			return i
+		}
+	}
+	return len(s.Items) - 1
+}
+
+// Pick returns the item selected by the seed.
+// The same (set, seed) pair always selects the same item.
+func (s VariantSet) Pick(seed uint64) Ref {
+	return s.Items[s.PickIndex(seed)]
+}
+
+// TileSeed combines the map seed and the tile coordinates into
+// a seed that can be used with VariantSet.Pick.
+func TileSeed(seed uint64, x, y int) uint64 {
+	return splitmix64(seed ^ splitmix64(uint64(uint32(x))<<32|uint64(uint32(y))))
+}
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+func newVariantSet(id VariantSetID, info VariantSetInfo) VariantSet {
+	if len(info.Items) == 0 {
+		panic(newError("validate", id.Ref(), "", errors.New("no items")))
+	}
+	set := VariantSet{ID: id, Items: info.Items}
+	if len(info.Weights) == 0 {
+		return set
+	}
+	if len(info.Weights) != len(info.Items) {
+		panic(newError("validate", id.Ref(), "", fmt.Errorf("%d items and %d weights", len(info.Items), len(info.Weights))))
+	}
+	// The weights are stored as the cumulative sums.
+	set.weights = make([]float64, len(info.Weights))
+	total := 0.0
+	for i, w := range info.Weights {
+		if w < 0 {
+			panic(newError("validate", id.Ref(), "", fmt.Errorf("negative weight %v", w)))
+		}
+		total += w
+		set.weights[i] = total
+	}
+	if total == 0 {
+		panic(newError("validate", id.Ref(), "", errors.New("zero total weight")))
+	}
+	return set
+}
@@ -0,0 +1,249 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+)
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xfffe
+)
+
+type wavFormat struct {
+	tag           int
+	channels      int
+	sampleRate    int
+	bitsPerSample int
+
+	// extensible is set for the WAVE_FORMAT_EXTENSIBLE header,
+	// the tag is taken from its sub-format then.
+	extensible bool
+}
+
+// nativelySupported reports whether Ebitengine WAV decoder
+// can handle this format without a conversion.
+func (f wavFormat) nativelySupported() bool {
+	// Ebitengine decoder only accepts the raw PCM tag,
+	// so the extensible headers are always converted.
+	return f.tag == wavFormatPCM && !f.extensible &&
+		(f.channels == 1 || f.channels == 2) &&
+		(f.bitsPerSample == 8 || f.bitsPerSample == 16)
+}
+
+// decodeWAV is like wav.DecodeWithoutResampling, but it also accepts
+// the WAV files Ebitengine can't decode directly:
+// 24 and 32-bit PCM, 32 and 64-bit float samples, more than 2 channels
+// and the WAVE_FORMAT_EXTENSIBLE header.
+//
+// Such files are converted into 16-bit stereo PCM in memory.
+// Only the first two channels are used for the multichannel files.
+func decodeWAV(r io.Reader) (*wav.Stream, error) {
+	seeker, ok := r.(io.Seeker)
+	if !ok {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		rs := bytes.NewReader(data)
+		r = rs
+		seeker = rs
+	}
+	f, err := readWAVFormat(r)
+	if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, seekErr
+	}
+	if err != nil || f.nativelySupported() {
+		// Let the Ebitengine decoder report the header errors.
+		return wav.DecodeWithoutResampling(r)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := convertWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	return wav.DecodeWithoutResampling(bytes.NewReader(converted))
+}
+
+// walkWAVChunks calls visit for every RIFF chunk until it returns false.
+// The chunk body reader is valid only during the visit call.
+func walkWAVChunks(r io.Reader, visit func(id string, size int64, body io.Reader) (bool, error)) error {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return errors.New("invalid header")
+	}
+	if string(header[0:4]) != "RIFF" || string(header[8:12]) != "WAVE" {
+		return errors.New("invalid header: not a RIFF WAVE file")
+	}
+	for {
+		var chunk [8]byte
+		if _, err := io.ReadFull(r, chunk[:]); err != nil {
+			return errors.New("invalid header: chunk is truncated")
+		}
+		size := int64(binary.LittleEndian.Uint32(chunk[4:]))
+		body := &io.LimitedReader{R: r, N: size}
+		more, err := visit(string(chunk[0:4]), size, body)
+		if err != nil || !more {
+			return err
+		}
+		// Chunks are aligned to 2 bytes.
+		if _, err := io.Copy(io.Discard, io.LimitReader(r, body.N+size%2)); err != nil {
+			return err
+		}
+	}
+}
+
+func readWAVFormat(r io.Reader) (wavFormat, error) {
+	var f wavFormat
+	found := false
+	err := walkWAVChunks(r, func(id string, size int64, body io.Reader) (bool, error) {
+		if id != "fmt " {
+			return true, nil
+		}
+		if size < 16 {
+			return false, errors.New("invalid fmt chunk")
+		}
+		buf := make([]byte, size)
+		if _, err := io.ReadFull(body, buf); err != nil {
+			return false, err
+		}
+		f = parseWAVFormat(buf)
+		found = true
+		return false, nil
+	})
+	if err == nil && !found {
+		err = errors.New("fmt chunk not found")
+	}
+	return f, err
+}
+
+func parseWAVFormat(buf []byte) wavFormat {
+	f := wavFormat{
+		tag:           int(binary.LittleEndian.Uint16(buf[0:])),
+		channels:      int(binary.LittleEndian.Uint16(buf[2:])),
+		sampleRate:    int(binary.LittleEndian.Uint32(buf[4:])),
+		bitsPerSample: int(binary.LittleEndian.Uint16(buf[14:])),
+	}
+	// For the extensible format, the actual format tag is stored
+	// as the first 2 bytes of the sub-format GUID.
+	if f.tag == wavFormatExtensible && len(buf) >= 26 {
+		f.tag = int(binary.LittleEndian.Uint16(buf[24:]))
+		f.extensible = true
+	}
+	return f
+}
+
+// convertWAV converts a WAV file data into 16-bit stereo PCM WAV.
+func convertWAV(data []byte) ([]byte, error) {
+	var f wavFormat
+	var samples []byte
+	err := walkWAVChunks(bytes.NewReader(data), func(id string, size int64, body io.Reader) (bool, error) {
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return false, errors.New("invalid fmt chunk")
+			}
+			buf := make([]byte, size)
+			if _, err := io.ReadFull(body, buf); err != nil {
+				return false, err
+			}
+			f = parseWAVFormat(buf)
+			return true, nil
+		case "data":
+			// Some encoders write an invalid data size for the streamed files,
+			// io.ReadAll reads what's actually available.
+			var err error
+			samples, err = io.ReadAll(body)
+			return false, err
+		default:
+			return true, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if f.channels == 0 {
+		return nil, errors.New("fmt chunk not found")
+	}
+
+	readSample, err := wavSampleReader(f)
+	if err != nil {
+		return nil, err
+	}
+	sampleSize := f.bitsPerSample / 8
+	frameSize := sampleSize * f.channels
+	numFrames := len(samples) / frameSize
+
+	out := make([]byte, 44+numFrames*4)
+	copy(out[0:], "RIFF")
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	copy(out[8:], "WAVEfmt ")
+	binary.LittleEndian.PutUint32(out[16:], 16)
+	binary.LittleEndian.PutUint16(out[20:], wavFormatPCM)
+	binary.LittleEndian.PutUint16(out[22:], 2)
+	binary.LittleEndian.PutUint32(out[24:], uint32(f.sampleRate))
+	binary.LittleEndian.PutUint32(out[28:], uint32(f.sampleRate*4))
+	binary.LittleEndian.PutUint16(out[32:], 4)
+	binary.LittleEndian.PutUint16(out[34:], 16)
+	copy(out[36:], "data")
+	binary.LittleEndian.PutUint32(out[40:], uint32(numFrames*4))
+
+	dst := out[44:]
+	for i := 0; i < numFrames; i++ {
+		frame := samples[i*frameSize:]
+		left := readSample(frame)
+		right := left
+		if f.channels > 1 {
+			right = readSample(frame[sampleSize:])
+		}
+		binary.LittleEndian.PutUint16(dst[i*4:], uint16(left))
+		binary.LittleEndian.PutUint16(dst[i*4+2:], uint16(right))
+	}
+	return out, nil
+}
+
+func wavSampleReader(f wavFormat) (func(b []byte) int16, error) {
+	switch {
+	case f.channels <= 0:
+		return nil, fmt.Errorf("invalid number of channels: %d", f.channels)
+	case f.tag == wavFormatPCM && f.bitsPerSample == 8:
+		return func(b []byte) int16 { return int16(int(b[0])-128) << 8 }, nil
+	case f.tag == wavFormatPCM && f.bitsPerSample == 16:
+		return func(b []byte) int16 { return int16(binary.LittleEndian.Uint16(b)) }, nil
+	case f.tag == wavFormatPCM && f.bitsPerSample == 24:
+		return func(b []byte) int16 { return int16(uint16(b[1]) | uint16(b[2])<<8) }, nil
+	case f.tag == wavFormatPCM && f.bitsPerSample == 32:
+		return func(b []byte) int16 { return int16(binary.LittleEndian.Uint32(b) >> 16) }, nil
+	case f.tag == wavFormatFloat && f.bitsPerSample == 32:
+		return func(b []byte) int16 {
+			return floatToPCM16(float64(math.Float32frombits(binary.LittleEndian.Uint32(b))))
+		}, nil
+	case f.tag == wavFormatFloat && f.bitsPerSample == 64:
+		return func(b []byte) int16 {
+			return floatToPCM16(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %d with %d bits per sample", f.tag, f.bitsPerSample)
+	}
+}
+
+func floatToPCM16(v float64) int16 {
+	switch {
+	case v >= 1:
+		return math.MaxInt16
+	case v <= -1:
+		return -math.MaxInt16
+	default:
+		return int16(v * math.MaxInt16)
+	}
+}
@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+)
+
+// makeTestWAV creates a WAV file with the given format and samples.
+// The samples are encoded as little-endian values of bitsPerSample size.
+func makeTestWAV(tag, channels, bitsPerSample int, extensible bool, samples []float64) []byte {
+	var fmtChunk bytes.Buffer
+	headerTag := tag
+	if extensible {
+		headerTag = wavFormatExtensible
+	}
+	blockAlign := channels * bitsPerSample / 8
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(headerTag))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(channels))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint32(44100*blockAlign))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(&fmtChunk, binary.LittleEndian, uint16(bitsPerSample))
+	if extensible {
+		binary.Write(&fmtChunk, binary.LittleEndian, uint16(22))            // cbSize
+		binary.Write(&fmtChunk, binary.LittleEndian, uint16(bitsPerSample)) // valid bits
+		binary.Write(&fmtChunk, binary.LittleEndian, uint32(3))             // channel mask
+		// The sub-format GUID: the tag followed by the fixed suffix.
+		binary.Write(&fmtChunk, binary.LittleEndian, uint16(tag))
+		fmtChunk.Write([]byte{0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x80, 0x00, 0x00, 0xaa, 0x00, 0x38, 0x9b, 0x71})
+	}
+
+	var data bytes.Buffer
+	for _, v := range samples {
+		switch {
+		case tag == wavFormatFloat && bitsPerSample == 32:
+			binary.Write(&data, binary.LittleEndian, math.Float32bits(float32(v)))
+		case tag == wavFormatFloat && bitsPerSample == 64:
+			binary.Write(&data, binary.LittleEndian, math.Float64bits(v))
+		case bitsPerSample == 8:
+			data.WriteByte(byte(int(v*127) + 128))
+		case bitsPerSample == 16:
+			binary.Write(&data, binary.LittleEndian, int16(v*math.MaxInt16))
+		case bitsPerSample == 24:
+			x := int32(v * (1<<23 - 1))
+			data.Write([]byte{byte(x), byte(x >> 8), byte(x >> 16)})
+		case bitsPerSample == 32:
+			binary.Write(&data, binary.LittleEndian, int32(v*math.MaxInt32))
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("RIFF")
+	binary.Write(&out, binary.LittleEndian, uint32(4+8+fmtChunk.Len()+8+data.Len()))
+	out.WriteString("WAVE")
+	out.WriteString("fmt ")
+	binary.Write(&out, binary.LittleEndian, uint32(fmtChunk.Len()))
+	out.Write(fmtChunk.Bytes())
+	out.WriteString("data")
+	binary.Write(&out, binary.LittleEndian, uint32(data.Len()))
+	out.Write(data.Bytes())
+	return out.Bytes()
+}
+
+func TestDecodeWAV(t *testing.T) {
+	tests := []struct {
+		name          string
+		tag           int
+		channels      int
+		bitsPerSample int
+		extensible    bool
+	}{
+		{"pcm8 mono", wavFormatPCM, 1, 8, false},
+		{"pcm16 stereo", wavFormatPCM, 2, 16, false},
+		{"pcm24 stereo", wavFormatPCM, 2, 24, false},
+		{"pcm32 mono", wavFormatPCM, 1, 32, false},
+		{"float32 stereo", wavFormatFloat, 2, 32, false},
+		{"float64 mono", wavFormatFloat, 1, 64, false},
+		{"pcm16 5.1", wavFormatPCM, 6, 16, false},
+		{"extensible pcm8 mono", wavFormatPCM, 1, 8, true},
+		{"extensible pcm16 mono", wavFormatPCM, 1, 16, true},
+		{"extensible pcm16 stereo", wavFormatPCM, 2, 16, true},
+		{"extensible pcm24 stereo", wavFormatPCM, 2, 24, true},
+		{"extensible float32 stereo", wavFormatFloat, 2, 32, true},
+	}
+
+	// Every frame has the same value in all channels,
+	// so the expected output is the same for the mono and multichannel files.
+	values := []float64{0, 0.5, -0.5, 0.25, -0.25}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var samples []float64
+			for _, v := range values {
+				for ch := 0; ch < test.channels; ch++ {
+					samples = append(samples, v)
+				}
+			}
+			data := makeTestWAV(test.tag, test.channels, test.bitsPerSample, test.extensible, samples)
+			stream, err := decodeWAV(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			pcm, err := io.ReadAll(stream)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			// The decoded stream is always 16-bit stereo.
+			if len(pcm) != len(values)*4 {
+				t.Fatalf("decoded %d bytes, want %d", len(pcm), len(values)*4)
+			}
+			// The 8-bit samples are the least precise.
+			const tolerance = 1.0 / 64
+			for i, want := range values {
+				for ch := 0; ch < 2; ch++ {
+					sample := int16(binary.LittleEndian.Uint16(pcm[i*4+ch*2:]))
+					have := float64(sample) / math.MaxInt16
+					if math.Abs(have-want) > tolerance {
+						t.Fatalf("frame %d channel %d: have %.4f, want %.4f", i, ch, have, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeWAVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"empty", nil},
+		{"not riff", []byte("RIFX\x00\x00\x00\x00WAVE")},
+		{"pcm12", makeTestWAV(wavFormatPCM, 1, 12, true, nil)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := decodeWAV(bytes.NewReader(test.data)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}